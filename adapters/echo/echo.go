@@ -0,0 +1,25 @@
+// Package cryptomusecho adapts the Cryptomus webhook handlers to the Echo framework.
+package cryptomusecho
+
+import (
+	"net/http"
+
+	"github.com/backtrac3r/go-cryptomus"
+	"github.com/labstack/echo/v4"
+)
+
+// Handler exposes a verified-webhook handler, such as *cryptomus.Dispatcher or
+// *cryptomus.WebhookStream, as an echo.HandlerFunc.
+func Handler(h http.Handler) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.ServeHTTP(c.Response(), c.Request())
+		return nil
+	}
+}
+
+// Dispatcher creates a new dispatcher for the client and exposes it as an echo.HandlerFunc.
+// Handlers registered on the returned dispatcher are served by the returned handler.
+func Dispatcher(client *cryptomus.Cryptomus, opts ...cryptomus.DispatcherOption) (*cryptomus.Dispatcher, echo.HandlerFunc) {
+	dispatcher := cryptomus.NewDispatcher(client, opts...)
+	return dispatcher, Handler(dispatcher)
+}
@@ -0,0 +1,56 @@
+package cryptomusecho_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/backtrac3r/go-cryptomus"
+	cryptomusecho "github.com/backtrac3r/go-cryptomus/adapters/echo"
+	"github.com/backtrac3r/go-cryptomus/cryptomustest"
+	"github.com/labstack/echo/v4"
+)
+
+func TestDispatcher(t *testing.T) {
+	dispatcher, handler := cryptomusecho.Dispatcher(cryptomustest.NewClient(nil))
+	var paid []cryptomus.UUID
+	dispatcher.OnPaymentPaid(func(ctx context.Context, webhook *cryptomus.PaymentWebhook) error {
+		paid = append(paid, webhook.UUID)
+		return nil
+	})
+
+	e := echo.New()
+	e.POST("/cryptomus/callback", handler)
+	server := httptest.NewServer(e)
+	t.Cleanup(server.Close)
+
+	post := func(body []byte) int {
+		t.Helper()
+
+		res, err := http.Post(server.URL+"/cryptomus/callback", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+
+		return res.StatusCode
+	}
+
+	fixture := cryptomustest.Webhook(t, "payment/paid")
+	if status := post(fixture.Body); status != http.StatusOK {
+		t.Fatalf("signed webhook: got status %d, want %d", status, http.StatusOK)
+	}
+	if want := fixture.Payload.(*cryptomus.PaymentWebhook).UUID; len(paid) != 1 || paid[0] != want {
+		t.Fatalf("got paid webhooks %v, want [%s]", paid, want)
+	}
+
+	forged := cryptomustest.SignWebhook(t, cryptomustest.PayoutKey, fixture.Payload)
+	if status := post(forged); status != http.StatusBadRequest {
+		t.Fatalf("forged webhook: got status %d, want %d", status, http.StatusBadRequest)
+	}
+	if len(paid) != 1 {
+		t.Fatalf("forged webhook was dispatched")
+	}
+}
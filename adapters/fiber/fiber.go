@@ -0,0 +1,25 @@
+// Package cryptomusfiber adapts the Cryptomus webhook handlers to the Fiber framework.
+package cryptomusfiber
+
+import (
+	"net/http"
+
+	"github.com/backtrac3r/go-cryptomus"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+)
+
+// Handler exposes a verified-webhook handler, such as *cryptomus.Dispatcher or
+// *cryptomus.WebhookStream, as a fiber.Handler.
+// The request is converted to net/http semantics, including the remote address
+// used by cryptomus.IPAllowlist.
+func Handler(h http.Handler) fiber.Handler {
+	return adaptor.HTTPHandler(h)
+}
+
+// Dispatcher creates a new dispatcher for the client and exposes it as a fiber.Handler.
+// Handlers registered on the returned dispatcher are served by the returned handler.
+func Dispatcher(client *cryptomus.Cryptomus, opts ...cryptomus.DispatcherOption) (*cryptomus.Dispatcher, fiber.Handler) {
+	dispatcher := cryptomus.NewDispatcher(client, opts...)
+	return dispatcher, Handler(dispatcher)
+}
@@ -0,0 +1,56 @@
+package cryptomusfiber_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/backtrac3r/go-cryptomus"
+	cryptomusfiber "github.com/backtrac3r/go-cryptomus/adapters/fiber"
+	"github.com/backtrac3r/go-cryptomus/cryptomustest"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestDispatcher(t *testing.T) {
+	dispatcher, handler := cryptomusfiber.Dispatcher(cryptomustest.NewClient(nil))
+	var paid []cryptomus.UUID
+	dispatcher.OnPaymentPaid(func(ctx context.Context, webhook *cryptomus.PaymentWebhook) error {
+		paid = append(paid, webhook.UUID)
+		return nil
+	})
+
+	app := fiber.New()
+	app.Post("/cryptomus/callback", handler)
+
+	post := func(body []byte) int {
+		t.Helper()
+
+		req := httptest.NewRequest(http.MethodPost, "/cryptomus/callback", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		res, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+
+		return res.StatusCode
+	}
+
+	fixture := cryptomustest.Webhook(t, "payment/paid")
+	if status := post(fixture.Body); status != http.StatusOK {
+		t.Fatalf("signed webhook: got status %d, want %d", status, http.StatusOK)
+	}
+	if want := fixture.Payload.(*cryptomus.PaymentWebhook).UUID; len(paid) != 1 || paid[0] != want {
+		t.Fatalf("got paid webhooks %v, want [%s]", paid, want)
+	}
+
+	forged := cryptomustest.SignWebhook(t, cryptomustest.PayoutKey, fixture.Payload)
+	if status := post(forged); status != http.StatusBadRequest {
+		t.Fatalf("forged webhook: got status %d, want %d", status, http.StatusBadRequest)
+	}
+	if len(paid) != 1 {
+		t.Fatalf("forged webhook was dispatched")
+	}
+}
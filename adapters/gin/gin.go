@@ -0,0 +1,24 @@
+// Package cryptomusgin adapts the Cryptomus webhook handlers to the Gin framework.
+package cryptomusgin
+
+import (
+	"net/http"
+
+	"github.com/backtrac3r/go-cryptomus"
+	"github.com/gin-gonic/gin"
+)
+
+// Handler exposes a verified-webhook handler, such as *cryptomus.Dispatcher or
+// *cryptomus.WebhookStream, as a gin.HandlerFunc.
+func Handler(h http.Handler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		h.ServeHTTP(c.Writer, c.Request)
+	}
+}
+
+// Dispatcher creates a new dispatcher for the client and exposes it as a gin.HandlerFunc.
+// Handlers registered on the returned dispatcher are served by the returned handler.
+func Dispatcher(client *cryptomus.Cryptomus, opts ...cryptomus.DispatcherOption) (*cryptomus.Dispatcher, gin.HandlerFunc) {
+	dispatcher := cryptomus.NewDispatcher(client, opts...)
+	return dispatcher, Handler(dispatcher)
+}
@@ -0,0 +1,94 @@
+package cryptomus
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+)
+
+// MaxAdditionalDataLength is the maximum number of characters of the additional data accepted by the API.
+const MaxAdditionalDataLength = 255
+
+// AdditionalData is the merchant metadata attached to payments, static wallet deposits and recurring payments,
+// which Cryptomus stores as a string and returns in the responses and webhooks.
+//
+// It holds either plain text, e.g. AdditionalData("order 5"), or JSON built with NewAdditionalData,
+// and is sent as a JSON string either way. Nil data is omitted from requests and decoded from null.
+type AdditionalData json.RawMessage
+
+// NewAdditionalData encodes the value as JSON metadata, failing if it is longer than the API accepts.
+func NewAdditionalData(v interface{}) (AdditionalData, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal additional data: %w", err)
+	}
+
+	d := AdditionalData(data)
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// Validate checks that the data is no longer than MaxAdditionalDataLength characters.
+func (d AdditionalData) Validate() error {
+	if n := utf8.RuneCount(d); n > MaxAdditionalDataLength {
+		return fmt.Errorf("additional data is %d characters long, at most %d are allowed", n, MaxAdditionalDataLength)
+	}
+
+	return nil
+}
+
+// Decode unmarshals the JSON metadata into v.
+func (d AdditionalData) Decode(v interface{}) error {
+	if len(d) == 0 {
+		return fmt.Errorf("additional data is empty")
+	}
+	if err := json.Unmarshal(d, v); err != nil {
+		return fmt.Errorf("failed to unmarshal additional data: %w", err)
+	}
+
+	return nil
+}
+
+// IsJSON reports whether the data is valid JSON rather than plain text.
+func (d AdditionalData) IsJSON() bool {
+	return len(d) > 0 && json.Valid(d)
+}
+
+// String returns the data as text.
+func (d AdditionalData) String() string {
+	return string(d)
+}
+
+// MarshalJSON encodes the data as a JSON string, or as null if it is nil.
+func (d AdditionalData) MarshalJSON() ([]byte, error) {
+	if d == nil {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(string(d))
+}
+
+// UnmarshalJSON decodes the data from a JSON string, keeping other JSON values as they are.
+// Null decodes to nil data.
+func (d *AdditionalData) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*d = nil
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return fmt.Errorf("invalid additional data %s: %w", data, err)
+		}
+		*d = AdditionalData(s)
+		return nil
+	}
+
+	*d = append((*d)[:0], data...)
+	return nil
+}
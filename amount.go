@@ -0,0 +1,60 @@
+package cryptomus
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Amount is a sum of money or a rate with arbitrary precision, backed by decimal.Decimal.
+// It is sent to the API as a JSON string, as Cryptomus expects, and decoded from strings and bare numbers;
+// a null or empty string decodes to zero. The decimal methods are available through the embedded field.
+type Amount struct {
+	decimal.Decimal
+}
+
+// NewAmount parses the amount from its decimal representation, e.g. "10.50".
+func NewAmount(s string) (Amount, error) {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return Amount{}, fmt.Errorf("invalid amount %q: %w", s, err)
+	}
+
+	return Amount{d}, nil
+}
+
+// MustAmount parses the amount like NewAmount, panicking if it is invalid.
+// It is meant for constants in code and tests.
+func MustAmount(s string) Amount {
+	amount, err := NewAmount(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return amount
+}
+
+// AmountFromDecimal wraps the decimal into an Amount.
+func AmountFromDecimal(d decimal.Decimal) Amount {
+	return Amount{d}
+}
+
+// MarshalJSON encodes the amount as a JSON string, e.g. "10.5".
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + a.Decimal.String() + `"`), nil
+}
+
+// UnmarshalJSON decodes the amount from a JSON string or number. A null or empty string decodes to zero.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) || bytes.Equal(data, []byte(`""`)) {
+		a.Decimal = decimal.Zero
+		return nil
+	}
+
+	if err := a.Decimal.UnmarshalJSON(data); err != nil {
+		return fmt.Errorf("invalid amount %s: %w", data, err)
+	}
+
+	return nil
+}
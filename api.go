@@ -0,0 +1,67 @@
+package cryptomus
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate moq -out cryptomusmock/mocks.go -pkg cryptomusmock . PaymentsAPI PayoutsAPI RecurrenceAPI RatesAPI CryptomusAPI
+
+// PaymentsAPI covers the invoices, static wallets and refunds, which are authenticated with the payment key.
+// Application code can depend on it rather than on *Cryptomus, and be tested with the mocks of the
+// cryptomusmock package or the in-memory fake of the cryptomusfake package.
+type PaymentsAPI interface {
+	CreateInvoice(invoiceReq *InvoiceRequest) (*Payment, error)
+	GeneratePaymentQRCode(paymentUUID UUID) (string, error)
+	GetPaymentInfo(paymentInfoReq *PaymentInfoRequest) (*Payment, error)
+	GetPaymentInfos(paymentInfoReqs []*PaymentInfoRequest) ([]*Payment, error)
+	GetPaymentHistory(dateFrom time.Time, dateTo time.Time) (*PaymentHistoryResponse, error)
+	PaymentHistory(ctx context.Context, opts *HistoryOptions) *Iterator[*Payment]
+	ListAllPayments(ctx context.Context, historyOpts *HistoryOptions, opts *ListAllOptions) ([]*Payment, error)
+	GetPaymentServicesList() ([]*PaymentService, error)
+	CreateStaticWallet(staticWalletReq *StaticWalletRequest) (*StaticWalletResponse, error)
+	CreateStaticWallets(staticWalletReqs []*StaticWalletRequest) ([]*StaticWalletResponse, error)
+	GenerateStaticWalletQRCode(walletUUID UUID) (string, error)
+	BlockAddress(blockAddressReq *BlockAddressRequest) (*BlockAddressResponse, error)
+	Refund(refundRequest *RefundRequest) (bool, error)
+	BlockedAddressRefund(refundRequest *BlockedAddressRefundRequest) (*BlockedAddressRefundResponse, error)
+}
+
+// PayoutsAPI covers the payouts, which are authenticated with the payout key.
+type PayoutsAPI interface {
+	CreatePayout(payoutReq *PayoutRequest) (*Payout, error)
+	CreatePayouts(payoutReqs []*PayoutRequest) ([]*Payout, error)
+	GetPayoutInfo(payoutInfoReq *PayoutInfoRequest) (*Payout, error)
+	GetPayoutInfos(payoutInfoReqs []*PayoutInfoRequest) ([]*Payout, error)
+	GetPayoutHistory(dateFrom time.Time, dateTo time.Time) (*PayoutHistoryResponse, error)
+	PayoutHistory(ctx context.Context, opts *HistoryOptions) *Iterator[*Payout]
+	ListAllPayouts(ctx context.Context, historyOpts *HistoryOptions, opts *ListAllOptions) ([]*Payout, error)
+	GetPayoutServicesList() ([]*PayoutService, error)
+}
+
+// RecurrenceAPI covers the recurring payments.
+type RecurrenceAPI interface {
+	CreateRecurrence(recReq *RecurrenceRequest) (*Recurrence, error)
+	GetRecurrenceInfo(infoReq *RecurrenceInfoRequest) (*Recurrence, error)
+	ListRecurrences(opts *RecurrenceListOptions) (*RecurrenceListResponse, error)
+	Recurrences(ctx context.Context, opts *RecurrenceListOptions) *Iterator[*Recurrence]
+	ListAllRecurrences(ctx context.Context, listOpts *RecurrenceListOptions, opts *ListAllOptions) ([]*Recurrence, error)
+	CancelRecurrence(cancelReq *RecurrenceCancelRequest) (*Recurrence, error)
+	ChangeRecurrencePlan(changeReq *RecurrencePlanChangeRequest) (*RecurrencePlanChange, error)
+}
+
+// RatesAPI covers the exchange rates and conversions. It is a RateProvider.
+type RatesAPI interface {
+	ListExchangeRates(ctx context.Context, currency string, opts *ExchangeRateOptions) ([]ExchangeRate, error)
+	Convert(ctx context.Context, amount string, from string, to string) (*Conversion, error)
+}
+
+// CryptomusAPI covers the whole client. *Cryptomus implements it.
+type CryptomusAPI interface {
+	PaymentsAPI
+	PayoutsAPI
+	RecurrenceAPI
+	RatesAPI
+}
+
+var _ CryptomusAPI = (*Cryptomus)(nil)
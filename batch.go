@@ -0,0 +1,255 @@
+package cryptomus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BatchItemError is the error a single item of a batch failed with.
+type BatchItemError struct {
+	Index int // Index of the item in the batch
+	Err   error
+}
+
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("item %d: %s", e.Index, e.Err)
+}
+
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
+
+// BatchError is returned by the batch helpers when some of the items failed.
+// The results of the succeeded items are still returned alongside it, so only the failed
+// subset needs to be retried.
+type BatchError struct {
+	Total     int               // Number of items in the batch
+	Succeeded []int             // Indexes of the items that succeeded, in order
+	Errors    []*BatchItemError // Errors of the items that failed, in order
+}
+
+func (e *BatchError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, itemErr := range e.Errors {
+		messages = append(messages, itemErr.Error())
+	}
+
+	return fmt.Sprintf("cryptomus: %d of %d batch items failed: %s", len(e.Errors), e.Total, strings.Join(messages, "; "))
+}
+
+// Unwrap returns the errors of the failed items, so errors.Is and errors.As match any of them.
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, itemErr := range e.Errors {
+		errs = append(errs, itemErr)
+	}
+
+	return errs
+}
+
+// Failed returns the indexes of the items that failed, in order.
+func (e *BatchError) Failed() []int {
+	indexes := make([]int, 0, len(e.Errors))
+	for _, itemErr := range e.Errors {
+		indexes = append(indexes, itemErr.Index)
+	}
+
+	return indexes
+}
+
+// DefaultBatchRateLimitWait is the pause of a batch after a rate-limited item whose response told no wait.
+const DefaultBatchRateLimitWait = time.Second
+
+// BatchOptions configures how RunBatch and the batch methods of the client, e.g. CreatePayouts, run the items.
+type BatchOptions struct {
+	Parallelism int           // Items run at once, 1 if zero: the items run one after the other
+	ItemTimeout time.Duration // Limit on each item, including the retries of its requests; none if zero
+	// Times an item failed with a *RateLimitError is run again, once the pause it caused is over.
+	// Whether retried or not, a rate-limited item pauses the start of the next items of the batch
+	// for the wait its response asked for, so that the items running at once don't all keep hitting the limit.
+	RateLimitRetries int
+	RateLimitWait    time.Duration // Pause after a rate-limited item whose response told no wait, DefaultBatchRateLimitWait if zero
+	Clock            Clock         // Clock the pauses are timed with, SystemClock if nil
+}
+
+// WithBatchOptions sets how the batch methods of the client run their items. By default they run one after the other.
+func WithBatchOptions(opts *BatchOptions) Option {
+	return func(c *Cryptomus) {
+		c.batch = opts
+	}
+}
+
+// batchOptions returns the options of the batch methods, timed with the clock of the client unless they have their own.
+func (c *Cryptomus) batchOptions() *BatchOptions {
+	var opts BatchOptions
+	if c.batch != nil {
+		opts = *c.batch
+	}
+	if opts.Clock == nil {
+		opts.Clock = c.clock
+	}
+
+	return &opts
+}
+
+// RunBatch calls fn for every item of a batch of n according to the options, which may be nil. The results
+// are indexed like the items, with the zero value for the failed ones, which are listed by the returned
+// *BatchError, or nil if all succeeded. The items not started when ctx is done fail with its error.
+// It is the executor of the batch methods of the client, exposed to batch calls they don't cover:
+//
+//	refunds, err := cryptomus.RunBatch(ctx, len(reqs), &cryptomus.BatchOptions{Parallelism: 4},
+//		func(ctx context.Context, i int) (bool, error) {
+//			return client.Refund(reqs[i])
+//		})
+func RunBatch[T any](ctx context.Context, n int, opts *BatchOptions, fn func(ctx context.Context, i int) (T, error)) ([]T, error) {
+	b := &batchRun[T]{fn: fn}
+	if opts != nil {
+		b.opts = *opts
+	}
+	b.clock = clockOrSystem(b.opts.Clock)
+	workers := min(max(b.opts.Parallelism, 1), n)
+
+	results := make([]T, n)
+	errs := make([]error, n)
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := int(next.Add(1) - 1); i < n; i = int(next.Add(1) - 1) {
+				results[i], errs[i] = b.item(ctx, i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	batchErr := &BatchError{Total: n}
+	for i, err := range errs {
+		if err != nil {
+			batchErr.Errors = append(batchErr.Errors, &BatchItemError{Index: i, Err: err})
+			continue
+		}
+		batchErr.Succeeded = append(batchErr.Succeeded, i)
+	}
+	if len(batchErr.Errors) > 0 {
+		return results, batchErr
+	}
+
+	return results, nil
+}
+
+// batchRun is the state of a batch shared by its workers.
+type batchRun[T any] struct {
+	opts  BatchOptions
+	clock Clock
+	fn    func(ctx context.Context, i int) (T, error)
+
+	mu          sync.Mutex
+	pausedUntil time.Time // The items don't start before, after a rate-limited one
+}
+
+// item runs the item, once the batch isn't paused, running it again while it is rate-limited and retries are left.
+func (b *batchRun[T]) item(ctx context.Context, i int) (T, error) {
+	for attempt := 0; ; attempt++ {
+		if err := b.wait(ctx); err != nil {
+			var zero T
+			return zero, err
+		}
+
+		result, err := b.call(ctx, i)
+		var rateLimitErr *RateLimitError
+		if !errors.As(err, &rateLimitErr) {
+			return result, err
+		}
+		b.pause(rateLimitErr.RetryAfter)
+		if attempt >= b.opts.RateLimitRetries {
+			return result, err
+		}
+	}
+}
+
+// call runs the item within the item timeout.
+func (b *batchRun[T]) call(ctx context.Context, i int) (T, error) {
+	if b.opts.ItemTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.opts.ItemTimeout)
+		defer cancel()
+	}
+
+	return b.fn(ctx, i)
+}
+
+// wait waits until the batch isn't paused, or until ctx is done.
+func (b *batchRun[T]) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		d := b.pausedUntil.Sub(b.clock.Now())
+		b.mu.Unlock()
+		if d <= 0 {
+			return ctx.Err()
+		}
+		// The pause may be extended meanwhile by another rate-limited item
+		if err := sleepContext(ctx, b.clock, d); err != nil {
+			return err
+		}
+	}
+}
+
+// pause holds off the items of the batch for the wait asked for by a rate-limited response.
+func (b *batchRun[T]) pause(wait time.Duration) {
+	if wait <= 0 {
+		wait = b.opts.RateLimitWait
+	}
+	if wait <= 0 {
+		wait = DefaultBatchRateLimitWait
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if until := b.clock.Now().Add(wait); until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+}
+
+// CreatePayouts creates a payout for every request. The results are indexed like the requests,
+// with nil for the failed ones, which are listed by the returned *BatchError.
+// The items run as configured with WithBatchOptions.
+func (c *Cryptomus) CreatePayouts(payoutReqs []*PayoutRequest) ([]*Payout, error) {
+	return RunBatch(context.Background(), len(payoutReqs), c.batchOptions(), func(ctx context.Context, i int) (*Payout, error) {
+		return c.createPayout(ctx, payoutReqs[i])
+	})
+}
+
+// CreateStaticWallets creates a static wallet for every request. The results are indexed like
+// the requests, with nil for the failed ones, which are listed by the returned *BatchError.
+// The items run as configured with WithBatchOptions.
+func (c *Cryptomus) CreateStaticWallets(staticWalletReqs []*StaticWalletRequest) ([]*StaticWalletResponse, error) {
+	return RunBatch(context.Background(), len(staticWalletReqs), c.batchOptions(), func(ctx context.Context, i int) (*StaticWalletResponse, error) {
+		return c.createStaticWallet(ctx, staticWalletReqs[i])
+	})
+}
+
+// GetPaymentInfos looks up the payment of every request. The results are indexed like the requests,
+// with nil for the failed ones, which are listed by the returned *BatchError.
+// The items run as configured with WithBatchOptions.
+func (c *Cryptomus) GetPaymentInfos(paymentInfoReqs []*PaymentInfoRequest) ([]*Payment, error) {
+	return RunBatch(context.Background(), len(paymentInfoReqs), c.batchOptions(), func(ctx context.Context, i int) (*Payment, error) {
+		return c.getPaymentInfo(ctx, paymentInfoReqs[i])
+	})
+}
+
+// GetPayoutInfos looks up the payout of every request. The results are indexed like the requests,
+// with nil for the failed ones, which are listed by the returned *BatchError.
+// The items run as configured with WithBatchOptions.
+func (c *Cryptomus) GetPayoutInfos(payoutInfoReqs []*PayoutInfoRequest) ([]*Payout, error) {
+	return RunBatch(context.Background(), len(payoutInfoReqs), c.batchOptions(), func(ctx context.Context, i int) (*Payout, error) {
+		return c.getPayoutInfo(ctx, payoutInfoReqs[i])
+	})
+}
@@ -0,0 +1,92 @@
+package cryptomus
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+)
+
+// maxPooledBufferSize keeps the buffers grown by unusually large bodies out of bufferPool,
+// so a single huge request doesn't pin its memory.
+const maxPooledBufferSize = 64 << 10
+
+// bufferPool holds the scratch buffers request bodies are encoded in, saving the allocations
+// of growing a fresh buffer for every request.
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// getBuffer returns an empty buffer from the pool.
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+
+	return buf
+}
+
+// putBuffer returns the buffer to the pool. Its bytes must not be used afterwards.
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	bufferPool.Put(buf)
+}
+
+// MarshalCanonical encodes v as JSON the way the reference PHP implementation of Cryptomus does with
+// json_encode($data, JSON_UNESCAPED_UNICODE): slashes are escaped as \/, while non-ASCII characters and
+// the HTML characters <, > and & are written as is. Signatures computed over it match the ones computed
+// by Cryptomus for payloads containing URLs or non-ASCII names. It is used to encode request bodies.
+func MarshalCanonical(v interface{}) ([]byte, error) {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	data, err := encodeUnescaped(buf, v)
+	if err != nil {
+		return nil, err
+	}
+
+	// The body outlives the pooled buffer, so it is copied once, with the slashes escaped on the way
+	return appendEscapedSlashes(make([]byte, 0, len(data)+bytes.Count(data, []byte("/"))), data), nil
+}
+
+// marshalUnescaped encodes v as JSON like json.Marshal, but writes the HTML characters as is.
+// Custom marshalers of request types use it, so that their output stays canonical.
+func marshalUnescaped(v interface{}) ([]byte, error) {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	data, err := encodeUnescaped(buf, v)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(nil), data...), nil
+}
+
+// encodeUnescaped encodes v into the buffer like json.Marshal, but writes the HTML characters as is,
+// and returns the encoded bytes, which belong to the buffer.
+func encodeUnescaped(buf *bytes.Buffer, v interface{}) ([]byte, error) {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// appendEscapedSlashes appends the JSON encoded by encoding/json to dst with its slashes escaped.
+// Slashes only occur within strings and are never escaped by encoding/json.
+func appendEscapedSlashes(dst, data []byte) []byte {
+	for {
+		i := bytes.IndexByte(data, '/')
+		if i < 0 {
+			return append(dst, data...)
+		}
+		dst = append(dst, data[:i]...)
+		dst = append(dst, '\\', '/')
+		data = data[i+1:]
+	}
+}
@@ -0,0 +1,75 @@
+package cryptomus
+
+import "time"
+
+// Clock tells the time and schedules waits for the time-dependent parts of the SDK: the retry backoff,
+// the exchange rate cache and its refresher, the rate watcher and the timings of metrics and error hooks.
+// Tests inject a fake clock, e.g. cryptomustest.Clock, to advance time instantly instead of sleeping.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer is a single event scheduled by a Clock, as a *time.Timer.
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+}
+
+// Ticker delivers ticks at intervals scheduled by a Clock, as a *time.Ticker.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// SystemClock is the Clock of the time package, used unless another one is configured.
+var SystemClock Clock = systemClock{}
+
+// WithClock makes the client tell the time and wait for retries with the clock.
+func WithClock(clock Clock) Option {
+	return func(c *Cryptomus) {
+		if clock != nil {
+			c.clock = clock
+		}
+	}
+}
+
+// clockOrSystem returns the clock, or SystemClock if it is nil.
+func clockOrSystem(clock Clock) Clock {
+	if clock == nil {
+		return SystemClock
+	}
+
+	return clock
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{time.NewTicker(d)}
+}
+
+type systemTimer struct {
+	*time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+type systemTicker struct {
+	*time.Ticker
+}
+
+func (t systemTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
@@ -0,0 +1,28 @@
+package cryptomus
+
+import "context"
+
+// coalesce runs fetch once for the concurrent calls with the same key, so that a burst of identical reads
+// from many goroutines produces a single request; the calls share its result and error. The shared request
+// is detached from the cancellation of the callers, each of which stops waiting when its own ctx is done,
+// so one caller giving up doesn't fail the others. Results must be copied before they are handed out.
+func coalesce[T any](ctx context.Context, c *Cryptomus, key string, fetch func(ctx context.Context) (T, error)) (T, error) {
+	// Requests to another base URL or merchant are not identical
+	baseURL, creds := c.settings()
+	key = baseURL + "\x00" + creds.merchantID + "\x00" + key
+
+	ch := c.flight.DoChan(key, func() (interface{}, error) {
+		return fetch(context.WithoutCancel(ctx))
+	})
+
+	var zero T
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return zero, res.Err
+		}
+		return res.Val.(T), nil
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
@@ -0,0 +1,154 @@
+package cryptomus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// inverseCoursePrecision is the number of decimal places kept when inverting or crossing courses.
+const inverseCoursePrecision = 18
+
+// crossCurrencies are the intermediate currencies used to derive a cross rate, in order of preference.
+var crossCurrencies = []string{"USDT", "USD"}
+
+// Conversion represents the result of converting an amount between currencies.
+type Conversion struct {
+	Amount decimal.Decimal // Converted amount in the target currency
+	Course decimal.Decimal // Course applied to the source amount
+	Rate   ExchangeRate    // Exchange rate the course was resolved from, synthetic if Derived
+	// Derived reports whether the course was computed as a cross rate
+	// because the API returned no direct pair.
+	Derived bool
+	Via     string // Intermediate currency of a derived cross rate
+}
+
+// Convert converts the amount from one currency to another using the current exchange rates.
+// The rate is looked up as a direct pair first, then as the inverse pair, and is
+// otherwise derived as a cross rate through USDT or USD.
+func (c *Cryptomus) Convert(ctx context.Context, amount, from, to string) (*Conversion, error) {
+	return convert(ctx, c, amount, from, to)
+}
+
+// Convert converts the amount from one currency to another using the cached exchange rates.
+func (rc *ExchangeRateCache) Convert(ctx context.Context, amount, from, to string) (*Conversion, error) {
+	return convert(ctx, rc, amount, from, to)
+}
+
+// Convert converts the amount from one currency to another using the exchange rates of the provider.
+func Convert(ctx context.Context, provider RateProvider, amount, from, to string) (*Conversion, error) {
+	return convert(ctx, provider, amount, from, to)
+}
+
+// convert resolves the course between the currencies through the provider and applies it to the amount.
+func convert(ctx context.Context, provider RateProvider, amount, from, to string) (*Conversion, error) {
+	value, err := decimal.NewFromString(strings.TrimSpace(amount))
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount %q: %w", amount, err)
+	}
+
+	from = strings.ToUpper(strings.TrimSpace(from))
+	to = strings.ToUpper(strings.TrimSpace(to))
+	if from == "" || to == "" {
+		return nil, errors.New("both from and to currencies are required")
+	}
+
+	rate, course, via, err := resolveCourse(ctx, provider, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Conversion{
+		Amount:  value.Mul(course),
+		Course:  course,
+		Rate:    rate,
+		Derived: via != "",
+		Via:     via,
+	}, nil
+}
+
+// resolveCourse finds the course of the from/to pair.
+// A direct pair is preferred, then the inverted to/from pair, and finally a cross rate
+// derived through one of the crossCurrencies.
+func resolveCourse(ctx context.Context, provider RateProvider, from, to string) (ExchangeRate, decimal.Decimal, string, error) {
+	if from == to {
+		return ExchangeRate{From: from, To: to, Course: AmountFromDecimal(decimal.NewFromInt(1))}, decimal.NewFromInt(1), "", nil
+	}
+
+	rate, course, found, err := pairCourse(ctx, provider, from, to)
+	if err != nil {
+		return ExchangeRate{}, decimal.Decimal{}, "", err
+	}
+	if found {
+		return rate, course, "", nil
+	}
+
+	// A leg the API can't list, e.g. from USD as it rejects listings for fiat, leaves the route unpriced
+	// rather than failing the conversion, so that the next intermediate currency is tried
+	var legErrs []error
+	for _, via := range crossCurrencies {
+		if via == from || via == to {
+			continue
+		}
+
+		_, fromCourse, found, err := pairCourse(ctx, provider, from, via)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ExchangeRate{}, decimal.Decimal{}, "", err
+			}
+			legErrs = append(legErrs, err)
+			continue
+		}
+		if !found {
+			continue
+		}
+
+		_, toCourse, found, err := pairCourse(ctx, provider, via, to)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ExchangeRate{}, decimal.Decimal{}, "", err
+			}
+			legErrs = append(legErrs, err)
+			continue
+		}
+		if !found {
+			continue
+		}
+
+		course := fromCourse.Mul(toCourse).Round(inverseCoursePrecision)
+		return ExchangeRate{From: from, To: to, Course: AmountFromDecimal(course)}, course, via, nil
+	}
+
+	if len(legErrs) > 0 {
+		return ExchangeRate{}, decimal.Decimal{}, "", fmt.Errorf("no exchange rate found for %s/%s: %w", from, to, errors.Join(legErrs...))
+	}
+	return ExchangeRate{}, decimal.Decimal{}, "", fmt.Errorf("no exchange rate found for %s/%s", from, to)
+}
+
+// pairCourse looks up the course of the from/to pair, inverting the to/from pair if needed.
+// It reports whether any of the pairs was returned by the provider, failing with the error of
+// the from listing if it failed and the inverse pair wasn't found either.
+func pairCourse(ctx context.Context, provider RateProvider, from, to string) (ExchangeRate, decimal.Decimal, bool, error) {
+	rates, listErr := provider.ListExchangeRates(ctx, from, &ExchangeRateOptions{To: []string{to}})
+	if listErr == nil && len(rates) > 0 {
+		return rates[0], rates[0].Course.Decimal, true, nil
+	}
+	if ctx.Err() != nil {
+		return ExchangeRate{}, decimal.Decimal{}, false, listErr
+	}
+
+	// The API rejects listings for some currencies (e.g., fiat), so a failed inverse lookup is not fatal.
+	rates, err := provider.ListExchangeRates(ctx, to, &ExchangeRateOptions{To: []string{from}})
+	if err == nil && len(rates) > 0 {
+		course := rates[0].Course.Decimal
+		if course.IsZero() {
+			return ExchangeRate{}, decimal.Decimal{}, false, fmt.Errorf("zero course for %s/%s", to, from)
+		}
+		return rates[0], decimal.NewFromInt(1).DivRound(course, inverseCoursePrecision), true, nil
+	}
+
+	return ExchangeRate{}, decimal.Decimal{}, false, listErr
+}
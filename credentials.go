@@ -0,0 +1,72 @@
+package cryptomus
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrMissingCredential is returned when the merchant ID or the API key an operation needs is not configured.
+var ErrMissingCredential = errors.New("missing credential")
+
+// CredentialError names the credential that is missing.
+type CredentialError struct {
+	Credential string // "merchant ID", "payment API key" or "payout API key"
+}
+
+func (e *CredentialError) Error() string {
+	return fmt.Sprintf("%s is not configured", e.Credential)
+}
+
+// Is makes errors.Is(err, ErrMissingCredential) report true.
+func (e *CredentialError) Is(target error) bool {
+	return target == ErrMissingCredential
+}
+
+// NewWithOptions creates a new Cryptomus API client like New, but returns a *CredentialError
+// if the merchant ID or both API keys are empty, instead of a client whose requests fail.
+// A client configured with one of the keys only is valid; using an operation that needs the other
+// one returns a *CredentialError on first use.
+func NewWithOptions(client *http.Client, merchantID, paymentApiKey, payoutApiKey string, opts ...Option) (*Cryptomus, error) {
+	if merchantID == "" {
+		return nil, &CredentialError{Credential: "merchant ID"}
+	}
+	if paymentApiKey == "" && payoutApiKey == "" {
+		return nil, &CredentialError{Credential: "payment API key"}
+	}
+
+	return New(client, merchantID, paymentApiKey, payoutApiKey, opts...), nil
+}
+
+// credentials are the merchant identifier and the API keys of a client.
+type credentials struct {
+	merchantID    string // Merchant identifier
+	paymentApiKey string // API key for payment operations
+	payoutApiKey  string // API key for payout operations
+}
+
+// currentCredentials returns the current credentials of the client.
+func (c *Cryptomus) currentCredentials() credentials {
+	_, creds := c.settings()
+	return creds
+}
+
+// requestKey returns the API key requests authenticated with auth are signed with,
+// or a *CredentialError if it or the merchant ID is not configured.
+func (c credentials) requestKey(auth RequestAuth) (string, error) {
+	if c.merchantID == "" {
+		return "", &CredentialError{Credential: "merchant ID"}
+	}
+
+	if auth == AuthPayout {
+		if c.payoutApiKey == "" {
+			return "", &CredentialError{Credential: "payout API key"}
+		}
+		return c.payoutApiKey, nil
+	}
+
+	if c.paymentApiKey == "" {
+		return "", &CredentialError{Credential: "payment API key"}
+	}
+	return c.paymentApiKey, nil
+}
@@ -3,11 +3,17 @@ package cryptomus
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/sync/singleflight"
 )
 
 // BaseURL is the default API endpoint for Cryptomus.
@@ -15,40 +21,105 @@ import (
 const BaseURL = "https://api.cryptomus.com/v1"
 
 // Cryptomus represents the Cryptomus API client.
+//
+// A client is safe for concurrent use by multiple goroutines and is meant to be shared. Its configuration
+// is fixed by the options once New returns; only the base URL and the credentials can be changed
+// afterwards, with SetBaseURL and SetCredentials, which take effect for the requests started after them.
 type Cryptomus struct {
-	baseURL       string       // Base URL for the API endpoints
-	merchantID    string       // Merchant identifier
-	paymentApiKey string       // API key for payment operations
-	payoutApiKey  string       // API key for payout operations
-	client        *http.Client // HTTP client used to make requests
+	mu         sync.RWMutex // Guards baseURL and creds
+	baseURL    string       // Base URL for the API endpoints
+	creds      credentials  // Merchant identifier and API keys
+	client     *http.Client // HTTP client used to make requests
+	metrics    Metrics      // Receives counters and timings, no-op unless set with WithMetrics
+	debug      bool         // Adds diagnostics to errors, see WithDebug
+	signer     Signer       // Signs requests and verifies webhooks, DefaultSigner unless set with WithSigner
+	emptyBody  EmptyBody    // What signed requests without payload send and sign, see WithEmptyBody
+	signInBody bool         // Adds the signature to JSON object bodies, see WithSignInBody
+	retry      RetryPolicy  // Retries of rate-limited requests, none unless set with WithRetry
+	onError    ErrorHook    // Reports failed calls, see WithOnError
+	sandbox    *sandbox     // Guardrails of test environments, see WithSandbox
+	clock      Clock        // Tells the time and waits for retries, SystemClock unless set with WithClock
+
+	transportOpts *TransportOptions            // Tunes the client built when New is given none, see WithTransportOptions
+	flight        singleflight.Group           // Coalesces concurrent identical reads, see coalesce
+	urls          atomic.Pointer[endpointURLs] // Full URLs of the endpoints under the base URL, see endpointURL
+	batch         *BatchOptions                // Runs the items of the batch methods, see WithBatchOptions
+
+	strictDecoding bool // Rejects unknown response fields, see WithStrictDecoding
+
+	previousPaymentKeys []string // Rotated payment keys still accepted on webhooks
+	previousPayoutKeys  []string // Rotated payout keys still accepted on webhooks
 }
 
+// Option configures a Cryptomus client.
+type Option func(*Cryptomus)
+
 // NewCryptomus creates a new Cryptomus API client.
 // Parameters:
-// - client: An instance of http.Client. If nil, http.DefaultClient is used.
+// - client: An instance of http.Client. If nil, a client tuned for the API is built, see NewHTTPClient and WithTransportOptions.
 // - merchantID: Your merchant identifier.
 // - paymentApiKey: Your API key for payment-related operations.
 // - payoutApiKey: Your API key for payout-related operations.
-func New(client *http.Client, merchantID, paymentApiKey, payoutApiKey string) *Cryptomus {
-	if client == nil {
-		client = http.DefaultClient
+// - opts: Optional settings, e.g. WithMetrics.
+func New(client *http.Client, merchantID, paymentApiKey, payoutApiKey string, opts ...Option) *Cryptomus {
+	c := &Cryptomus{
+		baseURL: BaseURL,
+		creds: credentials{
+			merchantID:    merchantID,
+			paymentApiKey: paymentApiKey,
+			payoutApiKey:  payoutApiKey,
+		},
+		client:  client,
+		metrics: noopMetrics{},
+		signer:  DefaultSigner,
+		clock:   SystemClock,
 	}
-
-	return &Cryptomus{
-		baseURL:       BaseURL,
-		merchantID:    merchantID,
-		paymentApiKey: paymentApiKey,
-		payoutApiKey:  payoutApiKey,
-		client:        client,
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.client == nil {
+		c.client = NewHTTPClient(c.transportOpts)
 	}
+
+	return c
 }
 
 // SetBaseURL allows overriding the default BaseURL.
 // This can be useful for testing or if the API endpoint changes.
+// It is safe to call while requests are in flight; they keep the base URL they started with.
 func (c *Cryptomus) SetBaseURL(baseURL string) {
+	c.mu.Lock()
 	c.baseURL = baseURL
+	c.mu.Unlock()
+}
+
+// SetCredentials replaces the merchant identifier and the API keys, e.g. when the keys are rotated.
+// It is safe to call while requests are in flight; they keep the credentials they started with.
+// Webhooks signed with the replaced keys fail verification unless they are passed to
+// WithPreviousPaymentKeys or WithPreviousPayoutKeys.
+func (c *Cryptomus) SetCredentials(merchantID, paymentApiKey, payoutApiKey string) {
+	c.mu.Lock()
+	c.creds = credentials{merchantID: merchantID, paymentApiKey: paymentApiKey, payoutApiKey: payoutApiKey}
+	c.mu.Unlock()
 }
 
+// settings returns the base URL and the credentials, as a consistent snapshot.
+func (c *Cryptomus) settings() (string, credentials) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.baseURL, c.creds
+}
+
+// RequestAuth defines how a request is authenticated.
+type RequestAuth int
+
+const (
+	AuthPayment RequestAuth = iota // Signed with the payment API key
+	AuthPayout                     // Signed with the payout API key
+	AuthNone                       // Public endpoint, sent without merchant and sign headers
+)
+
 // fetch performs an HTTP request to the specified endpoint with the given method and payload.
 // It sets the necessary headers, including merchant ID and signature.
 // Parameters:
@@ -59,47 +130,147 @@ func (c *Cryptomus) SetBaseURL(baseURL string) {
 // - *http.Response: The HTTP response from the API.
 // - error: Error if the request failed.
 func (c *Cryptomus) fetch(method, endpoint string, payload interface{}) (*http.Response, error) {
-	// Marshal the payload into JSON.
-	var bodyBytes []byte
-	var err error
-	if payload != nil {
-		bodyBytes, err = json.Marshal(payload)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal payload: %w", err)
-		}
+	return c.do(context.Background(), method, endpoint, payload, AuthPayment)
+}
+
+// Call performs a request to an API endpoint the client has no method for, e.g. one Cryptomus added recently.
+// The payload is sent as JSON and the request is signed with the key selected by auth.
+// The caller is responsible for closing the response body.
+func (c *Cryptomus) Call(ctx context.Context, method, endpoint string, payload interface{}, auth RequestAuth) (*http.Response, error) {
+	return c.do(ctx, method, endpoint, payload, auth)
+}
+
+// do is the common request pipeline used by every endpoint.
+// The request is bound to ctx, and is signed according to auth.
+// The payload of GET and HEAD requests is sent as query parameters, and the encoded query is signed.
+func (c *Cryptomus) do(ctx context.Context, method, endpoint string, payload interface{}, auth RequestAuth) (*http.Response, error) {
+	// Describe the request in the errors it fails with.
+	info := &requestInfo{
+		Method:   method,
+		Endpoint: endpoint,
+		ctx:      ctx,
+		start:    c.clock.Now(),
+		clock:    c.clock,
+		onError:  c.onError,
+		strict:   c.strictDecoding,
 	}
 
-	// Generate the signature using the payment API key.
-	// Предполагается, что метод signRequest реализован в sign.go.
-	sign, err := c.signRequest(c.paymentApiKey, bodyBytes)
+	// Use the same base URL and credentials for the whole call, even if they are changed meanwhile.
+	baseURL, creds := c.settings()
+
+	// Создаём полный URL с использованием joinURL, запоминая его для следующих запросов.
+	endpointURL, err := c.endpointURL(baseURL, endpoint)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate signature: %w", err)
+		return nil, info.fail(fmt.Errorf("failed to join base URL and endpoint: %w", err))
+	}
+	fullURL := endpointURL.full
+	if endpointURL.path != "" {
+		info.Endpoint = endpointURL.path
 	}
 
-	// Создаём полный URL с использованием joinURL.
-	fullURL, err := joinURL(c.baseURL, endpoint)
-	if err != nil {
-		return nil, fmt.Errorf("failed to join base URL and endpoint: %w", err)
+	// Check the payload before anything is sent.
+	if err := validateRequest(payload); err != nil {
+		return nil, info.fail(err)
 	}
 
-	// Создаём новый HTTP-запрос.
-	req, err := http.NewRequest(method, fullURL, bytes.NewBuffer(bodyBytes))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	// Marshal the payload into JSON, or into the query for reads.
+	var bodyBytes, signedBytes []byte
+	var query string
+	if method == http.MethodGet || method == http.MethodHead {
+		if payload != nil {
+			values, err := queryValues(payload)
+			if err != nil {
+				return nil, info.fail(fmt.Errorf("failed to encode query: %w", err))
+			}
+			query = values.Encode()
+			info.OrderID, info.UUID = values.Get("order_id"), values.Get("uuid")
+		}
+		signedBytes = []byte(query)
+	} else {
+		if payload != nil {
+			bodyBytes, err = MarshalCanonical(payload)
+			if err != nil {
+				return nil, info.fail(fmt.Errorf("failed to marshal payload: %w", err))
+			}
+			info.identify(bodyBytes)
+		} else if auth != AuthNone {
+			bodyBytes = c.emptyBody.bytes()
+		}
+		signedBytes = bodyBytes
 	}
 
-	// Устанавливаем необходимые заголовки.
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("merchant", c.merchantID)
-	req.Header.Set("sign", sign)
+	if c.sandbox != nil {
+		if err := c.sandbox.check(endpoint, bodyBytes); err != nil {
+			return nil, info.fail(err)
+		}
+	}
 
-	// Выполняем HTTP-запрос.
-	res, err := c.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	if query != "" {
+		if strings.Contains(fullURL, "?") {
+			fullURL += "&" + query
+		} else {
+			fullURL += "?" + query
+		}
+	}
+
+	var sign string
+	if auth != AuthNone {
+		// Generate the signature using the API key matching the endpoint.
+		apiKey, err := creds.requestKey(auth)
+		if err != nil {
+			return nil, info.fail(err)
+		}
+		sign, err = c.signRequest(apiKey, signedBytes)
+		if err != nil {
+			return nil, info.fail(fmt.Errorf("failed to generate signature: %w", err))
+		}
+
+		if c.signInBody && bytes.HasPrefix(bytes.TrimSpace(bodyBytes), []byte("{")) {
+			bodyBytes = appendSignField(bytes.TrimSpace(bodyBytes), sign)
+		}
 	}
 
-	return res, nil
+	// Отправляем запрос, повторяя его, пока API ограничивает частоту запросов и политика это позволяет.
+	for attempt := 0; ; attempt++ {
+		// Создаём новый HTTP-запрос.
+		var body io.Reader
+		if bodyBytes != nil {
+			body = bytes.NewReader(bodyBytes)
+		}
+		req, err := http.NewRequestWithContext(withRequestInfo(ctx, info), method, fullURL, body)
+		if err != nil {
+			return nil, info.fail(fmt.Errorf("failed to create HTTP request: %w", err))
+		}
+
+		// Устанавливаем необходимые заголовки.
+		req.Header.Set("Accept", "application/json")
+		if bodyBytes != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		if auth != AuthNone {
+			req.Header.Set("merchant", creds.merchantID)
+			req.Header.Set("sign", sign)
+		}
+		if c.sandbox != nil {
+			req.Header.Set(SandboxHeader, "1")
+		}
+
+		// Выполняем HTTP-запрос.
+		res, err := c.client.Do(req)
+		if err != nil {
+			return nil, info.fail(&TransportError{Err: err})
+		}
+
+		wait, ok := c.retry.retryWait(res, attempt, c.clock.Now())
+		if !ok {
+			return res, nil
+		}
+		discardResponse(res)
+		if err := sleepContext(ctx, c.clock, wait); err != nil {
+			return nil, info.fail(err)
+		}
+	}
 }
 
 // joinURL корректно объединяет base и endpoint в полный URL.
@@ -109,6 +280,10 @@ func joinURL(base, endpoint string) (string, error) {
 		return "", err
 	}
 
+	// Отделяем параметры запроса, переданные вместе с endpoint
+	endpoint, query, _ := strings.Cut(endpoint, "?")
+	u.RawQuery = query
+
 	// Объединяем пути, избегая двойных слешей
 	u.Path = path.Join(u.Path, endpoint)
 	return u.String(), nil
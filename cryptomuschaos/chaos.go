@@ -0,0 +1,289 @@
+// Package cryptomuschaos provides a fault-injection http.RoundTripper for the cryptomus client. It injects
+// timeouts, 5xx and 429 responses, truncated bodies and malformed JSON on a schedule, so tests can verify that
+// the retry policy and the error handling of an integration behave as configured:
+//
+//	chaos := cryptomuschaos.New(cryptomuschaos.Sequence(cryptomuschaos.FaultRateLimit, cryptomuschaos.FaultRateLimit))
+//	chaos.Transport = mockServer.Client().Transport
+//	client := cryptomus.New(chaos.Client(), merchant, paymentKey, payoutKey,
+//		cryptomus.WithRetry(cryptomus.RetryPolicy{MaxRetries: 2, DefaultWait: time.Millisecond}))
+//
+// Timeouts, 5xx and 429 responses are injected without sending the request, as if it never reached the API.
+// Truncated and malformed bodies are injected into the response of a request that was sent, as if the
+// API processed it and the response was lost on the way back.
+package cryptomuschaos
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Fault is a failure injected into a request.
+type Fault int
+
+const (
+	FaultNone          Fault = iota // Send the request untouched
+	FaultTimeout                    // Fail with ErrTimeout, without sending the request
+	FaultServerError                // Respond with a 5xx status, without sending the request
+	FaultRateLimit                  // Respond with 429 Too Many Requests, without sending the request
+	FaultTruncatedBody              // Send the request and cut the response body short with io.ErrUnexpectedEOF
+	FaultMalformedJSON              // Send the request and replace the response body with invalid JSON
+)
+
+func (f Fault) String() string {
+	switch f {
+	case FaultNone:
+		return "none"
+	case FaultTimeout:
+		return "timeout"
+	case FaultServerError:
+		return "server error"
+	case FaultRateLimit:
+		return "rate limit"
+	case FaultTruncatedBody:
+		return "truncated body"
+	case FaultMalformedJSON:
+		return "malformed JSON"
+	default:
+		return "Fault(" + strconv.Itoa(int(f)) + ")"
+	}
+}
+
+// ErrTimeout is returned for requests failed with FaultTimeout. It is a net.Error reporting a timeout,
+// like the errors of an http.Client whose Timeout expired.
+var ErrTimeout error = timeoutError{}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "cryptomuschaos: injected timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// MalformedBody is the response body of FaultMalformedJSON.
+const MalformedBody = `{"state":0,"result":{"uuid":`
+
+// Schedule decides the fault injected into the n-th request sent through the transport, counting from 0.
+type Schedule interface {
+	Fault(req *http.Request, n int) Fault
+}
+
+// ScheduleFunc adapts a function to a Schedule.
+type ScheduleFunc func(req *http.Request, n int) Fault
+
+// Fault calls f(req, n).
+func (f ScheduleFunc) Fault(req *http.Request, n int) Fault {
+	return f(req, n)
+}
+
+// Sequence injects the faults into the first requests, one per request, and none into the following ones.
+// Use FaultNone to let a request through in the middle of the sequence.
+func Sequence(faults ...Fault) Schedule {
+	return ScheduleFunc(func(_ *http.Request, n int) Fault {
+		if n < len(faults) {
+			return faults[n]
+		}
+		return FaultNone
+	})
+}
+
+// Every injects the fault into every n-th request: the n-th, the 2n-th and so on.
+func Every(n int, fault Fault) Schedule {
+	return ScheduleFunc(func(_ *http.Request, i int) Fault {
+		if n > 0 && (i+1)%n == 0 {
+			return fault
+		}
+		return FaultNone
+	})
+}
+
+// Random injects the fault into requests with the probability p. The source is seeded,
+// so a failing run can be reproduced with the same seed.
+func Random(p float64, fault Fault, seed int64) Schedule {
+	var mu sync.Mutex
+	rnd := rand.New(rand.NewSource(seed))
+
+	return ScheduleFunc(func(_ *http.Request, _ int) Fault {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if rnd.Float64() < p {
+			return fault
+		}
+		return FaultNone
+	})
+}
+
+// Injection records the fault injected into a request.
+type Injection struct {
+	Method string
+	URL    string
+	Fault  Fault
+}
+
+// Transport is an http.RoundTripper injecting the faults of its Schedule into the requests.
+// It is safe for concurrent use.
+type Transport struct {
+	Transport http.RoundTripper // Transport the requests are sent with, http.DefaultTransport if nil
+	Schedule  Schedule          // Faults to inject, none if nil
+
+	StatusCode int           // Status of FaultServerError responses, 503 Service Unavailable if zero
+	RetryAfter time.Duration // Retry-After of FaultRateLimit responses, not set if zero
+	Latency    time.Duration // How long FaultTimeout waits before failing, bounded by the request context
+
+	mu         sync.Mutex
+	requests   int
+	injections []Injection
+}
+
+// New returns a transport injecting the faults of the schedule into requests sent with http.DefaultTransport.
+func New(schedule Schedule) *Transport {
+	return &Transport{Schedule: schedule}
+}
+
+// Client returns an HTTP client sending its requests through the transport.
+func (t *Transport) Client() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+// Requests returns the number of requests that went through the transport, faulty or not.
+func (t *Transport) Requests() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.requests
+}
+
+// Injections returns the faults injected so far, in the order of the requests.
+func (t *Transport) Injections() []Injection {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]Injection(nil), t.injections...)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fault := t.next(req)
+
+	switch fault {
+	case FaultTimeout:
+		closeBody(req)
+		return nil, t.timeout(req.Context())
+	case FaultServerError:
+		closeBody(req)
+		status := t.StatusCode
+		if status == 0 {
+			status = http.StatusServiceUnavailable
+		}
+		return response(req, status, fmt.Sprintf(`{"state":1,"message":%q}`, http.StatusText(status))), nil
+	case FaultRateLimit:
+		closeBody(req)
+		res := response(req, http.StatusTooManyRequests, `{"state":1,"message":"Too many requests"}`)
+		if t.RetryAfter > 0 {
+			res.Header.Set("Retry-After", strconv.Itoa(int((t.RetryAfter+time.Second-1)/time.Second)))
+		}
+		return res, nil
+	}
+
+	res, err := t.transport().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch fault {
+	case FaultTruncatedBody:
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		res.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body[:len(body)/2]), errReader{io.ErrUnexpectedEOF}))
+	case FaultMalformedJSON:
+		_, _ = io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+		res.Body = io.NopCloser(bytes.NewReader([]byte(MalformedBody)))
+		res.ContentLength = int64(len(MalformedBody))
+		res.Header.Del("Content-Length")
+	}
+
+	return res, nil
+}
+
+// next counts the request and returns the fault to inject into it.
+func (t *Transport) next(req *http.Request) Fault {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	fault := FaultNone
+	if t.Schedule != nil {
+		fault = t.Schedule.Fault(req, t.requests)
+	}
+	t.requests++
+	if fault != FaultNone {
+		t.injections = append(t.injections, Injection{Method: req.Method, URL: req.URL.String(), Fault: fault})
+	}
+
+	return fault
+}
+
+// timeout waits for the latency, or until ctx is done, and returns the error of the timeout.
+func (t *Transport) timeout(ctx context.Context) error {
+	if t.Latency <= 0 {
+		return ErrTimeout
+	}
+
+	timer := time.NewTimer(t.Latency)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return ErrTimeout
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *Transport) transport() http.RoundTripper {
+	if t.Transport != nil {
+		return t.Transport
+	}
+
+	return http.DefaultTransport
+}
+
+// response returns a JSON response to the request.
+func response(req *http.Request, status int, body string) *http.Response {
+	return &http.Response{
+		Status:        strconv.Itoa(status) + " " + http.StatusText(status),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader([]byte(body))),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// closeBody closes the body of a request that is not sent, as a RoundTripper must.
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+}
+
+// errReader fails every read with err.
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
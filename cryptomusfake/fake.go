@@ -0,0 +1,102 @@
+// Package cryptomusfake provides a stateful in-memory implementation of the client interfaces of the cryptomus
+// package, to test business flows end to end without HTTP. Invoices, static wallets, payouts and recurring payments
+// are kept in memory, and their status transitions are triggered from the tests:
+//
+//	fake := cryptomusfake.New()
+//	shop := NewShop(fake) // depends on cryptomus.PaymentsAPI
+//	shop.Checkout("order-1")
+//	if err := fake.MarkPaid("order-1"); err != nil {
+//		t.Fatal(err)
+//	}
+//
+// Requests are not validated like the client does before sending them.
+package cryptomusfake
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/backtrac3r/go-cryptomus"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultInvoiceLifetime is the lifetime of the invoices created without one.
+const DefaultInvoiceLifetime = time.Hour
+
+var _ cryptomus.CryptomusAPI = (*Fake)(nil)
+
+// Fake is an in-memory cryptomus.CryptomusAPI. It is safe for concurrent use.
+// The objects it returns are copies, which the tests may modify freely.
+type Fake struct {
+	// Now optionally returns the current time, time.Now by default.
+	Now func() time.Time
+	// PaymentServices is returned by GetPaymentServicesList.
+	PaymentServices []*cryptomus.PaymentService
+	// PayoutServices is returned by GetPayoutServicesList.
+	PayoutServices []*cryptomus.PayoutService
+
+	mu          sync.Mutex
+	seq         int
+	payments    []*cryptomus.Payment
+	wallets     []*wallet
+	payouts     []*cryptomus.Payout
+	recurrences []*cryptomus.Recurrence
+	rates       map[string][]cryptomus.ExchangeRate
+	balances    map[string]decimal.Decimal
+}
+
+// wallet is a static wallet and its status.
+type wallet struct {
+	cryptomus.StaticWalletResponse
+	status cryptomus.WalletStatus
+}
+
+// New creates a new empty fake.
+func New() *Fake {
+	return &Fake{
+		rates:    make(map[string][]cryptomus.ExchangeRate),
+		balances: make(map[string]decimal.Decimal),
+	}
+}
+
+// now returns the current time.
+func (f *Fake) now() time.Time {
+	if f.Now != nil {
+		return f.Now()
+	}
+
+	return time.Now()
+}
+
+// newUUID returns the next identifier, deterministic so that tests can rely on it.
+func (f *Fake) newUUID() cryptomus.UUID {
+	f.seq++
+	return cryptomus.UUID(fmt.Sprintf("00000000-0000-4000-8000-%012d", f.seq))
+}
+
+// apiError returns the error the API fails with, matching the sentinel errors of the cryptomus package
+// like the errors of the client do.
+func apiError(endpoint, message string) error {
+	return &cryptomus.APIError{
+		State:      1,
+		StatusCode: http.StatusOK,
+		Message:    message,
+		Method:     http.MethodPost,
+		Endpoint:   "/v1" + endpoint,
+	}
+}
+
+// notFound returns the error of a lookup of an unknown object.
+func notFound(endpoint, object string, uuid cryptomus.UUID, orderID string) error {
+	err := apiError(endpoint, object+" not found").(*cryptomus.APIError)
+	err.UUID, err.OrderID = string(uuid), orderID
+
+	return err
+}
+
+// inRange reports whether the time is within the optional date range.
+func inRange(t, from, to time.Time) bool {
+	return (from.IsZero() || !t.Before(from)) && (to.IsZero() || !t.After(to))
+}
@@ -0,0 +1,235 @@
+package cryptomusfake
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/backtrac3r/go-cryptomus"
+)
+
+// CreateInvoice creates an invoice in the check status. Like the API, it returns the existing invoice
+// if the order already has one that is not final.
+func (f *Fake) CreateInvoice(invoiceReq *cryptomus.InvoiceRequest) (*cryptomus.Payment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if existing := f.findPayment("", invoiceReq.OrderID); existing != nil {
+		f.expire(existing)
+		if !existing.IsFinal {
+			return copyPayment(existing), nil
+		}
+		return nil, apiError("/payment", "The order id has already been taken.")
+	}
+
+	now := f.now()
+	payment := &cryptomus.Payment{
+		UUID:          f.newUUID(),
+		OrderID:       invoiceReq.OrderID,
+		Amount:        invoiceReq.Amount,
+		Currency:      string(invoiceReq.Currency),
+		PaymentStatus: cryptomus.PaymentStatusCheck,
+		Status:        cryptomus.PaymentStatusCheck,
+		CreatedAt:     cryptomus.NewCryptomusTime(now),
+		UpdatedAt:     cryptomus.NewCryptomusTime(now),
+	}
+	payment.Url = "https://pay.cryptomus.com/pay/" + string(payment.UUID)
+
+	lifetime := DefaultInvoiceLifetime
+	if opts := invoiceReq.InvoiceRequestOptions; opts != nil {
+		payment.Network = opts.Network
+		payment.AdditionalData = opts.AdditionalData
+		if opts.Lifetime > 0 {
+			lifetime = opts.Lifetime
+		}
+	}
+	payment.ExpiredAt = cryptomus.NewCryptomusTime(now.Add(lifetime))
+
+	f.payments = append(f.payments, payment)
+
+	return copyPayment(payment), nil
+}
+
+// GeneratePaymentQRCode returns a placeholder QR code image of the invoice.
+func (f *Fake) GeneratePaymentQRCode(paymentUUID cryptomus.UUID) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.findPayment(paymentUUID, "") == nil {
+		return "", notFound("/payment/qr", "Payment", paymentUUID, "")
+	}
+
+	return "data:image/png;base64,ZmFrZQ==", nil
+}
+
+// GetPaymentInfo returns the invoice of the uuid or order_id of the request.
+func (f *Fake) GetPaymentInfo(paymentInfoReq *cryptomus.PaymentInfoRequest) (*cryptomus.Payment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	payment := f.findPayment(paymentInfoReq.PaymentUUID, paymentInfoReq.OrderID)
+	if payment == nil {
+		return nil, notFound("/payment/info", "Payment", paymentInfoReq.PaymentUUID, paymentInfoReq.OrderID)
+	}
+	f.expire(payment)
+
+	return copyPayment(payment), nil
+}
+
+// GetPaymentInfos looks up the invoice of every request.
+func (f *Fake) GetPaymentInfos(paymentInfoReqs []*cryptomus.PaymentInfoRequest) ([]*cryptomus.Payment, error) {
+	return cryptomus.RunBatch(context.Background(), len(paymentInfoReqs), nil, func(_ context.Context, i int) (*cryptomus.Payment, error) {
+		return f.GetPaymentInfo(paymentInfoReqs[i])
+	})
+}
+
+// GetPaymentHistory returns the payments created within the date range, newest first, as a single page.
+func (f *Fake) GetPaymentHistory(dateFrom, dateTo time.Time) (*cryptomus.PaymentHistoryResponse, error) {
+	payments := f.paymentHistory(dateFrom, dateTo)
+
+	return &cryptomus.PaymentHistoryResponse{
+		Payments: payments,
+		Paginate: &cryptomus.PaymentHistoryPaginate{Count: len(payments), PerPage: len(payments)},
+	}, nil
+}
+
+// PaymentHistory returns an iterator over the payments selected by the options, newest first.
+func (f *Fake) PaymentHistory(ctx context.Context, opts *cryptomus.HistoryOptions) *cryptomus.Iterator[*cryptomus.Payment] {
+	if opts == nil {
+		opts = &cryptomus.HistoryOptions{}
+	}
+
+	return cryptomus.NewSliceIterator(ctx, f.paymentHistory(opts.DateFrom, opts.DateTo))
+}
+
+// ListAllPayments returns the payments selected by the history options, newest first.
+func (f *Fake) ListAllPayments(ctx context.Context, historyOpts *cryptomus.HistoryOptions, opts *cryptomus.ListAllOptions) ([]*cryptomus.Payment, error) {
+	it := f.PaymentHistory(ctx, historyOpts)
+	payments := []*cryptomus.Payment{}
+	for it.Next() {
+		if opts != nil && opts.MaxItems > 0 && len(payments) == opts.MaxItems {
+			return payments, fmt.Errorf("%w: more than %d items", cryptomus.ErrListTruncated, opts.MaxItems)
+		}
+		payments = append(payments, it.Item())
+	}
+
+	return payments, it.Err()
+}
+
+// paymentHistory returns copies of the payments created within the date range, newest first.
+func (f *Fake) paymentHistory(dateFrom, dateTo time.Time) []*cryptomus.Payment {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	payments := []*cryptomus.Payment{}
+	for i := len(f.payments) - 1; i >= 0; i-- {
+		payment := f.payments[i]
+		f.expire(payment)
+		if inRange(payment.CreatedAt.Time, dateFrom, dateTo) {
+			payments = append(payments, copyPayment(payment))
+		}
+	}
+
+	return payments
+}
+
+// GetPaymentServicesList returns the PaymentServices of the fake.
+func (f *Fake) GetPaymentServicesList() ([]*cryptomus.PaymentService, error) {
+	return f.PaymentServices, nil
+}
+
+// Refund starts refunding a paid invoice, which moves to the refund_process status.
+// Complete it with SetPaymentStatus and cryptomus.PaymentStatusRefundPaid.
+func (f *Fake) Refund(refundRequest *cryptomus.RefundRequest) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	payment := f.findPayment(refundRequest.PaymentUUID, refundRequest.OrderID)
+	if payment == nil {
+		return false, notFound("/payment/refund", "Payment", refundRequest.PaymentUUID, refundRequest.OrderID)
+	}
+	if !payment.PaymentStatus.IsSuccessful() {
+		return false, apiError("/payment/refund", fmt.Sprintf("Payment in status %s cannot be refunded", payment.PaymentStatus))
+	}
+
+	f.setPaymentStatus(payment, cryptomus.PaymentStatusRefundProcess)
+
+	return true, nil
+}
+
+// SetPaymentStatus moves the invoice of the order to the status, e.g. cryptomus.PaymentStatusWrongAmount.
+func (f *Fake) SetPaymentStatus(orderID string, status cryptomus.PaymentStatus) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	payment := f.findPayment("", orderID)
+	if payment == nil {
+		return notFound("/payment/info", "Payment", "", orderID)
+	}
+
+	f.setPaymentStatus(payment, status)
+
+	return nil
+}
+
+// MarkPaid marks the invoice of the order as paid in full, failing if it is already final.
+func (f *Fake) MarkPaid(orderID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	payment := f.findPayment("", orderID)
+	if payment == nil {
+		return notFound("/payment/info", "Payment", "", orderID)
+	}
+	f.expire(payment)
+	if payment.IsFinal {
+		return fmt.Errorf("cryptomusfake: payment of order %q is already final with status %s", orderID, payment.PaymentStatus)
+	}
+
+	payment.PaymentAmount, payment.MerchantAmount = payment.Amount, payment.Amount
+	payment.PayerAmount, payment.PayerCurrency = payment.Amount, payment.Currency
+	payment.TxId = fmt.Sprintf("fake-tx-%s", payment.UUID)
+	f.setPaymentStatus(payment, cryptomus.PaymentStatusPaid)
+
+	return nil
+}
+
+// MarkCanceled cancels the invoice of the order, as when it expires unpaid.
+func (f *Fake) MarkCanceled(orderID string) error {
+	return f.SetPaymentStatus(orderID, cryptomus.PaymentStatusCancel)
+}
+
+// setPaymentStatus moves the payment to the status.
+func (f *Fake) setPaymentStatus(payment *cryptomus.Payment, status cryptomus.PaymentStatus) {
+	payment.PaymentStatus, payment.Status = status, status
+	payment.IsFinal = status.IsFinal()
+	payment.UpdatedAt = cryptomus.NewCryptomusTime(f.now())
+}
+
+// expire cancels the payment if it expired before being paid, as the API does.
+func (f *Fake) expire(payment *cryptomus.Payment) {
+	if !payment.IsFinal && payment.PaymentStatus == cryptomus.PaymentStatusCheck && f.now().After(payment.ExpiredAt.Time) {
+		f.setPaymentStatus(payment, cryptomus.PaymentStatusCancel)
+	}
+}
+
+// findPayment returns the payment of the uuid, or the latest payment of the order.
+func (f *Fake) findPayment(uuid cryptomus.UUID, orderID string) *cryptomus.Payment {
+	for i := len(f.payments) - 1; i >= 0; i-- {
+		payment := f.payments[i]
+		if (uuid != "" && payment.UUID == uuid) || (uuid == "" && orderID != "" && payment.OrderID == orderID) {
+			return payment
+		}
+	}
+
+	return nil
+}
+
+func copyPayment(payment *cryptomus.Payment) *cryptomus.Payment {
+	c := *payment
+	if payment.AdditionalData != nil {
+		c.AdditionalData = append(cryptomus.AdditionalData{}, payment.AdditionalData...)
+	}
+
+	return &c
+}
@@ -0,0 +1,189 @@
+package cryptomusfake
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/backtrac3r/go-cryptomus"
+)
+
+// SetBalance sets the balance of the currency payouts are debited from. Payouts of currencies
+// without a balance are not limited.
+func (f *Fake) SetBalance(currency string, amount cryptomus.Amount) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.balances[strings.ToUpper(currency)] = amount.Decimal
+}
+
+// Balance returns the balance of the currency, and whether it was set.
+func (f *Fake) Balance(currency string) (cryptomus.Amount, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	balance, ok := f.balances[strings.ToUpper(currency)]
+
+	return cryptomus.AmountFromDecimal(balance), ok
+}
+
+// CreatePayout creates a payout in the process status, debiting the balance of the currency if it was set.
+// It fails with an error matching cryptomus.ErrInsufficientFunds if the balance is too low.
+func (f *Fake) CreatePayout(payoutReq *cryptomus.PayoutRequest) (*cryptomus.Payout, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.findPayout("", payoutReq.OrderID) != nil {
+		return nil, apiError("/payout", "The order id has already been taken.")
+	}
+
+	currency := strings.ToUpper(string(payoutReq.Currency))
+	balance, limited := f.balances[currency]
+	if limited {
+		if balance.LessThan(payoutReq.Amount.Decimal) {
+			return nil, apiError("/payout", "Insufficient funds on the balance")
+		}
+		balance = balance.Sub(payoutReq.Amount.Decimal)
+		f.balances[currency] = balance
+	}
+
+	payout := &cryptomus.Payout{
+		UUID:          f.newUUID(),
+		OrderID:       payoutReq.OrderID,
+		Amount:        payoutReq.Amount,
+		Currency:      string(payoutReq.Currency),
+		Network:       string(payoutReq.Network),
+		Address:       payoutReq.Address,
+		Status:        cryptomus.PayoutStatusProcess,
+		Balance:       cryptomus.AmountFromDecimal(balance),
+		PayerCurrency: string(payoutReq.Currency),
+		PayerAmount:   payoutReq.Amount,
+	}
+	f.payouts = append(f.payouts, payout)
+
+	c := *payout
+	return &c, nil
+}
+
+// CreatePayouts creates a payout for every request.
+func (f *Fake) CreatePayouts(payoutReqs []*cryptomus.PayoutRequest) ([]*cryptomus.Payout, error) {
+	return cryptomus.RunBatch(context.Background(), len(payoutReqs), nil, func(_ context.Context, i int) (*cryptomus.Payout, error) {
+		return f.CreatePayout(payoutReqs[i])
+	})
+}
+
+// GetPayoutInfo returns the payout of the uuid or order_id of the request.
+func (f *Fake) GetPayoutInfo(payoutInfoReq *cryptomus.PayoutInfoRequest) (*cryptomus.Payout, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	payout := f.findPayout(payoutInfoReq.PayoutUUID, payoutInfoReq.OrderID)
+	if payout == nil {
+		return nil, notFound("/payout/info", "Payout", payoutInfoReq.PayoutUUID, payoutInfoReq.OrderID)
+	}
+
+	c := *payout
+	return &c, nil
+}
+
+// GetPayoutInfos looks up the payout of every request.
+func (f *Fake) GetPayoutInfos(payoutInfoReqs []*cryptomus.PayoutInfoRequest) ([]*cryptomus.Payout, error) {
+	return cryptomus.RunBatch(context.Background(), len(payoutInfoReqs), nil, func(_ context.Context, i int) (*cryptomus.Payout, error) {
+		return f.GetPayoutInfo(payoutInfoReqs[i])
+	})
+}
+
+// GetPayoutHistory returns all the payouts, newest first, as a single page. Payouts have no creation time,
+// so the date range is ignored.
+func (f *Fake) GetPayoutHistory(dateFrom, dateTo time.Time) (*cryptomus.PayoutHistoryResponse, error) {
+	payouts := f.payoutHistory()
+
+	return &cryptomus.PayoutHistoryResponse{
+		Payouts:  payouts,
+		Paginate: &cryptomus.PayoutHistoryPaginate{Count: len(payouts), PerPage: len(payouts)},
+	}, nil
+}
+
+// PayoutHistory returns an iterator over all the payouts, newest first.
+func (f *Fake) PayoutHistory(ctx context.Context, opts *cryptomus.HistoryOptions) *cryptomus.Iterator[*cryptomus.Payout] {
+	return cryptomus.NewSliceIterator(ctx, f.payoutHistory())
+}
+
+// ListAllPayouts returns all the payouts, newest first.
+func (f *Fake) ListAllPayouts(ctx context.Context, historyOpts *cryptomus.HistoryOptions, opts *cryptomus.ListAllOptions) ([]*cryptomus.Payout, error) {
+	payouts := f.payoutHistory()
+	if opts != nil && opts.MaxItems > 0 && len(payouts) > opts.MaxItems {
+		return payouts[:opts.MaxItems], fmt.Errorf("%w: more than %d items", cryptomus.ErrListTruncated, opts.MaxItems)
+	}
+
+	return payouts, nil
+}
+
+// payoutHistory returns copies of the payouts, newest first.
+func (f *Fake) payoutHistory() []*cryptomus.Payout {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	payouts := make([]*cryptomus.Payout, 0, len(f.payouts))
+	for i := len(f.payouts) - 1; i >= 0; i-- {
+		c := *f.payouts[i]
+		payouts = append(payouts, &c)
+	}
+
+	return payouts
+}
+
+// GetPayoutServicesList returns the PayoutServices of the fake.
+func (f *Fake) GetPayoutServicesList() ([]*cryptomus.PayoutService, error) {
+	return f.PayoutServices, nil
+}
+
+// SetPayoutStatus moves the payout of the order to the status. The debited amount is credited back
+// to the balance when the payout fails or is canceled.
+func (f *Fake) SetPayoutStatus(orderID string, status cryptomus.PayoutStatus) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	payout := f.findPayout("", orderID)
+	if payout == nil {
+		return notFound("/payout/info", "Payout", "", orderID)
+	}
+	if payout.IsFinal {
+		return fmt.Errorf("cryptomusfake: payout of order %q is already final with status %s", orderID, payout.Status)
+	}
+
+	payout.Status, payout.IsFinal = status, status.IsFinal()
+	switch status {
+	case cryptomus.PayoutStatusPaid:
+		payout.TxId = fmt.Sprintf("fake-tx-%s", payout.UUID)
+	case cryptomus.PayoutStatusFail, cryptomus.PayoutStatusCancel, cryptomus.PayoutStatusSystemFail:
+		currency := strings.ToUpper(payout.Currency)
+		if balance, ok := f.balances[currency]; ok {
+			f.balances[currency] = balance.Add(payout.Amount.Decimal)
+		}
+	}
+
+	return nil
+}
+
+// MarkPayoutPaid marks the payout of the order as paid.
+func (f *Fake) MarkPayoutPaid(orderID string) error {
+	return f.SetPayoutStatus(orderID, cryptomus.PayoutStatusPaid)
+}
+
+// MarkPayoutFailed marks the payout of the order as failed, crediting the amount back to the balance.
+func (f *Fake) MarkPayoutFailed(orderID string) error {
+	return f.SetPayoutStatus(orderID, cryptomus.PayoutStatusFail)
+}
+
+// findPayout returns the payout of the uuid, or of the order.
+func (f *Fake) findPayout(uuid cryptomus.UUID, orderID string) *cryptomus.Payout {
+	for _, payout := range f.payouts {
+		if (uuid != "" && payout.UUID == uuid) || (uuid == "" && orderID != "" && payout.OrderID == orderID) {
+			return payout
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package cryptomusfake
+
+import (
+	"context"
+	"strings"
+
+	"github.com/backtrac3r/go-cryptomus"
+)
+
+// SetExchangeRate sets the course of the from/to pair, replacing the previous one.
+func (f *Fake) SetExchangeRate(from, to string, course cryptomus.Amount) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	rate := cryptomus.ExchangeRate{From: from, To: to, Course: course}
+	rates := f.rates[from]
+	for i := range rates {
+		if rates[i].To == to {
+			rates[i] = rate
+			return
+		}
+	}
+	f.rates[from] = append(rates, rate)
+}
+
+// ListExchangeRates returns the rates of the currency set with SetExchangeRate, filtered by the options,
+// failing with an error matching cryptomus.ErrNotFound if none was set.
+func (f *Fake) ListExchangeRates(ctx context.Context, currency string, opts *cryptomus.ExchangeRateOptions) ([]cryptomus.ExchangeRate, error) {
+	f.mu.Lock()
+	rates, ok := f.rates[strings.ToUpper(currency)]
+	rates = append([]cryptomus.ExchangeRate{}, rates...)
+	now := f.now()
+	f.mu.Unlock()
+
+	if !ok {
+		return nil, notFound("/exchange-rate/"+currency+"/list", "Currency", "", "")
+	}
+	for i := range rates {
+		rates[i].FetchedAt = now
+	}
+	if opts != nil && len(opts.To) > 0 {
+		rates = cryptomus.FilterExchangeRates(rates, opts.To...)
+	}
+
+	return rates, nil
+}
+
+// Convert converts the amount using the rates set with SetExchangeRate, resolving inverse and cross rates
+// like the client does.
+func (f *Fake) Convert(ctx context.Context, amount, from, to string) (*cryptomus.Conversion, error) {
+	return cryptomus.Convert(ctx, f, amount, from, to)
+}
@@ -0,0 +1,212 @@
+package cryptomusfake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/backtrac3r/go-cryptomus"
+)
+
+// CreateRecurrence creates a recurring payment waiting for the payer to accept it.
+func (f *Fake) CreateRecurrence(recReq *cryptomus.RecurrenceRequest) (*cryptomus.Recurrence, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if recReq.OrderID != "" {
+		if existing := f.findRecurrence("", recReq.OrderID); existing != nil && !existing.Status.IsFinal() {
+			return nil, apiError("/recurrence/create", "The order id has already been taken.")
+		}
+	}
+
+	recurrence := &cryptomus.Recurrence{
+		UUID:           f.newUUID(),
+		Name:           recReq.Name,
+		OrderID:        recReq.OrderID,
+		Amount:         recReq.Amount,
+		Currency:       string(recReq.Currency),
+		ToCurrency:     recReq.ToCurrency,
+		UrlCallback:    recReq.UrlCallback,
+		Period:         recReq.Period,
+		Status:         cryptomus.RecurrenceStatusWaitAccept,
+		AdditionalData: recReq.AdditionalData,
+	}
+	recurrence.Url = "https://pay.cryptomus.com/recurrence/" + string(recurrence.UUID)
+	if recReq.DiscountDays != nil {
+		recurrence.DiscountDays = *recReq.DiscountDays
+	}
+	if recReq.DiscountAmount != nil {
+		recurrence.DiscountAmount = *recReq.DiscountAmount
+	}
+	f.recurrences = append(f.recurrences, recurrence)
+
+	return copyRecurrence(recurrence), nil
+}
+
+// GetRecurrenceInfo returns the recurring payment of the uuid or order_id of the request.
+func (f *Fake) GetRecurrenceInfo(infoReq *cryptomus.RecurrenceInfoRequest) (*cryptomus.Recurrence, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	recurrence := f.findRecurrence(infoReq.UUID, infoReq.OrderID)
+	if recurrence == nil {
+		return nil, notFound("/recurrence/info", "Recurrence", infoReq.UUID, infoReq.OrderID)
+	}
+
+	return copyRecurrence(recurrence), nil
+}
+
+// ListRecurrences returns the recurring payments selected by the options, newest first, as a single page.
+// Options may be nil.
+func (f *Fake) ListRecurrences(opts *cryptomus.RecurrenceListOptions) (*cryptomus.RecurrenceListResponse, error) {
+	items := f.listRecurrences(opts)
+
+	return &cryptomus.RecurrenceListResponse{
+		Items:    items,
+		Paginate: &cryptomus.RecurrencePaginate{Count: len(items), PerPage: len(items)},
+	}, nil
+}
+
+// Recurrences returns an iterator over the recurring payments selected by the options, newest first.
+func (f *Fake) Recurrences(ctx context.Context, opts *cryptomus.RecurrenceListOptions) *cryptomus.Iterator[*cryptomus.Recurrence] {
+	return cryptomus.NewSliceIterator(ctx, f.listRecurrences(opts))
+}
+
+// ListAllRecurrences returns the recurring payments selected by the options, newest first.
+func (f *Fake) ListAllRecurrences(ctx context.Context, listOpts *cryptomus.RecurrenceListOptions, opts *cryptomus.ListAllOptions) ([]*cryptomus.Recurrence, error) {
+	items := f.listRecurrences(listOpts)
+	if opts != nil && opts.MaxItems > 0 && len(items) > opts.MaxItems {
+		return items[:opts.MaxItems], fmt.Errorf("%w: more than %d items", cryptomus.ErrListTruncated, opts.MaxItems)
+	}
+
+	return items, nil
+}
+
+// listRecurrences returns copies of the recurring payments selected by the options, newest first.
+func (f *Fake) listRecurrences(opts *cryptomus.RecurrenceListOptions) []*cryptomus.Recurrence {
+	if opts == nil {
+		opts = &cryptomus.RecurrenceListOptions{}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	items := []*cryptomus.Recurrence{}
+	for i := len(f.recurrences) - 1; i >= 0; i-- {
+		recurrence := f.recurrences[i]
+		if opts.Status != "" && recurrence.Status != opts.Status {
+			continue
+		}
+		if opts.Currency != "" && !strings.EqualFold(recurrence.Currency, opts.Currency) {
+			continue
+		}
+		items = append(items, copyRecurrence(recurrence))
+	}
+
+	return items
+}
+
+// CancelRecurrence cancels the recurring payment on behalf of the merchant.
+func (f *Fake) CancelRecurrence(cancelReq *cryptomus.RecurrenceCancelRequest) (*cryptomus.Recurrence, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	recurrence := f.findRecurrence(cancelReq.UUID, cancelReq.OrderID)
+	if recurrence == nil {
+		return nil, notFound("/recurrence/cancel", "Recurrence", cancelReq.UUID, cancelReq.OrderID)
+	}
+	if recurrence.Status.IsFinal() {
+		return nil, apiError("/recurrence/cancel", "Recurrence is already canceled")
+	}
+	recurrence.Status = cryptomus.RecurrenceStatusCancelByMerchant
+
+	return copyRecurrence(recurrence), nil
+}
+
+// ChangeRecurrencePlan cancels the recurring payment and creates a replacement with the new amount and period,
+// like the client does.
+func (f *Fake) ChangeRecurrencePlan(changeReq *cryptomus.RecurrencePlanChangeRequest) (*cryptomus.RecurrencePlanChange, error) {
+	if changeReq == nil {
+		return nil, errors.New("recurrence plan change request cannot be nil")
+	}
+	if changeReq.Amount == nil && changeReq.Period == "" {
+		return nil, errors.New("either amount or period must be provided")
+	}
+
+	old, err := f.CancelRecurrence(&cryptomus.RecurrenceCancelRequest{UUID: changeReq.UUID, OrderID: changeReq.OrderID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel current recurrence: %w", err)
+	}
+
+	recReq := &cryptomus.RecurrenceRequest{
+		Amount:         old.Amount,
+		Currency:       cryptomus.CurrencyCode(old.Currency),
+		Name:           old.Name,
+		Period:         old.Period,
+		ToCurrency:     old.ToCurrency,
+		OrderID:        old.OrderID,
+		UrlCallback:    old.UrlCallback,
+		AdditionalData: old.AdditionalData,
+	}
+	if changeReq.Amount != nil {
+		recReq.Amount = *changeReq.Amount
+	}
+	if changeReq.Period != "" {
+		recReq.Period = changeReq.Period
+	}
+	if old.DiscountDays > 0 {
+		recReq.DiscountDays = cryptomus.Ptr(old.DiscountDays)
+	}
+	if !old.DiscountAmount.IsZero() {
+		recReq.DiscountAmount = cryptomus.Ptr(old.DiscountAmount)
+	}
+
+	change := &cryptomus.RecurrencePlanChange{Old: old}
+	change.New, err = f.CreateRecurrence(recReq)
+	if err != nil {
+		return change, fmt.Errorf("recurrence %s was canceled but the replacement could not be created: %w", old.UUID, err)
+	}
+
+	return change, nil
+}
+
+// SetRecurrenceStatus moves the latest recurring payment of the order to the status.
+func (f *Fake) SetRecurrenceStatus(orderID string, status cryptomus.RecurrenceStatus) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	recurrence := f.findRecurrence("", orderID)
+	if recurrence == nil {
+		return notFound("/recurrence/info", "Recurrence", "", orderID)
+	}
+	recurrence.Status = status
+
+	return nil
+}
+
+// AcceptRecurrence activates the recurring payment of the order, as when the payer accepts it.
+func (f *Fake) AcceptRecurrence(orderID string) error {
+	return f.SetRecurrenceStatus(orderID, cryptomus.RecurrenceStatusActive)
+}
+
+// findRecurrence returns the recurring payment of the uuid, or the latest one of the order.
+func (f *Fake) findRecurrence(uuid cryptomus.UUID, orderID string) *cryptomus.Recurrence {
+	for i := len(f.recurrences) - 1; i >= 0; i-- {
+		recurrence := f.recurrences[i]
+		if (uuid != "" && recurrence.UUID == uuid) || (uuid == "" && orderID != "" && recurrence.OrderID == orderID) {
+			return recurrence
+		}
+	}
+
+	return nil
+}
+
+func copyRecurrence(recurrence *cryptomus.Recurrence) *cryptomus.Recurrence {
+	c := *recurrence
+	if recurrence.AdditionalData != nil {
+		c.AdditionalData = append(cryptomus.AdditionalData{}, recurrence.AdditionalData...)
+	}
+
+	return &c
+}
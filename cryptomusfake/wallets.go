@@ -0,0 +1,138 @@
+package cryptomusfake
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/backtrac3r/go-cryptomus"
+)
+
+// CreateStaticWallet creates an active static wallet. Like the API, it returns the existing wallet
+// of the order, currency and network if there is one.
+func (f *Fake) CreateStaticWallet(staticWalletReq *cryptomus.StaticWalletRequest) (*cryptomus.StaticWalletResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, w := range f.wallets {
+		if w.OrderID == staticWalletReq.OrderID && w.Currency == string(staticWalletReq.Currency) && w.Network == string(staticWalletReq.Network) {
+			response := w.StaticWalletResponse
+			return &response, nil
+		}
+	}
+
+	w := &wallet{status: cryptomus.WalletStatusActive}
+	w.OrderID = staticWalletReq.OrderID
+	w.Currency, w.Network = string(staticWalletReq.Currency), string(staticWalletReq.Network)
+	w.UUID, w.WalletUUID = f.newUUID(), f.newUUID()
+	w.Address = fmt.Sprintf("fake-%s-address-%d", staticWalletReq.Network, f.seq)
+	w.Url = "https://pay.cryptomus.com/wallet/" + string(w.UUID)
+	f.wallets = append(f.wallets, w)
+
+	response := w.StaticWalletResponse
+	return &response, nil
+}
+
+// CreateStaticWallets creates a static wallet for every request.
+func (f *Fake) CreateStaticWallets(staticWalletReqs []*cryptomus.StaticWalletRequest) ([]*cryptomus.StaticWalletResponse, error) {
+	return cryptomus.RunBatch(context.Background(), len(staticWalletReqs), nil, func(_ context.Context, i int) (*cryptomus.StaticWalletResponse, error) {
+		return f.CreateStaticWallet(staticWalletReqs[i])
+	})
+}
+
+// GenerateStaticWalletQRCode returns a placeholder QR code image of the wallet.
+func (f *Fake) GenerateStaticWalletQRCode(walletUUID cryptomus.UUID) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.findWallet(walletUUID, "") == nil {
+		return "", notFound("/wallet/qr", "Wallet", walletUUID, "")
+	}
+
+	return "data:image/png;base64,ZmFrZQ==", nil
+}
+
+// BlockAddress blocks the wallet, which rejects the deposits made afterwards.
+func (f *Fake) BlockAddress(blockAddressReq *cryptomus.BlockAddressRequest) (*cryptomus.BlockAddressResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w := f.findWallet(blockAddressReq.WalletUUID, blockAddressReq.OrderID)
+	if w == nil {
+		return nil, notFound("/wallet/block-address", "Wallet", blockAddressReq.WalletUUID, blockAddressReq.OrderID)
+	}
+	w.status = cryptomus.WalletStatusBlocked
+
+	return &cryptomus.BlockAddressResponse{WalletUUID: w.UUID, Status: w.status}, nil
+}
+
+// BlockedAddressRefund refunds the deposits of a blocked wallet, free of commission.
+func (f *Fake) BlockedAddressRefund(refundRequest *cryptomus.BlockedAddressRefundRequest) (*cryptomus.BlockedAddressRefundResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w := f.findWallet(refundRequest.WalletUUID, refundRequest.OrderID)
+	if w == nil {
+		return nil, notFound("/wallet/blocked-address-refund", "Wallet", refundRequest.WalletUUID, refundRequest.OrderID)
+	}
+	if w.status != cryptomus.WalletStatusBlocked {
+		return nil, apiError("/wallet/blocked-address-refund", "Wallet is not blocked")
+	}
+
+	total := cryptomus.MustAmount("0")
+	for _, payment := range f.payments {
+		if payment.Address == w.Address && payment.PaymentStatus.IsSuccessful() {
+			total = cryptomus.AmountFromDecimal(total.Add(payment.PaymentAmount.Decimal))
+			f.setPaymentStatus(payment, cryptomus.PaymentStatusRefundPaid)
+		}
+	}
+
+	return &cryptomus.BlockedAddressRefundResponse{Commision: cryptomus.MustAmount("0"), Amount: total}, nil
+}
+
+// Deposit simulates a deposit of the amount to the static wallet of the order, recorded as a paid payment
+// like the API does. It fails if the wallet is blocked.
+func (f *Fake) Deposit(orderID string, amount cryptomus.Amount) (*cryptomus.Payment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w := f.findWallet("", orderID)
+	if w == nil {
+		return nil, notFound("/wallet", "Wallet", "", orderID)
+	}
+	if w.status == cryptomus.WalletStatusBlocked {
+		return nil, fmt.Errorf("cryptomusfake: wallet of order %q is blocked", orderID)
+	}
+
+	now := cryptomus.NewCryptomusTime(f.now())
+	payment := &cryptomus.Payment{
+		UUID:           f.newUUID(),
+		OrderID:        orderID,
+		Amount:         amount,
+		PaymentAmount:  amount,
+		PayerAmount:    amount,
+		MerchantAmount: amount,
+		PayerCurrency:  w.Currency,
+		Currency:       w.Currency,
+		Network:        w.Network,
+		Address:        w.Address,
+		Url:            w.Url,
+		CreatedAt:      now,
+	}
+	payment.TxId = fmt.Sprintf("fake-tx-%s", payment.UUID)
+	f.setPaymentStatus(payment, cryptomus.PaymentStatusPaid)
+	f.payments = append(f.payments, payment)
+
+	return copyPayment(payment), nil
+}
+
+// findWallet returns the wallet of the uuid, or the latest wallet of the order.
+func (f *Fake) findWallet(uuid cryptomus.UUID, orderID string) *wallet {
+	for i := len(f.wallets) - 1; i >= 0; i-- {
+		w := f.wallets[i]
+		if (uuid != "" && (w.UUID == uuid || w.WalletUUID == uuid)) || (uuid == "" && orderID != "" && w.OrderID == orderID) {
+			return w
+		}
+	}
+
+	return nil
+}
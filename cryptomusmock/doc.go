@@ -0,0 +1,9 @@
+// Package cryptomusmock provides mocks of the client interfaces of the cryptomus package, generated with moq,
+// to test application code without HTTP:
+//
+//	api := &cryptomusmock.PaymentsAPIMock{
+//		CreateInvoiceFunc: func(invoiceReq *cryptomus.InvoiceRequest) (*cryptomus.Payment, error) {
+//			return &cryptomus.Payment{OrderID: invoiceReq.OrderID, PaymentStatus: cryptomus.PaymentStatusCheck}, nil
+//		},
+//	}
+package cryptomusmock
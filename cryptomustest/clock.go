@@ -0,0 +1,171 @@
+package cryptomustest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/backtrac3r/go-cryptomus"
+)
+
+// Clock is a fake cryptomus.Clock for tests. Its time only moves when advanced, firing the timers
+// and tickers that are due, so retries, caches and pollers can be tested without sleeping:
+//
+//	clock := cryptomustest.NewClock(time.Now())
+//	client := cryptomustest.NewClient(httpClient, cryptomus.WithClock(clock))
+//	go client.CreatePayout(payout) // rate-limited, waits for a retry
+//	clock.BlockUntil(1)
+//	clock.Advance(time.Second)
+//
+// It is safe for concurrent use.
+type Clock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters map[*clockWaiter]struct{}
+}
+
+var _ cryptomus.Clock = (*Clock)(nil)
+
+// clockWaiter is a timer, or a ticker if its period is set.
+type clockWaiter struct {
+	clock  *Clock
+	at     time.Time
+	period time.Duration
+	ch     chan time.Time
+}
+
+// NewClock returns a fake clock set to the time.
+func NewClock(now time.Time) *Clock {
+	c := &Clock{now: now, waiters: make(map[*clockWaiter]struct{})}
+	c.cond = sync.NewCond(&c.mu)
+
+	return c
+}
+
+// Now returns the time of the clock.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// NewTimer returns a timer firing once the clock is advanced by d.
+func (c *Clock) NewTimer(d time.Duration) cryptomus.Timer {
+	return c.add(d, 0)
+}
+
+// NewTicker returns a ticker ticking every time the clock is advanced by d. Like a *time.Ticker,
+// it drops the ticks a slow receiver misses.
+func (c *Clock) NewTicker(d time.Duration) cryptomus.Ticker {
+	if d <= 0 {
+		panic("cryptomustest: non-positive interval for NewTicker")
+	}
+
+	return clockTicker{c.add(d, d)}
+}
+
+// Advance moves the time of the clock forward by d, firing the timers and tickers that are due.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	c.fire()
+}
+
+// Set moves the clock to the time, firing the timers and tickers that are due.
+func (c *Clock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = now
+	c.fire()
+}
+
+// Waiters returns the number of pending timers and tickers.
+func (c *Clock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.waiters)
+}
+
+// BlockUntil waits until at least n timers and tickers are pending, e.g. until the code under test
+// started waiting for a retry, so that advancing the clock fires it.
+func (c *Clock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
+
+// add registers a waiter due in d, repeating with the period if it is positive.
+func (c *Clock) add(d, period time.Duration) *clockWaiter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w := &clockWaiter{clock: c, at: c.now.Add(d), period: period, ch: make(chan time.Time, 1)}
+	c.waiters[w] = struct{}{}
+	c.fire()
+	c.cond.Broadcast()
+
+	return w
+}
+
+// fire delivers the due waiters, removing the timers and rescheduling the tickers. c.mu must be held.
+func (c *Clock) fire() {
+	for w := range c.waiters {
+		if w.at.After(c.now) {
+			continue
+		}
+
+		select {
+		case w.ch <- c.now:
+		default:
+		}
+
+		if w.period <= 0 {
+			delete(c.waiters, w)
+			continue
+		}
+		for !w.at.After(c.now) {
+			w.at = w.at.Add(w.period)
+		}
+	}
+	c.cond.Broadcast()
+}
+
+// stop removes the waiter, reporting whether it was pending.
+func (w *clockWaiter) stop() bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+
+	_, ok := w.clock.waiters[w]
+	delete(w.clock.waiters, w)
+	w.clock.cond.Broadcast()
+
+	return ok
+}
+
+// C returns the channel the timer fires on.
+func (w *clockWaiter) C() <-chan time.Time {
+	return w.ch
+}
+
+// Stop prevents the timer from firing, reporting whether it was pending.
+func (w *clockWaiter) Stop() bool {
+	return w.stop()
+}
+
+// clockTicker adapts a waiter to cryptomus.Ticker.
+type clockTicker struct {
+	*clockWaiter
+}
+
+// Stop turns off the ticker.
+func (t clockTicker) Stop() {
+	t.clockWaiter.stop()
+}
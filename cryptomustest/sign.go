@@ -0,0 +1,108 @@
+// Package cryptomustest provides helpers to test code integrating with Cryptomus: deterministic credentials,
+// and signatures computed exactly like production does, for webhook handlers and mock servers.
+package cryptomustest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/backtrac3r/go-cryptomus"
+)
+
+// Deterministic credentials for tests.
+const (
+	Merchant   = "test-merchant"
+	PaymentKey = "test-payment-key"
+	PayoutKey  = "test-payout-key"
+)
+
+// NewClient returns a client with the test credentials.
+func NewClient(httpClient *http.Client, opts ...cryptomus.Option) *cryptomus.Cryptomus {
+	return cryptomus.New(httpClient, Merchant, PaymentKey, PayoutKey, opts...)
+}
+
+// Sign returns the signature of the body with the API key, as sent in the sign header of API requests.
+func Sign(t testing.TB, apiKey string, body []byte) string {
+	t.Helper()
+
+	sign, err := cryptomus.Sign(apiKey, body)
+	if err != nil {
+		t.Fatalf("cryptomustest: failed to sign body: %v", err)
+	}
+
+	return sign
+}
+
+// SignWebhook encodes the payload and signs it with the API key as Cryptomus does for callbacks, appending
+// the sign field. The payload is either a raw JSON body, as a string, []byte or json.RawMessage,
+// or a value encoded to JSON, e.g. a *cryptomus.PaymentWebhook.
+func SignWebhook(t testing.TB, apiKey string, payload interface{}) []byte {
+	t.Helper()
+
+	body, err := encode(payload)
+	if err != nil {
+		t.Fatalf("cryptomustest: %v", err)
+	}
+
+	signed, err := cryptomus.SignWebhook(apiKey, body)
+	if err != nil {
+		t.Fatalf("cryptomustest: failed to sign webhook: %v", err)
+	}
+
+	return signed
+}
+
+// WebhookRequest returns a callback request delivering the payload signed with the API key,
+// to be served to a webhook handler with an httptest.ResponseRecorder.
+func WebhookRequest(t testing.TB, apiKey string, payload interface{}) *http.Request {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/cryptomus/callback", bytes.NewReader(SignWebhook(t, apiKey, payload)))
+	req.Header.Set("Content-Type", "application/json")
+
+	return req
+}
+
+// VerifyRequest checks that the API request is signed with the API key in its sign header and comes from
+// the test merchant, as a mock of the API would. The body is returned and restored on the request.
+func VerifyRequest(r *http.Request, apiKey string) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	if merchant := r.Header.Get("merchant"); merchant != Merchant {
+		return nil, fmt.Errorf("%w: unknown merchant %q", cryptomus.ErrUnauthorized, merchant)
+	}
+	if err := cryptomus.VerifyDetached(apiKey, body, r.Header.Get("sign")); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// encode returns the payload as a JSON body.
+func encode(payload interface{}) ([]byte, error) {
+	switch payload := payload.(type) {
+	case string:
+		return []byte(payload), nil
+	case []byte:
+		return payload, nil
+	case json.RawMessage:
+		return payload, nil
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	return body, nil
+}
@@ -0,0 +1,282 @@
+package cryptomustest
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/backtrac3r/go-cryptomus"
+	"github.com/shopspring/decimal"
+)
+
+// WebhookFixture is a representative callback payload, signed with the test key Cryptomus would use for its type.
+type WebhookFixture struct {
+	// Name identifies the fixture as "<type>/<status>", e.g. "payment/paid", and is meant as a subtest name.
+	Name   string
+	Type   cryptomus.WebhookType
+	Status string
+	// Payload is the payload decoded from Body: a *cryptomus.PaymentWebhook, *cryptomus.PayoutWebhook,
+	// *cryptomus.WalletWebhook or *cryptomus.RecurrenceWebhook, with the Sign field set.
+	Payload interface{}
+	// Body is the signed JSON body, as delivered to the callback URL.
+	Body []byte
+}
+
+// Key returns the API key the fixture is signed with: PayoutKey for payouts, PaymentKey otherwise.
+func (f WebhookFixture) Key() string {
+	if f.Type == cryptomus.WebhookTypePayout {
+		return PayoutKey
+	}
+
+	return PaymentKey
+}
+
+// Statuses the fixtures are generated for.
+var (
+	paymentStatuses = []cryptomus.PaymentStatus{
+		cryptomus.PaymentStatusPaid, cryptomus.PaymentStatusPaidOver, cryptomus.PaymentStatusWrongAmount,
+		cryptomus.PaymentStatusProcess, cryptomus.PaymentStatusConfirmCheck, cryptomus.PaymentStatusWrongAmountWaiting,
+		cryptomus.PaymentStatusCheck, cryptomus.PaymentStatusFail, cryptomus.PaymentStatusCancel,
+		cryptomus.PaymentStatusSystemFail, cryptomus.PaymentStatusRefundProcess, cryptomus.PaymentStatusRefundFail,
+		cryptomus.PaymentStatusRefundPaid, cryptomus.PaymentStatusLocked,
+	}
+	payoutStatuses = []cryptomus.PayoutStatus{
+		cryptomus.PayoutStatusProcess, cryptomus.PayoutStatusCheck, cryptomus.PayoutStatusPaid,
+		cryptomus.PayoutStatusFail, cryptomus.PayoutStatusCancel, cryptomus.PayoutStatusSystemFail,
+	}
+	recurrenceStatuses = []cryptomus.RecurrenceStatus{
+		cryptomus.RecurrenceStatusWaitAccept, cryptomus.RecurrenceStatusActive,
+		cryptomus.RecurrenceStatusCancelByMerchant, cryptomus.RecurrenceStatusCancelByUser,
+	}
+)
+
+// Values shared by the fixtures, so that they are deterministic.
+const (
+	fixtureAddress     = "TDD97yguPESTpcrJMqU6h2ozZbibv4Vaqm"
+	fixturePayer       = "TXRbqBnMxgcGYbxHRmYUwUvymVtM5WbCGX"
+	fixtureWalletUUID  = "00000000-0000-4000-8000-00000000aaaa"
+	fixtureNetwork     = "tron"
+	fixtureCurrency    = "USDT"
+	fixtureRecurrence  = "Subscription"
+	fixtureTimeRFC3339 = "2024-05-01T12:00:00Z"
+)
+
+// Webhooks returns a fixture for every webhook type and status known to the SDK, in a stable order:
+// payments, wallet deposits, payouts and recurring payments, each by status as declared.
+func Webhooks(t testing.TB) []WebhookFixture {
+	t.Helper()
+
+	var fixtures []WebhookFixture
+	fixtures = append(fixtures, PaymentWebhooks(t)...)
+	fixtures = append(fixtures, WalletWebhooks(t)...)
+	fixtures = append(fixtures, PayoutWebhooks(t)...)
+	fixtures = append(fixtures, RecurrenceWebhooks(t)...)
+
+	return fixtures
+}
+
+// Webhook returns the fixture of the name, e.g. "payout/fail", failing the test if there is none.
+func Webhook(t testing.TB, name string) WebhookFixture {
+	t.Helper()
+
+	for _, fixture := range Webhooks(t) {
+		if fixture.Name == name {
+			return fixture
+		}
+	}
+	t.Fatalf("cryptomustest: no webhook fixture %q", name)
+
+	return WebhookFixture{}
+}
+
+// PaymentWebhooks returns an invoice callback for every payment status.
+func PaymentWebhooks(t testing.TB) []WebhookFixture {
+	t.Helper()
+
+	fixtures := make([]WebhookFixture, 0, len(paymentStatuses))
+	for i, status := range paymentStatuses {
+		amount := decimal.NewFromInt(10)
+		paid := paidAmount(status, amount)
+		fixtures = append(fixtures, newFixture(t, cryptomus.WebhookTypePayment, string(status), &cryptomus.PaymentWebhook{
+			Type:                    string(cryptomus.WebhookTypePayment),
+			UUID:                    fixtureUUID(1, i),
+			OrderID:                 "payment-" + string(status),
+			Amount:                  cryptomus.AmountFromDecimal(amount),
+			PaymentAmount:           cryptomus.AmountFromDecimal(paid),
+			PaymentAmountUSD:        cryptomus.AmountFromDecimal(paid),
+			PayerAmount:             cryptomus.AmountFromDecimal(paid),
+			PayerAmountExchangeRate: cryptomus.AmountFromDecimal(decimal.NewFromInt(1)),
+			MerchantAmount:          cryptomus.AmountFromDecimal(merchantAmount(paid)),
+			Commission:              cryptomus.AmountFromDecimal(commission(paid)),
+			Discount:                cryptomus.AmountFromDecimal(decimal.Zero),
+			IsFinal:                 status.IsFinal(),
+			Status:                  status,
+			From:                    fixturePayer,
+			Network:                 fixtureNetwork,
+			Currency:                fixtureCurrency,
+			PayerCurrency:           fixtureCurrency,
+			TxId:                    fixtureTxID(1, i),
+			CreatedAt:               fixtureTime(),
+			UpdatedAt:               fixtureTime(),
+		}, &cryptomus.PaymentWebhook{}))
+	}
+
+	return fixtures
+}
+
+// WalletWebhooks returns a static wallet deposit callback for every payment status.
+func WalletWebhooks(t testing.TB) []WebhookFixture {
+	t.Helper()
+
+	fixtures := make([]WebhookFixture, 0, len(paymentStatuses))
+	for i, status := range paymentStatuses {
+		amount := decimal.NewFromInt(25)
+		fixtures = append(fixtures, newFixture(t, cryptomus.WebhookTypeWallet, string(status), &cryptomus.WalletWebhook{
+			Type:              string(cryptomus.WebhookTypeWallet),
+			UUID:              fixtureUUID(2, i),
+			OrderID:           "wallet-1",
+			WalletAddressUUID: fixtureWalletUUID,
+			Address:           fixtureAddress,
+			From:              fixturePayer,
+			Amount:            cryptomus.AmountFromDecimal(amount),
+			PaymentAmount:     cryptomus.AmountFromDecimal(amount),
+			PaymentAmountUSD:  cryptomus.AmountFromDecimal(amount),
+			MerchantAmount:    cryptomus.AmountFromDecimal(merchantAmount(amount)),
+			Commission:        cryptomus.AmountFromDecimal(commission(amount)),
+			IsFinal:           status.IsFinal(),
+			Status:            status,
+			Network:           fixtureNetwork,
+			Currency:          fixtureCurrency,
+			PayerCurrency:     fixtureCurrency,
+			TxId:              fixtureTxID(2, i),
+			CreatedAt:         fixtureTime(),
+			UpdatedAt:         fixtureTime(),
+		}, &cryptomus.WalletWebhook{}))
+	}
+
+	return fixtures
+}
+
+// PayoutWebhooks returns a payout callback for every payout status.
+func PayoutWebhooks(t testing.TB) []WebhookFixture {
+	t.Helper()
+
+	fixtures := make([]WebhookFixture, 0, len(payoutStatuses))
+	for i, status := range payoutStatuses {
+		amount := decimal.NewFromInt(10)
+		fixtures = append(fixtures, newFixture(t, cryptomus.WebhookTypePayout, string(status), &cryptomus.PayoutWebhook{
+			Type:           string(cryptomus.WebhookTypePayout),
+			UUID:           fixtureUUID(3, i),
+			OrderID:        "payout-" + string(status),
+			Amount:         cryptomus.AmountFromDecimal(amount),
+			MerchantAmount: cryptomus.AmountFromDecimal(amount.Add(decimal.NewFromInt(1))),
+			Commission:     cryptomus.AmountFromDecimal(decimal.NewFromInt(1)),
+			IsFinal:        status.IsFinal(),
+			Status:         status,
+			TxId:           fixtureTxID(3, i),
+			Address:        fixtureAddress,
+			Currency:       fixtureCurrency,
+			Network:        fixtureNetwork,
+			PayerCurrency:  fixtureCurrency,
+			PayerAmount:    cryptomus.AmountFromDecimal(amount),
+			Balance:        cryptomus.AmountFromDecimal(decimal.NewFromInt(100)),
+			CreatedAt:      fixtureTime(),
+			UpdatedAt:      fixtureTime(),
+		}, &cryptomus.PayoutWebhook{}))
+	}
+
+	return fixtures
+}
+
+// RecurrenceWebhooks returns a recurring payment callback for every recurring payment status.
+func RecurrenceWebhooks(t testing.TB) []WebhookFixture {
+	t.Helper()
+
+	fixtures := make([]WebhookFixture, 0, len(recurrenceStatuses))
+	for i, status := range recurrenceStatuses {
+		amount := decimal.NewFromInt(5)
+		webhook := &cryptomus.RecurrenceWebhook{
+			Type:           string(cryptomus.WebhookTypeRecurrence),
+			UUID:           fixtureUUID(4, i),
+			OrderID:        "recurrence-" + string(status),
+			Name:           fixtureRecurrence,
+			Amount:         cryptomus.AmountFromDecimal(amount),
+			Currency:       "USD",
+			PayerCurrency:  fixtureCurrency,
+			PayerAmount:    cryptomus.AmountFromDecimal(amount),
+			PayerAmountUSD: cryptomus.AmountFromDecimal(amount),
+			Period:         "monthly",
+			Status:         status,
+			IsFinal:        status.IsFinal(),
+			DiscountAmount: cryptomus.AmountFromDecimal(decimal.Zero),
+		}
+		if status != cryptomus.RecurrenceStatusWaitAccept {
+			webhook.TxId = fixtureTxID(4, i)
+			webhook.LastPayOff = fixtureTime()
+		}
+		fixtures = append(fixtures, newFixture(t, cryptomus.WebhookTypeRecurrence, string(status), webhook, &cryptomus.RecurrenceWebhook{}))
+	}
+
+	return fixtures
+}
+
+// newFixture signs the payload with the key of the type and decodes the signed body into decoded.
+func newFixture(t testing.TB, typ cryptomus.WebhookType, status string, payload, decoded interface{}) WebhookFixture {
+	t.Helper()
+
+	fixture := WebhookFixture{
+		Name:   fmt.Sprintf("%s/%s", typ, status),
+		Type:   typ,
+		Status: status,
+	}
+	fixture.Body = SignWebhook(t, fixture.Key(), payload)
+	if err := json.Unmarshal(fixture.Body, decoded); err != nil {
+		t.Fatalf("cryptomustest: failed to decode webhook fixture %s: %v", fixture.Name, err)
+	}
+	fixture.Payload = decoded
+
+	return fixture
+}
+
+// paidAmount returns the amount paid for an invoice of the amount, given its status.
+func paidAmount(status cryptomus.PaymentStatus, amount decimal.Decimal) decimal.Decimal {
+	switch status {
+	case cryptomus.PaymentStatusPaidOver:
+		return amount.Add(decimal.NewFromInt(2))
+	case cryptomus.PaymentStatusWrongAmount, cryptomus.PaymentStatusWrongAmountWaiting:
+		return amount.Sub(decimal.NewFromInt(3))
+	case cryptomus.PaymentStatusCancel, cryptomus.PaymentStatusFail, cryptomus.PaymentStatusSystemFail:
+		return decimal.Zero
+	default:
+		return amount
+	}
+}
+
+// commission returns the 2% commission charged on the amount.
+func commission(amount decimal.Decimal) decimal.Decimal {
+	return amount.Mul(decimal.RequireFromString("0.02"))
+}
+
+// merchantAmount returns the amount credited to the merchant, net of the commission.
+func merchantAmount(amount decimal.Decimal) decimal.Decimal {
+	return amount.Sub(commission(amount))
+}
+
+// fixtureUUID returns the deterministic UUID of the i-th fixture of a kind.
+func fixtureUUID(kind, i int) cryptomus.UUID {
+	return cryptomus.UUID(fmt.Sprintf("00000000-0000-4000-8000-%06d%06d", kind, i+1))
+}
+
+// fixtureTxID returns the deterministic transaction hash of the i-th fixture of a kind.
+func fixtureTxID(kind, i int) string {
+	return fmt.Sprintf("%032x%032x", kind, i+1)
+}
+
+// fixtureTime returns the time of the fixtures.
+func fixtureTime() *cryptomus.CryptomusTime {
+	at, _ := time.Parse(time.RFC3339, fixtureTimeRFC3339)
+	value := cryptomus.NewCryptomusTime(at)
+
+	return &value
+}
@@ -0,0 +1,100 @@
+package cryptomus
+
+import (
+	"strings"
+)
+
+// CurrencyCode is the code of a cryptocurrency or fiat currency, e.g. "USDT" or "USD".
+// Codes not listed below are valid too, as Cryptomus keeps adding currencies.
+type CurrencyCode string
+
+// Cryptocurrencies supported by Cryptomus.
+const (
+	CurrencyUSDT  CurrencyCode = "USDT"
+	CurrencyUSDC  CurrencyCode = "USDC"
+	CurrencyDAI   CurrencyCode = "DAI"
+	CurrencyBTC   CurrencyCode = "BTC"
+	CurrencyETH   CurrencyCode = "ETH"
+	CurrencyLTC   CurrencyCode = "LTC"
+	CurrencyBCH   CurrencyCode = "BCH"
+	CurrencyDASH  CurrencyCode = "DASH"
+	CurrencyDOGE  CurrencyCode = "DOGE"
+	CurrencyTRX   CurrencyCode = "TRX"
+	CurrencyTON   CurrencyCode = "TON"
+	CurrencyBNB   CurrencyCode = "BNB"
+	CurrencySOL   CurrencyCode = "SOL"
+	CurrencyXMR   CurrencyCode = "XMR"
+	CurrencyPOL   CurrencyCode = "POL"
+	CurrencyMATIC CurrencyCode = "MATIC"
+	CurrencyAVAX  CurrencyCode = "AVAX"
+	CurrencySHIB  CurrencyCode = "SHIB"
+	CurrencyCGPT  CurrencyCode = "CGPT"
+	CurrencyVERSE CurrencyCode = "VERSE"
+	CurrencyHMSTR CurrencyCode = "HMSTR"
+	CurrencyDOGS  CurrencyCode = "DOGS"
+	CurrencyNOT   CurrencyCode = "NOT"
+)
+
+// Fiat currencies supported by Cryptomus for invoices and exchange rates.
+const (
+	CurrencyUSD CurrencyCode = "USD"
+	CurrencyEUR CurrencyCode = "EUR"
+	CurrencyGBP CurrencyCode = "GBP"
+	CurrencyCHF CurrencyCode = "CHF"
+	CurrencyCAD CurrencyCode = "CAD"
+	CurrencyAUD CurrencyCode = "AUD"
+	CurrencyJPY CurrencyCode = "JPY"
+	CurrencyCNY CurrencyCode = "CNY"
+	CurrencyINR CurrencyCode = "INR"
+	CurrencyBRL CurrencyCode = "BRL"
+	CurrencyTRY CurrencyCode = "TRY"
+	CurrencyRUB CurrencyCode = "RUB"
+	CurrencyUAH CurrencyCode = "UAH"
+	CurrencyKZT CurrencyCode = "KZT"
+	CurrencyPLN CurrencyCode = "PLN"
+	CurrencyAED CurrencyCode = "AED"
+)
+
+// cryptoCurrencies lists the cryptocurrencies known to the SDK.
+var cryptoCurrencies = map[CurrencyCode]bool{
+	CurrencyUSDT: true, CurrencyUSDC: true, CurrencyDAI: true, CurrencyBTC: true, CurrencyETH: true,
+	CurrencyLTC: true, CurrencyBCH: true, CurrencyDASH: true, CurrencyDOGE: true, CurrencyTRX: true,
+	CurrencyTON: true, CurrencyBNB: true, CurrencySOL: true, CurrencyXMR: true, CurrencyPOL: true,
+	CurrencyMATIC: true, CurrencyAVAX: true, CurrencySHIB: true, CurrencyCGPT: true, CurrencyVERSE: true,
+	CurrencyHMSTR: true, CurrencyDOGS: true, CurrencyNOT: true,
+}
+
+// Normalize returns the code trimmed and upper-cased.
+func (c CurrencyCode) Normalize() CurrencyCode {
+	return CurrencyCode(strings.ToUpper(strings.TrimSpace(string(c))))
+}
+
+// IsFiat reports whether the code is a known fiat currency.
+func (c CurrencyCode) IsFiat() bool {
+	return fiatCurrencies[string(c.Normalize())]
+}
+
+// IsCrypto reports whether the code is a known cryptocurrency.
+func (c CurrencyCode) IsCrypto() bool {
+	return cryptoCurrencies[c.Normalize()]
+}
+
+// IsKnown reports whether the code is a currency known to the SDK.
+func (c CurrencyCode) IsKnown() bool {
+	return c.IsFiat() || c.IsCrypto()
+}
+
+// IsValid reports whether the code is well-formed: 2 to 10 latin letters or digits.
+func (c CurrencyCode) IsValid() bool {
+	code := strings.TrimSpace(string(c))
+	if len(code) < 2 || len(code) > 10 {
+		return false
+	}
+	for _, r := range code {
+		if (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,33 @@
+package cryptomus
+
+import "time"
+
+// DateWindow is a date range of a history listing.
+type DateWindow struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// SplitDateRange splits the date range into consecutive windows of at most the given length, oldest first,
+// for listings whose range is longer than the API accepts. A zero end means now.
+// The range is returned as a single window if its start is zero or the length isn't positive.
+func SplitDateRange(from, to time.Time, window time.Duration) []DateWindow {
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() || window <= 0 || !to.After(from) {
+		return []DateWindow{{From: from, To: to}}
+	}
+
+	var windows []DateWindow
+	for start := from; !start.After(to); start = start.Add(window) {
+		// The API dates are precise to the second and both ends are inclusive
+		end := start.Add(window - time.Second)
+		if end.After(to) {
+			end = to
+		}
+		windows = append(windows, DateWindow{From: start, To: end})
+	}
+
+	return windows
+}
@@ -0,0 +1,34 @@
+package cryptomus
+
+// EmptyBody defines what a signed request without payload sends and signs.
+type EmptyBody int
+
+const (
+	// EmptyBodyNone sends no body and signs the empty string, i.e. md5(base64("") + key) = md5(key).
+	// It is the default.
+	EmptyBodyNone EmptyBody = iota
+	// EmptyBodyObject sends and signs "{}".
+	EmptyBodyObject
+	// EmptyBodyArray sends and signs "[]", which is what the reference PHP implementation produces
+	// for json_encode([]).
+	EmptyBodyArray
+)
+
+// WithEmptyBody sets what signed requests without payload send and sign.
+func WithEmptyBody(mode EmptyBody) Option {
+	return func(c *Cryptomus) {
+		c.emptyBody = mode
+	}
+}
+
+// bytes returns the body sent for the mode, nil for no body.
+func (m EmptyBody) bytes() []byte {
+	switch m {
+	case EmptyBodyObject:
+		return []byte("{}")
+	case EmptyBodyArray:
+		return []byte("[]")
+	default:
+		return nil
+	}
+}
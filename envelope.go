@@ -0,0 +1,46 @@
+package cryptomus
+
+import (
+	"context"
+	"net/http"
+)
+
+// result is the envelope the API wraps the result of every endpoint in.
+type result[T any] struct {
+	State  int `json:"state"`
+	Result T   `json:"result"`
+}
+
+// decodeResponse reads the response and returns the result it carries, failing like decodeInto
+// on a non-200 status, a non-zero state or a malformed body.
+func decodeResponse[T any](res *http.Response) (T, error) {
+	envelope := &result[T]{}
+	if err := decodeInto(res, envelope); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return envelope.Result, nil
+}
+
+// call performs the request through the common pipeline and decodes the result of the response.
+func call[T any](ctx context.Context, c *Cryptomus, method, endpoint string, payload interface{}, auth RequestAuth) (T, error) {
+	res, err := c.do(ctx, method, endpoint, payload, auth)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	defer res.Body.Close()
+
+	return decodeResponse[T](res)
+}
+
+// post performs a POST request signed with the payment key and decodes the result of the response.
+func post[T any](c *Cryptomus, endpoint string, payload interface{}) (T, error) {
+	return call[T](context.Background(), c, http.MethodPost, endpoint, payload, AuthPayment)
+}
+
+// postPayout performs a POST request signed with the payout key and decodes the result of the response.
+func postPayout[T any](c *Cryptomus, endpoint string, payload interface{}) (T, error) {
+	return call[T](context.Background(), c, http.MethodPost, endpoint, payload, AuthPayout)
+}
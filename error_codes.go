@@ -0,0 +1,78 @@
+package cryptomus
+
+import "strings"
+
+// ErrorCode identifies a known failure mode of the API, recognized from the messages it responds with.
+type ErrorCode string
+
+// Known error codes.
+const (
+	CodeUnknown              ErrorCode = ""
+	CodeValidation           ErrorCode = "validation"
+	CodeUnauthorized         ErrorCode = "unauthorized"
+	CodeInvalidSignature     ErrorCode = "invalid_signature"
+	CodeRateLimited          ErrorCode = "rate_limited"
+	CodeMerchantNotFound     ErrorCode = "merchant_not_found"
+	CodeMerchantBlocked      ErrorCode = "merchant_blocked"
+	CodePaymentNotFound      ErrorCode = "payment_not_found"
+	CodePayoutNotFound       ErrorCode = "payout_not_found"
+	CodeWalletNotFound       ErrorCode = "wallet_not_found"
+	CodeRecurrenceNotFound   ErrorCode = "recurrence_not_found"
+	CodeCurrencyNotSupported ErrorCode = "currency_not_supported"
+	CodeNetworkNotSupported  ErrorCode = "network_not_supported"
+	CodeMinAmountNotReached  ErrorCode = "min_amount_not_reached"
+	CodeMaxAmountExceeded    ErrorCode = "max_amount_exceeded"
+	CodeInsufficientFunds    ErrorCode = "insufficient_funds"
+	CodeDuplicateOrderID     ErrorCode = "duplicate_order_id"
+	CodeAlreadyPaid          ErrorCode = "already_paid"
+)
+
+// errorCatalog maps the lowercase fragments of known API messages to their codes.
+// Entries are matched in order, so the specific ones come before the generic ones.
+var errorCatalog = []struct {
+	code      ErrorCode
+	fragments []string
+}{
+	{CodeInvalidSignature, []string{"invalid sign", "wrong sign"}},
+	{CodeRateLimited, []string{"too many"}},
+	{CodeMerchantBlocked, []string{"merchant is blocked", "merchant blocked"}},
+	{CodeMerchantNotFound, []string{"merchant not found"}},
+	{CodePaymentNotFound, []string{"payment not found", "payment was not found", "invoice not found"}},
+	{CodePayoutNotFound, []string{"payout not found", "payout was not found"}},
+	{CodeWalletNotFound, []string{"wallet not found", "wallet was not found", "address not found"}},
+	{CodeRecurrenceNotFound, []string{"recurrence not found", "recurring payment not found"}},
+	{CodeCurrencyNotSupported, []string{"currency not found", "currency is not supported", "unsupported currency"}},
+	{CodeNetworkNotSupported, []string{"network not found", "network is not supported", "unsupported network"}},
+	{CodeMinAmountNotReached, []string{"minimum amount", "less than the minimum", "amount is too small"}},
+	{CodeMaxAmountExceeded, []string{"maximum amount", "greater than the maximum", "amount is too large"}},
+	{CodeInsufficientFunds, []string{"insufficient funds", "not enough funds", "not enough money"}},
+	{CodeDuplicateOrderID, []string{"order id has already been taken", "order_id has already been taken", "order already exists"}},
+	{CodeAlreadyPaid, []string{"already paid"}},
+	{CodeUnauthorized, []string{"unauthorized", "unauthenticated"}},
+}
+
+// Code returns the code of the failure, recognized from the message and validation errors of the response.
+// It returns CodeValidation for validation errors matching no specific code, and CodeUnknown otherwise.
+func (e *APIError) Code() ErrorCode {
+	messages := []string{e.Message}
+	for _, fieldErrors := range e.Errors {
+		messages = append(messages, fieldErrors...)
+	}
+
+	for _, entry := range errorCatalog {
+		for _, message := range messages {
+			message = strings.ToLower(message)
+			for _, fragment := range entry.fragments {
+				if strings.Contains(message, fragment) {
+					return entry.code
+				}
+			}
+		}
+	}
+
+	if len(e.Errors) > 0 {
+		return CodeValidation
+	}
+
+	return CodeUnknown
+}
@@ -0,0 +1,28 @@
+package cryptomus
+
+import (
+	"context"
+	"time"
+)
+
+// CallInfo describes the API call an error hook is invoked for.
+type CallInfo struct {
+	Method   string        // HTTP method of the request
+	Endpoint string        // Path of the request, e.g. "/v1/payment"
+	OrderID  string        // order_id the request referred to, if any
+	UUID     string        // uuid the request referred to, if any
+	Duration time.Duration // Time from the start of the call to the failure, retries included
+}
+
+// ErrorHook is invoked with the error an API call failed with: a *TransportError,
+// *DecodeError, *APIError or *RateLimitError, or a *CredentialError, wrapped with the call context.
+type ErrorHook func(ctx context.Context, err error, call CallInfo)
+
+// WithOnError sets a hook invoked once for every failed API call, so failures can be piped into
+// alerting or error reporting without wrapping every call. The hook is invoked synchronously on the
+// calling goroutine, before the error is returned, and must be safe for concurrent use.
+func WithOnError(hook ErrorHook) Option {
+	return func(c *Cryptomus) {
+		c.onError = hook
+	}
+}
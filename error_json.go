@@ -0,0 +1,63 @@
+package cryptomus
+
+import (
+	"encoding/json"
+)
+
+// apiErrorJSON is the stable JSON representation of an APIError.
+type apiErrorJSON struct {
+	Error      string           `json:"error"`
+	Code       ErrorCode        `json:"code,omitempty"`
+	State      int              `json:"state"`
+	StatusCode int              `json:"status_code"`
+	Message    string           `json:"message,omitempty"`
+	Errors     ValidationErrors `json:"errors,omitempty"`
+	Method     string           `json:"method,omitempty"`
+	Endpoint   string           `json:"endpoint,omitempty"`
+	OrderID    string           `json:"order_id,omitempty"`
+	UUID       string           `json:"uuid,omitempty"`
+}
+
+// MarshalJSON encodes the error as an object with snake_case fields, so it can be logged into
+// structured pipelines. The raw response body is left out.
+func (e *APIError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.jsonValue())
+}
+
+func (e *APIError) jsonValue() apiErrorJSON {
+	return apiErrorJSON{
+		Error:      e.Error(),
+		Code:       e.Code(),
+		State:      e.State,
+		StatusCode: e.StatusCode,
+		Message:    e.Message,
+		Errors:     e.Errors,
+		Method:     e.Method,
+		Endpoint:   e.Endpoint,
+		OrderID:    e.OrderID,
+		UUID:       e.UUID,
+	}
+}
+
+// MarshalJSON encodes the error like an APIError, adding the wait asked for in seconds.
+func (e *RateLimitError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		apiErrorJSON
+		Error             string  `json:"error"`
+		RetryAfterSeconds float64 `json:"retry_after_seconds"`
+	}{
+		apiErrorJSON:      e.APIError.jsonValue(),
+		Error:             e.Error(),
+		RetryAfterSeconds: e.RetryAfter.Seconds(),
+	})
+}
+
+// MarshalJSON encodes the errors as an object of message lists keyed by field, sorted by field.
+// A nil value is encoded as an empty object.
+func (v ValidationErrors) MarshalJSON() ([]byte, error) {
+	if v == nil {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(map[string][]string(v))
+}
@@ -0,0 +1,380 @@
+package cryptomus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Sentinel errors the failures are classified into, to be tested with errors.Is.
+// An *APIError matches the sentinels its status code and message map to.
+var (
+	ErrNotFound          = errors.New("not found")
+	ErrUnauthorized      = errors.New("unauthorized")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrInvalidSignature  = errors.New("invalid signature")
+	ErrRateLimited       = errors.New("rate limited")
+)
+
+// TransportError is returned when the request could not be sent or its response not received,
+// e.g. on a connection failure or a timeout. The API didn't reject the request, so it is safe to retry;
+// payouts and payments should be retried with the same order_id, as the request may have reached the API.
+type TransportError struct {
+	Err error
+}
+
+func (e *TransportError) Error() string {
+	return "HTTP request failed: " + e.Err.Error()
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeError is returned when a successful response could not be decoded. The API accepted the
+// request, so it must not be blindly retried.
+type DecodeError struct {
+	Err  error
+	Body []byte // Raw response body, truncated to 64 KiB
+}
+
+func (e *DecodeError) Error() string {
+	return "failed to decode response: " + e.Err.Error()
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// IsRetryable reports whether the request that failed with err can be retried: the error is
+// a *TransportError, or a *RateLimitError as a rate-limited request has not been processed.
+// An *APIError means the API rejected the request, and a *DecodeError that it accepted it.
+func IsRetryable(err error) bool {
+	var transportErr *TransportError
+	var rateErr *RateLimitError
+	return errors.As(err, &transportErr) || errors.As(err, &rateErr)
+}
+
+// maxErrorBodySize limits the size of the raw body kept by an APIError.
+const maxErrorBodySize = 64 << 10
+
+// APIError is returned when the API responds with a non-200 status or a non-zero state.
+type APIError struct {
+	State      int              // State of the response, non-zero on errors
+	StatusCode int              // HTTP status code of the response
+	Message    string           // Message field of the response, if any
+	Errors     ValidationErrors // Validation errors per request field, if any
+	Method     string           // HTTP method of the request
+	Endpoint   string           // Path of the request, e.g. "/v1/payment"
+	OrderID    string           // order_id the request referred to, if any
+	UUID       string           // uuid the request referred to, if any
+	Body       []byte           // Raw response body, truncated to 64 KiB
+
+	err error // Cause, if any
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	info := requestInfo{Method: e.Method, Endpoint: e.Endpoint, OrderID: e.OrderID, UUID: e.UUID}
+	fmt.Fprintf(&b, "cryptomus: %s: state %d, HTTP %d", info.String(), e.State, e.StatusCode)
+	if e.Message != "" {
+		fmt.Fprintf(&b, ": %s", e.Message)
+	}
+	if len(e.Errors) > 0 {
+		fmt.Fprintf(&b, ": %s", e.Errors)
+	}
+
+	return b.String()
+}
+
+// Unwrap returns the cause of the error, if any. The validation errors of the response are
+// its cause, so they can be extracted with errors.As.
+func (e *APIError) Unwrap() error {
+	return e.err
+}
+
+// Is reports whether the error maps to the target sentinel error.
+func (e *APIError) Is(target error) bool {
+	for _, sentinel := range e.sentinels() {
+		if target == sentinel {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sentinels returns the sentinel errors the status code and message of the error map to.
+func (e *APIError) sentinels() []error {
+	var sentinels []error
+	message := strings.ToLower(e.Message)
+
+	switch {
+	case e.StatusCode == http.StatusNotFound || strings.Contains(message, "not found"):
+		sentinels = append(sentinels, ErrNotFound)
+	case e.StatusCode == http.StatusTooManyRequests || strings.Contains(message, "too many"):
+		sentinels = append(sentinels, ErrRateLimited)
+	case strings.Contains(message, "insufficient funds") || strings.Contains(message, "not enough"):
+		sentinels = append(sentinels, ErrInsufficientFunds)
+	}
+
+	if strings.Contains(message, "sign") && (strings.Contains(message, "invalid") || strings.Contains(message, "wrong")) {
+		sentinels = append(sentinels, ErrInvalidSignature, ErrUnauthorized)
+	} else if e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden ||
+		strings.Contains(message, "unauthorized") || strings.Contains(message, "unauthenticated") {
+		sentinels = append(sentinels, ErrUnauthorized)
+	}
+
+	return sentinels
+}
+
+// apiEnvelope holds the fields common to every API response.
+// Some failures carry their message in an "error" field instead of "message".
+type apiEnvelope struct {
+	State   int             `json:"state"`
+	Message string          `json:"message"`
+	Error   string          `json:"error"`
+	Errors  json.RawMessage `json:"errors"`
+}
+
+// WithStrictDecoding makes the client reject responses with fields unknown to the SDK with a *DecodeError,
+// so integration environments catch changes of the API early. Production should keep the lenient default.
+func WithStrictDecoding() Option {
+	return func(c *Cryptomus) {
+		c.strictDecoding = true
+	}
+}
+
+// decodeInto reads the response and decodes it into v, the raw response of the endpoint,
+// returning an *APIError if the API responded with a non-200 status or a non-zero state,
+// a *RateLimitError wrapping it if the request was rate-limited, or a *DecodeError if the result is malformed.
+func decodeInto(res *http.Response, v interface{}) error {
+	info := responseRequestInfo(res)
+
+	// The body is read into a pooled buffer; the errors keep a copy of it
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if _, err := buf.ReadFrom(res.Body); err != nil {
+		return info.fail(&TransportError{Err: fmt.Errorf("failed to read response: %w", err)})
+	}
+	body := buf.Bytes()
+
+	envelope := apiEnvelope{}
+	envelopeErr := json.Unmarshal(body, &envelope)
+	if res.StatusCode == http.StatusTooManyRequests {
+		return info.report(&RateLimitError{
+			APIError:   newAPIError(res, body, envelope),
+			RetryAfter: parseRetryAfter(res.Header, clockOrSystem(info.clock).Now()),
+		})
+	}
+	if res.StatusCode != http.StatusOK || (envelopeErr == nil && envelope.State != 0) {
+		return info.report(newAPIError(res, body, envelope))
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	if info.strict {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(v); err != nil {
+		if len(body) > maxErrorBodySize {
+			body = body[:maxErrorBodySize]
+		}
+		return info.fail(&DecodeError{Err: err, Body: bytes.Clone(body)})
+	}
+
+	return nil
+}
+
+// newAPIError builds the error describing the failed response.
+func newAPIError(res *http.Response, body []byte, envelope apiEnvelope) *APIError {
+	apiErr := &APIError{
+		State:      envelope.State,
+		StatusCode: res.StatusCode,
+		Message:    envelope.Message,
+		Errors:     decodeValidationErrors(envelope.Errors),
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = envelope.Error
+	}
+	if len(apiErr.Errors) > 0 {
+		apiErr.err = apiErr.Errors
+	}
+	info := responseRequestInfo(res)
+	apiErr.Method, apiErr.Endpoint = info.Method, info.Endpoint
+	apiErr.OrderID, apiErr.UUID = info.OrderID, info.UUID
+	if len(body) > maxErrorBodySize {
+		body = body[:maxErrorBodySize]
+	}
+	apiErr.Body = bytes.Clone(body)
+
+	return apiErr
+}
+
+// ValidationErrors lists the messages of the API per invalid request field.
+type ValidationErrors map[string][]string
+
+// Field returns the messages for the field, or nil if it is valid.
+func (v ValidationErrors) Field(name string) []string {
+	return v[name]
+}
+
+// Has reports whether the field is invalid.
+func (v ValidationErrors) Has(name string) bool {
+	return len(v[name]) > 0
+}
+
+// Fields returns the names of the invalid fields, sorted.
+func (v ValidationErrors) Fields() []string {
+	fields := make([]string, 0, len(v))
+	for field := range v {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	return fields
+}
+
+// Error formats the messages as "validation errors: field: message, message; field: message".
+func (v ValidationErrors) Error() string {
+	var b strings.Builder
+	b.WriteString("validation errors:")
+	for i, field := range v.Fields() {
+		if i > 0 {
+			b.WriteByte(';')
+		}
+		fmt.Fprintf(&b, " %s: %s", field, strings.Join(v[field], ", "))
+	}
+
+	return b.String()
+}
+
+// decodeValidationErrors decodes the errors field, which lists either several messages
+// or a single one per field.
+func decodeValidationErrors(raw json.RawMessage) ValidationErrors {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var errs ValidationErrors
+	if json.Unmarshal(raw, &errs) == nil && len(errs) > 0 {
+		return errs
+	}
+
+	var single map[string]string
+	if json.Unmarshal(raw, &single) == nil && len(single) > 0 {
+		errs = make(ValidationErrors, len(single))
+		for field, message := range single {
+			errs[field] = []string{message}
+		}
+		return errs
+	}
+
+	return nil
+}
+
+// requestInfo describes a request in the errors it fails with, and how its response is decoded.
+type requestInfo struct {
+	Method   string
+	Endpoint string
+	OrderID  string
+	UUID     string
+
+	ctx     context.Context // Context of the call, passed to onError
+	start   time.Time       // Start of the call
+	clock   Clock           // Clock the call is timed with
+	onError ErrorHook       // Reports the errors of the call, if set
+	strict  bool            // Rejects unknown fields in the response, see WithStrictDecoding
+}
+
+type requestInfoKey struct{}
+
+// withRequestInfo attaches the description to the context of the request,
+// so the errors decoded from its response can refer to it.
+func withRequestInfo(ctx context.Context, info *requestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// responseRequestInfo returns the description of the request the response answers.
+func responseRequestInfo(res *http.Response) *requestInfo {
+	if res.Request == nil {
+		return &requestInfo{}
+	}
+	if info, ok := res.Request.Context().Value(requestInfoKey{}).(*requestInfo); ok {
+		return info
+	}
+
+	return &requestInfo{Method: res.Request.Method, Endpoint: res.Request.URL.Path}
+}
+
+// identify records the order_id and uuid of the JSON body, if it has them.
+func (i *requestInfo) identify(body []byte) {
+	ids := struct {
+		OrderID interface{} `json:"order_id"`
+		UUID    interface{} `json:"uuid"`
+	}{}
+	// Keep numeric identifiers as they were sent, rather than as float64
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if dec.Decode(&ids) != nil {
+		return
+	}
+
+	if ids.OrderID != nil {
+		i.OrderID = fmt.Sprint(ids.OrderID)
+	}
+	if ids.UUID != nil {
+		i.UUID = fmt.Sprint(ids.UUID)
+	}
+}
+
+// String formats the description as "POST /v1/payment/info [order_id=1 uuid=a]".
+func (i requestInfo) String() string {
+	s := i.Method + " " + i.Endpoint
+	var ids []string
+	if i.OrderID != "" {
+		ids = append(ids, "order_id="+i.OrderID)
+	}
+	if i.UUID != "" {
+		ids = append(ids, "uuid="+i.UUID)
+	}
+	if len(ids) > 0 {
+		s += " [" + strings.Join(ids, " ") + "]"
+	}
+
+	return s
+}
+
+// wrap prefixes the error with the description of the request.
+func (i *requestInfo) wrap(err error) error {
+	return fmt.Errorf("cryptomus: %s: %w", i.String(), err)
+}
+
+// fail wraps the error with the description of the request and reports it.
+func (i *requestInfo) fail(err error) error {
+	return i.report(i.wrap(err))
+}
+
+// report passes the error the call failed with to the error hook, if any, and returns it.
+func (i *requestInfo) report(err error) error {
+	if i.onError != nil {
+		ctx := i.ctx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		i.onError(ctx, err, CallInfo{
+			Method:   i.Method,
+			Endpoint: i.Endpoint,
+			OrderID:  i.OrderID,
+			UUID:     i.UUID,
+			Duration: clockOrSystem(i.clock).Now().Sub(i.start),
+		})
+	}
+
+	return err
+}
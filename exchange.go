@@ -1,11 +1,14 @@
 package cryptomus
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 // Endpoint constants
@@ -14,80 +17,154 @@ const (
 )
 
 // ExchangeRate представляет структуру обменного курса.
+// Course хранится как Amount — десятичное число произвольной точности, но в JSON по-прежнему передаётся строкой.
+// FetchedAt и CacheHit не передаются в JSON и описывают актуальность курса.
 type ExchangeRate struct {
 	From   string `json:"from"`
 	To     string `json:"to"`
-	Course string `json:"course"`
+	Course Amount `json:"course"`
+
+	FetchedAt time.Time `json:"-"` // Время получения курса от API
+	CacheHit  bool      `json:"-"` // Курс получен из ExchangeRateCache, а не напрямую от API
+}
+
+// Age возвращает возраст курса относительно момента его получения от API по системным часам.
+// Если курс получен клиентом с другими часами (WithClock), используйте AgeAt.
+func (r ExchangeRate) Age() time.Duration {
+	return r.AgeAt(time.Now())
+}
+
+// AgeAt возвращает возраст курса на момент now, например по часам клиента: rate.AgeAt(clock.Now()).
+func (r ExchangeRate) AgeAt(now time.Time) time.Duration {
+	if r.FetchedAt.IsZero() {
+		return 0
+	}
+
+	return now.Sub(r.FetchedAt)
 }
 
-// exchangeRateListRawResponse представляет структуру ответа API для списка обменных курсов.
-type exchangeRateListRawResponse struct {
-	State  int8           `json:"state"`
-	Result []ExchangeRate `json:"result"`
+// CourseString возвращает курс в виде строки без потери точности.
+func (r ExchangeRate) CourseString() string {
+	return r.Course.String()
+}
+
+// CourseFloat64 возвращает курс в виде float64 и признак того, что значение представлено точно.
+func (r ExchangeRate) CourseFloat64() (float64, bool) {
+	return r.Course.Float64()
+}
+
+// Apply пересчитывает сумму в валюте From в сумму в валюте To.
+func (r ExchangeRate) Apply(amount decimal.Decimal) decimal.Decimal {
+	return amount.Mul(r.Course.Decimal)
+}
+
+// ExchangeRateOptions представляет параметры запроса списка обменных курсов.
+type ExchangeRateOptions struct {
+	// To ограничивает результат указанными целевыми валютами (например, "USD", "EUR").
+	// Пустой список означает все доступные валюты.
+	To []string
 }
 
 // ListExchangeRates запрашивает список обменных курсов для указанной валюты.
 // Параметр currency является обязательным и должен содержать код валюты (например, "ETH").
-func (c *Cryptomus) ListExchangeRates(currency string) ([]ExchangeRate, error) {
+// Параметр opts может быть nil.
+func (c *Cryptomus) ListExchangeRates(ctx context.Context, currency string, opts *ExchangeRateOptions) ([]ExchangeRate, error) {
 	// Проверка обязательного параметра currency
 	currency = strings.TrimSpace(currency)
 	if currency == "" {
 		return nil, errors.New("currency parameter is required")
 	}
 
+	// Одновременные запросы курсов одной валюты объединяются в один запрос к API
+	rates, err := coalesce(ctx, c, "exchange-rate:"+currency, func(ctx context.Context) ([]ExchangeRate, error) {
+		return c.fetchExchangeRates(ctx, currency)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Каждый вызывающий получает собственную копию общего результата
+	return opts.filter(append([]ExchangeRate{}, rates...)), nil
+}
+
+// fetchExchangeRates запрашивает у API полный список обменных курсов для валюты.
+func (c *Cryptomus) fetchExchangeRates(ctx context.Context, currency string) ([]ExchangeRate, error) {
 	// Формируем эндпоинт с указанной валютой
 	endpoint := fmt.Sprintf(exchangeRateListEndpoint, currency)
 
-	// Формируем полный URL, корректно объединяя baseURL и endpoint
-	fullURL, err := joinURL(c.baseURL, endpoint)
+	// Отправляем запрос через общий конвейер; эндпоинт публичный и не требует подписи.
+	// При статус-коде, отличном от 200, или ненулевом state возвращается *APIError
+	rates, err := call[[]ExchangeRate](ctx, c, http.MethodGet, endpoint, nil, AuthNone)
 	if err != nil {
-		return nil, fmt.Errorf("invalid base URL or endpoint: %w", err)
+		return nil, err
 	}
 
-	// Создаём новый HTTP GET-запрос без тела
-	req, err := http.NewRequest("GET", fullURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	// Пустой список не является ошибкой: для редких валют API может не вернуть ни одного курса
+	if rates == nil {
+		rates = []ExchangeRate{}
 	}
 
-	// Устанавливаем необходимые заголовки
-	req.Header.Set("Accept", "application/json") // Опционально, если API требует
+	// Отмечаем время получения курсов
+	fetchedAt := c.clock.Now()
+	for i := range rates {
+		rates[i].FetchedAt = fetchedAt
+	}
 
-	// Отправляем запрос через существующий HTTP-клиент
-	res, err := c.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	return rates, nil
+}
+
+// filter оставляет только курсы с целевыми валютами из opts.To.
+func (opts *ExchangeRateOptions) filter(rates []ExchangeRate) []ExchangeRate {
+	if opts == nil || len(opts.To) == 0 {
+		return rates
 	}
-	defer res.Body.Close()
 
-	// Проверяем статус-код ответа
-	if res.StatusCode != http.StatusOK {
-		// Попытка декодировать сообщение об ошибке из тела ответа
-		var errResp struct {
-			Message string `json:"message"`
+	filtered := make([]ExchangeRate, 0, len(opts.To))
+	for _, rate := range rates {
+		for _, to := range opts.To {
+			if strings.EqualFold(rate.To, strings.TrimSpace(to)) {
+				filtered = append(filtered, rate)
+				break
+			}
 		}
-		_ = json.NewDecoder(res.Body).Decode(&errResp) // Игнорируем ошибку декодирования
-		if errResp.Message != "" {
-			return nil, fmt.Errorf("unexpected status code: %d, message: %s", res.StatusCode, errResp.Message)
-		}
-		return nil, fmt.Errorf("unexpected status code: %d", res.StatusCode)
 	}
 
-	// Декодируем JSON-ответ
-	response := &exchangeRateListRawResponse{}
-	if err := json.NewDecoder(res.Body).Decode(response); err != nil {
-		return nil, fmt.Errorf("failed to decode JSON response: %w", err)
-	}
+	return filtered
+}
+
+// fiatCurrencies содержит коды фиатных валют, которые возвращает API обменных курсов.
+var fiatCurrencies = map[string]bool{
+	"AED": true, "ARS": true, "AUD": true, "AZN": true, "BDT": true, "BGN": true, "BRL": true,
+	"BYN": true, "CAD": true, "CHF": true, "CLP": true, "CNY": true, "COP": true, "CZK": true,
+	"DKK": true, "EGP": true, "EUR": true, "GBP": true, "GEL": true, "HKD": true, "HUF": true,
+	"IDR": true, "ILS": true, "INR": true, "IRR": true, "JPY": true, "KES": true, "KGS": true,
+	"KRW": true, "KZT": true, "MDL": true, "MXN": true, "MYR": true, "NGN": true, "NOK": true,
+	"NZD": true, "PHP": true, "PKR": true, "PLN": true, "RON": true, "RUB": true, "SAR": true,
+	"SEK": true, "SGD": true, "THB": true, "TJS": true, "TRY": true, "TWD": true, "UAH": true,
+	"USD": true, "UZS": true, "VND": true, "ZAR": true,
+}
 
-	// Проверяем статус ответа от API
-	if response.State != 0 {
-		return nil, fmt.Errorf("API error: state %d", response.State)
+// FilterExchangeRates оставляет только курсы с указанными целевыми валютами.
+// Сравнение кодов валют не зависит от регистра.
+func FilterExchangeRates(rates []ExchangeRate, to ...string) []ExchangeRate {
+	if len(to) == 0 {
+		return []ExchangeRate{}
 	}
 
-	// Проверяем, что список обменных курсов не пустой
-	if len(response.Result) == 0 {
-		return nil, errors.New("exchange rate list is empty")
+	return (&ExchangeRateOptions{To: to}).filter(rates)
+}
+
+// GroupExchangeRates разделяет курсы на курсы к фиатным валютам и курсы к криптовалютам.
+func GroupExchangeRates(rates []ExchangeRate) (fiat, crypto []ExchangeRate) {
+	fiat = []ExchangeRate{}
+	crypto = []ExchangeRate{}
+	for _, rate := range rates {
+		if fiatCurrencies[strings.ToUpper(rate.To)] {
+			fiat = append(fiat, rate)
+		} else {
+			crypto = append(crypto, rate)
+		}
 	}
 
-	return response.Result, nil
+	return fiat, crypto
 }
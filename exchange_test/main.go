@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -26,8 +27,12 @@ func main() {
 	// Указываем валюту, для которой хотим получить обменные курсы
 	currency := "USDT"
 
-	// Вызываем метод ListExchangeRates
-	rates, err := apiClient.ListExchangeRates(currency)
+	// Ограничиваем время ожидания ответа через контекст
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Вызываем метод ListExchangeRates, оставляя только курсы к USD и EUR
+	rates, err := apiClient.ListExchangeRates(ctx, currency, &cryptomus.ExchangeRateOptions{To: []string{"USD", "EUR"}})
 	if err != nil {
 		log.Fatalf("Error fetching exchange rates: %v", err)
 	}
@@ -35,6 +40,6 @@ func main() {
 	// Выводим полученные обменные курсы
 	fmt.Printf("Exchange Rates for %s:\n", currency)
 	for _, rate := range rates {
-		fmt.Printf("1 %s = %s %s\n", rate.From, rate.Course, rate.To)
+		fmt.Printf("1 %s = %s %s\n", rate.From, rate.CourseString(), rate.To)
 	}
 }
@@ -2,9 +2,14 @@ module github.com/backtrac3r/go-cryptomus
 
 go 1.21.3
 
+require (
+	github.com/shopspring/decimal v1.4.0
+	github.com/stretchr/testify v1.9.0
+	golang.org/x/sync v0.10.0
+)
+
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/stretchr/testify v1.9.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,37 @@
+package cryptomus
+
+import "time"
+
+// Names of the metrics emitted by the client.
+const (
+	MetricWebhooksReceived       = "cryptomus_webhooks_received_total"       // Callbacks received, tagged by type
+	MetricWebhooksInvalid        = "cryptomus_webhooks_invalid_total"        // Callbacks that could not be parsed
+	MetricWebhookVerifyFailures  = "cryptomus_webhook_verify_failures_total" // Callbacks with an invalid signature, tagged by type
+	MetricWebhookHandlerErrors   = "cryptomus_webhook_handler_errors_total"  // Failed handlers, tagged by type and status
+	MetricWebhookProcessDuration = "cryptomus_webhook_process_duration"      // Time spent in handlers, tagged by type, status and result
+)
+
+// Metrics receives the counters and timings emitted by the client.
+// Implementations adapt it to Prometheus, StatsD, OpenTelemetry and the like, and must be safe for concurrent use.
+type Metrics interface {
+	// IncCounter increments the counter with the name by one.
+	IncCounter(name string, tags map[string]string)
+	// ObserveDuration records a timing for the metric with the name.
+	ObserveDuration(name string, d time.Duration, tags map[string]string)
+}
+
+// WithMetrics makes the client and the webhook dispatchers created for it emit metrics.
+func WithMetrics(metrics Metrics) Option {
+	return func(c *Cryptomus) {
+		if metrics != nil {
+			c.metrics = metrics
+		}
+	}
+}
+
+// noopMetrics discards all metrics.
+type noopMetrics struct{}
+
+func (noopMetrics) IncCounter(string, map[string]string) {}
+
+func (noopMetrics) ObserveDuration(string, time.Duration, map[string]string) {}
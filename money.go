@@ -0,0 +1,111 @@
+package cryptomus
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrCurrencyMismatch is returned by the Money operations given sums in different currencies.
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// Money is an amount in a currency. Its operations refuse to mix currencies,
+// making it a safe primitive for fee and refund math.
+type Money struct {
+	Amount   Amount       `json:"amount"`
+	Currency CurrencyCode `json:"currency"`
+}
+
+// NewMoney creates a sum of the amount in the currency. The currency code is upper-cased.
+func NewMoney(amount Amount, currency CurrencyCode) Money {
+	return Money{Amount: amount, Currency: currency.Normalize()}
+}
+
+// String formats the sum as "10.5 USDT".
+func (m Money) String() string {
+	return m.Amount.String() + " " + string(m.Currency)
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool {
+	return m.Amount.IsZero()
+}
+
+// Equal reports whether both sums have the same currency and amount.
+func (m Money) Equal(other Money) bool {
+	return m.sameCurrency(other) && m.Amount.Equal(other.Amount.Decimal)
+}
+
+// Add returns the sum of both, failing with ErrCurrencyMismatch if their currencies differ.
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.checkCurrency(other); err != nil {
+		return Money{}, err
+	}
+
+	return Money{Amount: AmountFromDecimal(m.Amount.Add(other.Amount.Decimal)), Currency: m.Currency}, nil
+}
+
+// Sub returns the difference of both, failing with ErrCurrencyMismatch if their currencies differ.
+func (m Money) Sub(other Money) (Money, error) {
+	if err := m.checkCurrency(other); err != nil {
+		return Money{}, err
+	}
+
+	return Money{Amount: AmountFromDecimal(m.Amount.Sub(other.Amount.Decimal)), Currency: m.Currency}, nil
+}
+
+// Mul returns the sum multiplied by the factor, e.g. a fee percentage, in the same currency.
+func (m Money) Mul(factor decimal.Decimal) Money {
+	return Money{Amount: AmountFromDecimal(m.Amount.Mul(factor)), Currency: m.Currency}
+}
+
+// Convert returns the sum converted into the To currency of the exchange rate,
+// failing with ErrCurrencyMismatch if its From currency is not the currency of the sum.
+func (m Money) Convert(rate ExchangeRate) (Money, error) {
+	if !strings.EqualFold(rate.From, string(m.Currency)) {
+		return Money{}, fmt.Errorf("%w: rate from %s applied to %s", ErrCurrencyMismatch, rate.From, m.Currency)
+	}
+
+	return NewMoney(AmountFromDecimal(rate.Apply(m.Amount.Decimal)), CurrencyCode(rate.To)), nil
+}
+
+// Split divides the sum into n parts rounded down to the decimal places, spreading the remainder
+// over the first parts, so the parts always add up to the sum rounded to the places.
+func (m Money) Split(n int, places int32) ([]Money, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("cannot split into %d parts", n)
+	}
+
+	total := m.Amount.Round(places)
+	sign := decimal.NewFromInt(int64(total.Sign()))
+	total = total.Abs()
+	part := total.Div(decimal.NewFromInt(int64(n))).RoundDown(places)
+	unit := decimal.New(1, -places)
+	remainder := total.Sub(part.Mul(decimal.NewFromInt(int64(n))))
+
+	parts := make([]Money, n)
+	for i := range parts {
+		amount := part
+		if remainder.Sign() > 0 {
+			amount = amount.Add(unit)
+			remainder = remainder.Sub(unit)
+		}
+		parts[i] = Money{Amount: AmountFromDecimal(amount.Mul(sign)), Currency: m.Currency}
+	}
+
+	return parts, nil
+}
+
+func (m Money) sameCurrency(other Money) bool {
+	return m.Currency.Normalize() == other.Currency.Normalize()
+}
+
+func (m Money) checkCurrency(other Money) error {
+	if !m.sameCurrency(other) {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package cryptomus
+
+import (
+	"strings"
+)
+
+// Network is the code of a blockchain network a cryptocurrency is transferred on, e.g. "tron".
+// Codes not listed below are valid too, as Cryptomus keeps adding networks.
+type Network string
+
+// Networks supported by Cryptomus.
+const (
+	NetworkTron      Network = "tron"
+	NetworkEthereum  Network = "eth"
+	NetworkBSC       Network = "bsc"
+	NetworkPolygon   Network = "polygon"
+	NetworkArbitrum  Network = "arbitrum"
+	NetworkAvalanche Network = "avalanche"
+	NetworkSolana    Network = "sol"
+	NetworkTON       Network = "ton"
+	NetworkBitcoin   Network = "btc"
+	NetworkLitecoin  Network = "ltc"
+	NetworkBCH       Network = "bch"
+	NetworkDash      Network = "dash"
+	NetworkDogecoin  Network = "doge"
+	NetworkMonero    Network = "xmr"
+)
+
+// currencyNetworks lists the networks each known cryptocurrency runs on.
+var currencyNetworks = map[CurrencyCode][]Network{
+	CurrencyUSDT:  {NetworkTron, NetworkEthereum, NetworkBSC, NetworkPolygon, NetworkArbitrum, NetworkAvalanche, NetworkSolana, NetworkTON},
+	CurrencyUSDC:  {NetworkEthereum, NetworkBSC, NetworkPolygon, NetworkArbitrum, NetworkAvalanche, NetworkSolana},
+	CurrencyDAI:   {NetworkEthereum, NetworkBSC, NetworkPolygon},
+	CurrencyBTC:   {NetworkBitcoin},
+	CurrencyETH:   {NetworkEthereum, NetworkBSC, NetworkArbitrum},
+	CurrencyLTC:   {NetworkLitecoin},
+	CurrencyBCH:   {NetworkBCH},
+	CurrencyDASH:  {NetworkDash},
+	CurrencyDOGE:  {NetworkDogecoin},
+	CurrencyTRX:   {NetworkTron},
+	CurrencyTON:   {NetworkTON},
+	CurrencyBNB:   {NetworkBSC},
+	CurrencySOL:   {NetworkSolana},
+	CurrencyXMR:   {NetworkMonero},
+	CurrencyPOL:   {NetworkPolygon},
+	CurrencyMATIC: {NetworkPolygon},
+	CurrencyAVAX:  {NetworkAvalanche},
+	CurrencySHIB:  {NetworkEthereum},
+	CurrencyCGPT:  {NetworkBSC},
+	CurrencyVERSE: {NetworkEthereum},
+	CurrencyHMSTR: {NetworkTON},
+	CurrencyDOGS:  {NetworkTON},
+	CurrencyNOT:   {NetworkTON},
+}
+
+// Normalize returns the code trimmed and lower-cased.
+func (n Network) Normalize() Network {
+	return Network(strings.ToLower(strings.TrimSpace(string(n))))
+}
+
+// IsKnown reports whether the code is a network known to the SDK.
+func (n Network) IsKnown() bool {
+	n = n.Normalize()
+	for _, networks := range currencyNetworks {
+		for _, network := range networks {
+			if network == n {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Networks returns the networks the cryptocurrency runs on, or nil if it is not known to the SDK.
+func (c CurrencyCode) Networks() []Network {
+	networks := currencyNetworks[c.Normalize()]
+	if networks == nil {
+		return nil
+	}
+
+	return append([]Network(nil), networks...)
+}
+
+// SupportsNetwork reports whether the cryptocurrency runs on the network. Currencies and networks
+// not known to the SDK are assumed to be compatible, leaving the decision to the API.
+func (c CurrencyCode) SupportsNetwork(network Network) bool {
+	networks, ok := currencyNetworks[c.Normalize()]
+	if !ok || !network.IsKnown() {
+		return true
+	}
+
+	network = network.Normalize()
+	for _, n := range networks {
+		if n == network {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateNetwork checks that the network of the request, if set, runs the currency,
+// failing with ValidationErrors otherwise.
+func validateNetwork(currency CurrencyCode, network Network) error {
+	if currency == "" || network == "" || currency.SupportsNetwork(network) {
+		return nil
+	}
+
+	return ValidationErrors{"network": {
+		"The network " + string(network) + " does not support " + string(currency.Normalize()) + ".",
+	}}
+}
@@ -0,0 +1,407 @@
+package cryptomus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Default caps of the ListAll methods.
+const (
+	DefaultListAllMaxItems = 10000
+	DefaultListAllMaxPages = 1000
+)
+
+// ErrListTruncated is returned by the ListAll methods, along with the items collected so far,
+// when the listing has more items than the caps allow.
+var ErrListTruncated = errors.New("listing truncated")
+
+// ListAllOptions caps the number of items and pages the ListAll methods collect,
+// so that a large account doesn't exhaust memory or the rate limit by accident.
+type ListAllOptions struct {
+	MaxItems int // Maximum number of items collected, DefaultListAllMaxItems if zero
+	MaxPages int // Maximum number of pages fetched, DefaultListAllMaxPages if zero
+}
+
+// HistoryOptions selects the payments or payouts listed by the history iterators.
+type HistoryOptions struct {
+	DateFrom time.Time // Optional: Only list the items created since this time
+	DateTo   time.Time // Optional: Only list the items created until this time
+	PerPage  int       // Optional: Number of items per page requested from the API, its default if zero
+	// Optional: Length of the date windows the range is split into, for ranges longer than the API accepts.
+	// The windows are listed in turn, oldest first, as a single stream. The range isn't split if zero.
+	Window time.Duration
+}
+
+// windows returns the date windows listed in turn.
+func (o *HistoryOptions) windows() []DateWindow {
+	if o.Window > 0 {
+		return SplitDateRange(o.DateFrom, o.DateTo, o.Window)
+	}
+
+	return []DateWindow{{From: o.DateFrom, To: o.DateTo}}
+}
+
+// payload builds the payload of the history endpoints for the date window.
+func (o *HistoryOptions) payload(window DateWindow) map[string]any {
+	payload := historyPayload(window.From, window.To)
+	if o.PerPage > 0 {
+		payload["per_page"] = o.PerPage
+	}
+
+	return payload
+}
+
+// Page is a page of a cursor-paginated listing.
+type Page[T any] struct {
+	Items          []T
+	Window         DateWindow // Date window the page belongs to, zero for listings not split by date
+	Cursor         string     // Cursor the page was fetched with, empty for the first page
+	NextCursor     string     // Cursor of the next page, empty for the last page
+	PreviousCursor string     // Cursor of the previous page, empty for the first page
+}
+
+// pageFetcher fetches the page of a listing in the date window at the cursor, the first page if it is empty.
+type pageFetcher[T any] func(ctx context.Context, window DateWindow, cursor string) (*Page[T], error)
+
+// Iterator walks a cursor-paginated listing, fetching the pages as they are needed and
+// threading the cursors between them, then moving on to the next date window if the listing is split.
+// Iterate either the items with Next and Item, or the pages with NextPage and Page, but not both:
+//
+//	it := client.PaymentHistory(ctx, &cryptomus.HistoryOptions{DateFrom: from, PerPage: 100})
+//	for it.Next() {
+//		payment := it.Item()
+//		// ...
+//	}
+//	if err := it.Err(); err != nil {
+//		// ...
+//	}
+//
+// An Iterator is not safe for concurrent use.
+type Iterator[T any] struct {
+	ctx      context.Context
+	fetch    pageFetcher[T]
+	windows  []DateWindow
+	window   int
+	cursor   string
+	page     *Page[T]
+	index    int
+	done     bool
+	err      error
+	prefetch bool
+	pending  *prefetchedPage[T]
+	reverse  bool
+
+	// Streaming, see Stream
+	openStream pageStreamer[T]
+	stream     bool
+	current    *pageStream[T]
+	item       T
+}
+
+// prefetchedPage is a page being fetched in the background, at the position it was requested for.
+type prefetchedPage[T any] struct {
+	window int
+	cursor string
+	result chan prefetchResult[T]
+}
+
+type prefetchResult[T any] struct {
+	page *Page[T]
+	err  error
+}
+
+// newIterator returns an iterator starting at the first page of the first date window of the listing.
+// Listings not split by date have no windows.
+func newIterator[T any](ctx context.Context, windows []DateWindow, fetch pageFetcher[T]) *Iterator[T] {
+	if len(windows) == 0 {
+		windows = []DateWindow{{}}
+	}
+
+	return &Iterator[T]{ctx: ctx, fetch: fetch, windows: windows}
+}
+
+// NewSliceIterator returns an iterator over the items as a single page, to return from mocks and fakes
+// of the methods listing pages.
+func NewSliceIterator[T any](ctx context.Context, items []T) *Iterator[T] {
+	return newIterator(ctx, nil, func(ctx context.Context, _ DateWindow, _ string) (*Page[T], error) {
+		return &Page[T]{Items: items}, nil
+	})
+}
+
+// Prefetch makes the iterator fetch the next page in the background as soon as the current one is returned,
+// hiding the round-trip latency while the caller processes it, e.g. for large history exports:
+//
+//	it := client.PaymentHistory(ctx, opts).Prefetch()
+//
+// At most one page is fetched ahead. A page prefetched when the iteration is abandoned is discarded.
+func (it *Iterator[T]) Prefetch() *Iterator[T] {
+	it.prefetch = true
+	return it
+}
+
+// Reverse makes the iterator walk the listing backwards, following the previous cursors of the pages
+// and listing the date windows newest first. The items of each page are reversed too, so the whole stream
+// is in reverse order. It is meant for backfilling from a known position, set with Resume:
+//
+//	it := client.PaymentHistory(ctx, opts).Reverse()
+//	if err := it.Resume(&cryptomus.ResumeToken{Cursor: cursor}); err != nil {
+//		// ...
+//	}
+//
+// Without a position, iterating backwards starts from the first page of the newest window.
+// Call it before Resume and before the first page is fetched, as it restarts the listing.
+func (it *Iterator[T]) Reverse() *Iterator[T] {
+	if it.reverse {
+		return it
+	}
+
+	windows := make([]DateWindow, len(it.windows))
+	for i, window := range it.windows {
+		windows[len(windows)-1-i] = window
+	}
+	it.closeStream()
+	it.windows, it.window, it.cursor = windows, 0, ""
+	it.reverse, it.pending = true, nil
+
+	return it
+}
+
+// Stream makes Next decode the items of each page one by one as the response is read, instead of reading
+// the whole page first, so that a large page of a history export isn't held in memory at once:
+//
+//	it := client.PaymentHistory(ctx, &cryptomus.HistoryOptions{PerPage: 1000}).Stream()
+//	defer it.Close()
+//
+// The response of a page stays open while its items are iterated, so the client's request timeout must
+// allow for the processing of a whole page, and an iteration abandoned halfway must be closed.
+// While streaming, Page returns the page being read, without its items; its cursors are set once it is
+// exhausted, and until then ResumeToken points at it rather than at the next page.
+// Streaming doesn't prefetch, and has no effect on iterators walking backwards, which reverse whole pages,
+// on NextPage, or on listings other than the payment and payout histories.
+func (it *Iterator[T]) Stream() *Iterator[T] {
+	it.stream = true
+	return it
+}
+
+// Close releases the response of the page being streamed, if any. The iteration ends with it.
+// It is only needed to abandon a streaming iteration before its end.
+func (it *Iterator[T]) Close() error {
+	it.closeStream()
+	it.done = true
+
+	return nil
+}
+
+// streaming reports whether Next decodes the items as they are read.
+func (it *Iterator[T]) streaming() bool {
+	return it.stream && it.openStream != nil && !it.reverse
+}
+
+// closeStream releases the response of the page being streamed, if any.
+func (it *Iterator[T]) closeStream() {
+	if it.current != nil {
+		it.current.close()
+		it.current = nil
+	}
+}
+
+// Next advances to the next item, fetching the next page when the current one is exhausted.
+// It returns false at the end of the listing or on error, which Err reports.
+func (it *Iterator[T]) Next() bool {
+	if it.streaming() {
+		return it.nextStreamed()
+	}
+
+	for it.page == nil || it.index+1 >= len(it.page.Items) {
+		if !it.NextPage() {
+			return false
+		}
+	}
+	it.index++
+
+	return true
+}
+
+// nextStreamed advances to the next item of the page being streamed, opening the next page
+// when the current one is exhausted.
+func (it *Iterator[T]) nextStreamed() bool {
+	for {
+		if it.current == nil {
+			if it.done || it.err != nil {
+				return false
+			}
+			if err := it.ctx.Err(); err != nil {
+				it.err = err
+				return false
+			}
+
+			window := it.windows[it.window]
+			current, err := it.openStream(it.ctx, window, it.cursor)
+			if err != nil {
+				it.err = err
+				return false
+			}
+			it.current, it.page, it.index = current, &Page[T]{Window: window, Cursor: it.cursor}, -1
+		}
+
+		item, ok, err := it.current.next()
+		if err != nil {
+			it.closeStream()
+			it.err = err
+			return false
+		}
+		if ok {
+			it.item = item
+			return true
+		}
+
+		it.page.NextCursor, it.page.PreviousCursor = it.current.cursors.NextCursor, it.current.cursors.PreviousCursor
+		it.closeStream()
+		it.advance(it.page.NextCursor)
+	}
+}
+
+// Item returns the current item.
+func (it *Iterator[T]) Item() T {
+	if it.streaming() {
+		return it.item
+	}
+	if it.page == nil || it.index < 0 || it.index >= len(it.page.Items) {
+		var zero T
+		return zero
+	}
+
+	return it.page.Items[it.index]
+}
+
+// NextPage fetches the next page of the listing. It returns false past the last page or on error,
+// which Err reports. Pages may be empty when the listing is filtered client-side.
+func (it *Iterator[T]) NextPage() bool {
+	if it.done || it.err != nil {
+		return false
+	}
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	window := it.windows[it.window]
+	page, err := it.fetchPage(window)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	page.Window, page.Cursor = window, it.cursor
+	next := page.NextCursor
+	if it.reverse {
+		next = page.PreviousCursor
+		for i, j := 0, len(page.Items)-1; i < j; i, j = i+1, j-1 {
+			page.Items[i], page.Items[j] = page.Items[j], page.Items[i]
+		}
+	}
+	it.page, it.index = page, -1
+	it.advance(next)
+	if it.prefetch && !it.done {
+		it.startPrefetch()
+	}
+
+	return true
+}
+
+// advance moves past the current page to the page at the next cursor.
+func (it *Iterator[T]) advance(next string) {
+	// Move on to the next window after the last page, and on a cursor pointing back at the page,
+	// which would loop forever
+	switch {
+	case next != "" && next != it.cursor:
+		it.cursor = next
+	case it.window+1 < len(it.windows):
+		it.window++
+		it.cursor = ""
+	default:
+		it.done = true
+	}
+}
+
+// fetchPage returns the page of the window at the current cursor, waiting for it if it is being prefetched.
+func (it *Iterator[T]) fetchPage(window DateWindow) (*Page[T], error) {
+	if pending := it.pending; pending != nil {
+		it.pending = nil
+		if pending.window == it.window && pending.cursor == it.cursor {
+			select {
+			case res := <-pending.result:
+				return res.page, res.err
+			case <-it.ctx.Done():
+				return nil, it.ctx.Err()
+			}
+		}
+	}
+
+	return it.fetch(it.ctx, window, it.cursor)
+}
+
+// startPrefetch starts fetching the page at the current position in the background.
+func (it *Iterator[T]) startPrefetch() {
+	// The channel is buffered so that the goroutine exits even if the page is never received
+	pending := &prefetchedPage[T]{window: it.window, cursor: it.cursor, result: make(chan prefetchResult[T], 1)}
+	window := it.windows[it.window]
+	go func() {
+		page, err := it.fetch(it.ctx, window, pending.cursor)
+		pending.result <- prefetchResult[T]{page: page, err: err}
+	}()
+	it.pending = pending
+}
+
+// Page returns the current page.
+func (it *Iterator[T]) Page() *Page[T] {
+	return it.page
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// cursorEndpoint returns the endpoint with the cursor of the page as query parameter, as the history endpoints expect.
+func cursorEndpoint(endpoint, cursor string) string {
+	if cursor == "" {
+		return endpoint
+	}
+
+	return endpoint + "?" + url.Values{"cursor": {cursor}}.Encode()
+}
+
+// collect follows the cursors of the listing to completion and returns its items, failing with
+// ErrListTruncated along with the items collected so far if the caps of the options are reached first.
+// Options may be nil.
+func collect[T any](it *Iterator[T], opts *ListAllOptions) ([]T, error) {
+	maxItems, maxPages := DefaultListAllMaxItems, DefaultListAllMaxPages
+	if opts != nil && opts.MaxItems > 0 {
+		maxItems = opts.MaxItems
+	}
+	if opts != nil && opts.MaxPages > 0 {
+		maxPages = opts.MaxPages
+	}
+
+	items := []T{}
+	for pages := 0; it.NextPage(); pages++ {
+		for _, item := range it.Page().Items {
+			if len(items) == maxItems {
+				return items, fmt.Errorf("%w: more than %d items", ErrListTruncated, maxItems)
+			}
+			items = append(items, item)
+		}
+		if pages+1 == maxPages && !it.done {
+			return items, fmt.Errorf("%w: more than %d pages", ErrListTruncated, maxPages)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
@@ -1,8 +1,10 @@
 package cryptomus
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"net/http"
 	"time"
 )
 
@@ -15,29 +17,64 @@ const (
 )
 
 type InvoiceRequest struct {
-	Amount   string `json:"amount"`
-	Currency string `json:"currency"`
-	OrderID  string `json:"order_id"`
+	Amount   Amount       `json:"amount" validate:"amount"`
+	Currency CurrencyCode `json:"currency" validate:"required,currency"`
+	OrderID  string       `json:"order_id" validate:"required,max=128"`
 	*InvoiceRequestOptions
 }
 
 type InvoiceRequestOptions struct {
-	Network                string     `json:"network,omitempty"`
-	UrlReturn              string     `json:"url_return,omitempty"`
-	UrlSuccess             string     `json:"url_success,omitempty"`
-	UrlCallback            string     `json:"url_callback,omitempty"`
-	IsPaymentMultiple      bool       `json:"is_payment_multiple,omitempty"`
-	Lifetime               uint16     `json:"lifetime,omitempty"`
-	ToCurrency             string     `json:"to_currency,omitempty"`
-	Subtract               uint8      `json:"subtract,omitempty"`
-	AccuarcyPaymentPercent float32    `json:"accuarcy_payment_percent,omitempty"`
-	AdditionalData         string     `json:"additional_data,omitempty"`
-	Currencies             []Currency `json:"currencies,omitempty"`
-	ExceptCurrencies       []Currency `json:"except_currencies,omitempty"`
-	CourseSource           string     `json:"course_source,omitempty"`
-	FromReferralCode       string     `json:"from_referral_code,omitempty"`
-	DiscountPercent        int8       `json:"discount_percent,omitempty"`
-	IsRefresh              bool       `json:"is_refresh,omitempty"`
+	Network                string         `json:"network,omitempty"`
+	UrlReturn              string         `json:"url_return,omitempty" validate:"url,max=255"`
+	UrlSuccess             string         `json:"url_success,omitempty" validate:"url,max=255"`
+	UrlCallback            string         `json:"url_callback,omitempty" validate:"url,max=255"`
+	IsPaymentMultiple      *bool          `json:"is_payment_multiple,omitempty"`
+	Lifetime               time.Duration  `json:"-" validate:"min=300,max=43200"` // Sent in whole seconds, e.g. 30*time.Minute
+	ToCurrency             string         `json:"to_currency,omitempty"`
+	Subtract               *uint8         `json:"subtract,omitempty" validate:"max=100"`
+	AccuarcyPaymentPercent *float32       `json:"accuarcy_payment_percent,omitempty"`
+	AdditionalData         AdditionalData `json:"additional_data,omitempty" validate:"max=255"`
+	Currencies             []Currency     `json:"currencies,omitempty"`
+	ExceptCurrencies       []Currency     `json:"except_currencies,omitempty"`
+	CourseSource           string         `json:"course_source,omitempty"`
+	FromReferralCode       string         `json:"from_referral_code,omitempty"`
+	DiscountPercent        *int8          `json:"discount_percent,omitempty" validate:"min=-99,max=100"`
+	IsRefresh              *bool          `json:"is_refresh,omitempty"`
+}
+
+// MarshalJSON encodes the request, with the lifetime of the invoice in whole seconds as the API expects.
+func (r InvoiceRequest) MarshalJSON() ([]byte, error) {
+	type plain InvoiceRequest
+	var lifetime int64
+	if r.InvoiceRequestOptions != nil {
+		lifetime = int64(r.Lifetime / time.Second)
+	}
+
+	return marshalUnescaped(struct {
+		plain
+		Lifetime int64 `json:"lifetime,omitempty"`
+	}{plain(r), lifetime})
+}
+
+// UnmarshalJSON decodes the request, reading the lifetime of the invoice in seconds.
+func (r *InvoiceRequest) UnmarshalJSON(data []byte) error {
+	type plain InvoiceRequest
+	aux := struct {
+		*plain
+		Lifetime int64 `json:"lifetime"`
+	}{plain: (*plain)(r)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if aux.Lifetime != 0 {
+		if r.InvoiceRequestOptions == nil {
+			r.InvoiceRequestOptions = &InvoiceRequestOptions{}
+		}
+		r.Lifetime = time.Duration(aux.Lifetime) * time.Second
+	}
+
+	return nil
 }
 
 type Currency struct {
@@ -46,47 +83,40 @@ type Currency struct {
 }
 
 type Payment struct {
-	UUID                    string    `json:"uuid"`
-	OrderID                 string    `json:"order_id"`
-	Amount                  string    `json:"amount"`
-	PaymentAmount           string    `json:"payment_amount,omitempty"`
-	PaymentAmountUSD        string    `json:"payment_amount_usd,omitempty"`
-	PayerAmount             string    `json:"payer_amount,omitempty"`
-	PayerAmountExchangeRate string    `json:"payer_amount_exchange_rate,omitempty"`
-	DiscountPercent         int8      `json:"discount_percent,omitempty"`
-	Discount                string    `json:"discount,omitempty"`
-	PayerCurrency           string    `json:"payer_currency,omitempty"`
-	Currency                string    `json:"currency"`
-	MerchantAmount          string    `json:"merchant_amount,omitempty"`
-	Network                 string    `json:"network,omitempty"`
-	Address                 string    `json:"address,omitempty"`
-	From                    string    `json:"from,omitempty"`
-	TxId                    string    `json:"txid,omitempty"`
-	PaymentStatus           string    `json:"payment_status"`
-	Status                  string    `json:"status,omitempty"`
-	Url                     string    `json:"url"`
-	ExpiredAt               float64   `json:"expired_at"`
-	IsFinal                 bool      `json:"is_final"`
-	AdditionalData          string    `json:"additional_data,omitempty"`
-	Comments                string    `json:"comments,omitempty"`
-	CreatedAt               time.Time `json:"created_at"`
-	UpdatedAt               time.Time `json:"updated_at"`
-}
-
-type invoiceRawResponse struct {
-	Result *Payment
-	State  int8
-}
-
-type paymentQRCodeRawResponse struct {
-	Result struct {
-		Image string `json:"image"`
-	} `json:"result"`
-	State int8 `json:"state"`
+	UUID                    UUID           `json:"uuid"`
+	OrderID                 string         `json:"order_id"`
+	Amount                  Amount         `json:"amount"`
+	PaymentAmount           Amount         `json:"payment_amount,omitempty"`
+	PaymentAmountUSD        Amount         `json:"payment_amount_usd,omitempty"`
+	PayerAmount             Amount         `json:"payer_amount,omitempty"`
+	PayerAmountExchangeRate Amount         `json:"payer_amount_exchange_rate,omitempty"`
+	DiscountPercent         int8           `json:"discount_percent,omitempty"`
+	Discount                Amount         `json:"discount,omitempty"`
+	PayerCurrency           string         `json:"payer_currency,omitempty"`
+	Currency                string         `json:"currency"`
+	MerchantAmount          Amount         `json:"merchant_amount,omitempty"`
+	Network                 string         `json:"network,omitempty"`
+	Address                 string         `json:"address,omitempty"`
+	From                    string         `json:"from,omitempty"`
+	TxId                    string         `json:"txid,omitempty"`
+	PaymentStatus           PaymentStatus  `json:"payment_status"`
+	Status                  PaymentStatus  `json:"status,omitempty"`
+	Url                     string         `json:"url"`
+	ExpiredAt               CryptomusTime  `json:"expired_at"`
+	IsFinal                 bool           `json:"is_final"`
+	AdditionalData          AdditionalData `json:"additional_data,omitempty"`
+	Comments                string         `json:"comments,omitempty"`
+	CreatedAt               CryptomusTime  `json:"created_at"`
+	UpdatedAt               CryptomusTime  `json:"updated_at"`
+}
+
+// qrCodeResult is the result of the QR code endpoints.
+type qrCodeResult struct {
+	Image string `json:"image"`
 }
 
 type PaymentInfoRequest struct {
-	PaymentUUID string `json:"uuid,omitempty"`
+	PaymentUUID UUID   `json:"uuid,omitempty"`
 	OrderID     string `json:"order_id,omitempty"`
 }
 
@@ -96,16 +126,20 @@ type PaymentHistoryResponse struct {
 }
 
 type PaymentHistoryPaginate struct {
-	Count          int16  `json:"count"`
+	Count          int    `json:"count"`
 	HasPages       bool   `json:"hasPages"`
 	NextCursor     string `json:"nextCursor,omitempty"`
 	PreviousCursor string `json:"previousCursor,omitempty"`
-	PerPage        int16  `json:"perPage"`
+	PerPage        int    `json:"perPage"`
 }
 
 type paymentHistoryRawResponse struct {
-	State    int8                    `json:"state"`
-	Result   []*Payment              `json:"result"`
+	State  int8                  `json:"state"`
+	Result *paymentHistoryResult `json:"result"`
+}
+
+type paymentHistoryResult struct {
+	Items    []*Payment              `json:"items"`
 	Paginate *PaymentHistoryPaginate `json:"paginate"`
 }
 
@@ -118,77 +152,114 @@ type PaymentService struct {
 }
 
 type PaymentServiceLimit struct {
-	MinAmount string `json:"minAmount"`
-	MaxAmount string `json:"maxAmount"`
+	MinAmount Amount `json:"minAmount"`
+	MaxAmount Amount `json:"maxAmount"`
 }
 
 type PaymentServiceCommision struct {
-	FeeAmount string `json:"feeAmount"`
-	Percent   string `json:"percent"`
-}
-
-type paymentServiceListRawResponse struct {
-	Result []*PaymentService `json:"result"`
-	State  int8              `json:"state"`
+	FeeAmount Amount      `json:"feeAmount"`
+	Percent   json.Number `json:"percent"`
 }
 
 func (c *Cryptomus) CreateInvoice(invoiceReq *InvoiceRequest) (*Payment, error) {
-	res, err := c.fetch("POST", createInvoiceEndpoit, invoiceReq)
-	if err != nil {
-		return nil, err
+	if invoiceReq == nil {
+		return nil, errors.New("invoice request cannot be nil")
 	}
 
-	defer res.Body.Close()
-
-	response := &invoiceRawResponse{}
-	if err = json.NewDecoder(res.Body).Decode(response); err != nil {
+	// Express the amount with the precision of the currency, leaving the request of the caller as is
+	req := *invoiceReq
+	var network Network
+	if req.InvoiceRequestOptions != nil {
+		network = Network(req.Network)
+	}
+	amount, err := normalizeAmount("amount", req.Amount, req.Currency, network)
+	if err != nil {
 		return nil, err
 	}
+	req.Amount = amount
 
-	return response.Result, nil
+	return post[*Payment](c, createInvoiceEndpoit, &req)
 }
 
-func (c *Cryptomus) GeneratePaymentQRCode(paymentUUID string) (string, error) {
-	payload := map[string]any{"merchant_payment_uuid": paymentUUID}
-	res, err := c.fetch("POST", generateInvoiceQRCodeEndpoint, payload)
-	if err != nil {
+func (c *Cryptomus) GeneratePaymentQRCode(paymentUUID UUID) (string, error) {
+	if err := validateUUID("merchant_payment_uuid", paymentUUID); err != nil {
 		return "", err
 	}
 
-	defer res.Body.Close()
-
-	response := &paymentQRCodeRawResponse{}
-	if err = json.NewDecoder(res.Body).Decode(response); err != nil {
+	payload := map[string]any{"merchant_payment_uuid": paymentUUID}
+	qrCode, err := post[qrCodeResult](c, generateInvoiceQRCodeEndpoint, payload)
+	if err != nil {
 		return "", err
 	}
 
-	return response.Result.Image, nil
-
+	return qrCode.Image, nil
 }
 
 func (c *Cryptomus) GetPaymentInfo(paymentInfoReq *PaymentInfoRequest) (*Payment, error) {
-	if paymentInfoReq.PaymentUUID == "" && paymentInfoReq.OrderID == "" {
-		return nil, errors.New("you should pass one of required values [PaymentUUID, OrderID]")
-	}
+	return c.getPaymentInfo(context.Background(), paymentInfoReq)
+}
 
-	res, err := c.fetch("POST", paymentInfoEndpoint, paymentInfoReq)
-	if err != nil {
+func (c *Cryptomus) getPaymentInfo(ctx context.Context, paymentInfoReq *PaymentInfoRequest) (*Payment, error) {
+	if err := checkIdentifiers(paymentInfoReq.PaymentUUID, paymentInfoReq.OrderID, "you should pass one of required values [PaymentUUID, OrderID]"); err != nil {
 		return nil, err
 	}
 
-	defer res.Body.Close()
-
-	response := &invoiceRawResponse{}
-	if err = json.NewDecoder(res.Body).Decode(response); err != nil {
-		return nil, err
+	// Concurrent lookups of the same payment share a single request
+	key := "payment:" + string(paymentInfoReq.PaymentUUID) + "\x00" + paymentInfoReq.OrderID
+	payment, err := coalesce(ctx, c, key, func(ctx context.Context) (*Payment, error) {
+		return call[*Payment](ctx, c, http.MethodPost, paymentInfoEndpoint, paymentInfoReq, AuthPayment)
+	})
+	if err != nil || payment == nil {
+		return payment, err
 	}
 
-	return response.Result, nil
+	copied := *payment
+	copied.AdditionalData = append(AdditionalData(nil), payment.AdditionalData...)
+	return &copied, nil
 }
 
 func (c *Cryptomus) GetPaymentHistory(dateFrom, dateTo time.Time) (*PaymentHistoryResponse, error) {
-	payload := map[string]any{"date_from": dateFrom, "date_to": dateTo}
-	res, err := c.fetch("POST", paymentHistoryEndpoint, payload)
+	return c.paymentHistoryPage(context.Background(), "", &HistoryOptions{}, DateWindow{From: dateFrom, To: dateTo})
+}
+
+// PaymentHistory returns an iterator over the payments selected by the options, following the cursors
+// of the history pages. Options may be nil, in which case the whole history is listed.
+// Call Stream on the iterator to decode the payments of each page as they are read.
+func (c *Cryptomus) PaymentHistory(ctx context.Context, opts *HistoryOptions) *Iterator[*Payment] {
+	if opts == nil {
+		opts = &HistoryOptions{}
+	}
+
+	it := newIterator(ctx, opts.windows(), func(ctx context.Context, window DateWindow, cursor string) (*Page[*Payment], error) {
+		history, err := c.paymentHistoryPage(ctx, cursor, opts, window)
+		if err != nil {
+			return nil, err
+		}
+
+		page := &Page[*Payment]{Items: history.Payments}
+		if history.Paginate != nil {
+			page.NextCursor, page.PreviousCursor = history.Paginate.NextCursor, history.Paginate.PreviousCursor
+		}
+		return page, nil
+	})
+	it.openStream = func(ctx context.Context, window DateWindow, cursor string) (*pageStream[*Payment], error) {
+		return openPageStream[*Payment](ctx, c, cursorEndpoint(paymentHistoryEndpoint, cursor), opts.payload(window), AuthPayment)
+	}
+
+	return it
+}
+
+// ListAllPayments returns the payments selected by the history options, following the cursors of the history
+// to completion. It fails with ErrListTruncated, along with the payments collected so far, if the history
+// is longer than the caps of the options allow. Both options may be nil.
+func (c *Cryptomus) ListAllPayments(ctx context.Context, historyOpts *HistoryOptions, opts *ListAllOptions) ([]*Payment, error) {
+	return collect(c.PaymentHistory(ctx, historyOpts), opts)
+}
+
+// paymentHistoryPage fetches the page of the payment history in the date window at the cursor, the first page if it is empty.
+func (c *Cryptomus) paymentHistoryPage(ctx context.Context, cursor string, opts *HistoryOptions, window DateWindow) (*PaymentHistoryResponse, error) {
+	payload := opts.payload(window)
+	res, err := c.do(ctx, http.MethodPost, cursorEndpoint(paymentHistoryEndpoint, cursor), payload, AuthPayment)
 	if err != nil {
 		return nil, err
 	}
@@ -196,29 +267,18 @@ func (c *Cryptomus) GetPaymentHistory(dateFrom, dateTo time.Time) (*PaymentHisto
 	defer res.Body.Close()
 
 	response := &paymentHistoryRawResponse{}
-	if err = json.NewDecoder(res.Body).Decode(response); err != nil {
+	if err = decodeInto(res, response); err != nil {
 		return nil, err
 	}
 
-	paymentHistory := &PaymentHistoryResponse{
-		Payments: response.Result,
-		Paginate: response.Paginate,
+	paymentHistory := &PaymentHistoryResponse{}
+	if response.Result != nil {
+		paymentHistory.Payments = response.Result.Items
+		paymentHistory.Paginate = response.Result.Paginate
 	}
 	return paymentHistory, nil
 }
 
 func (c *Cryptomus) GetPaymentServicesList() ([]*PaymentService, error) {
-	payload := make(map[string]any)
-	res, err := c.fetch("POST", paymentServicesListEndpoint, payload)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	response := &paymentServiceListRawResponse{}
-	if err = json.NewDecoder(res.Body).Decode(response); err != nil {
-		return nil, err
-	}
-
-	return response.Result, nil
+	return post[[]*PaymentService](c, paymentServicesListEndpoint, map[string]any{})
 }
@@ -1,8 +1,10 @@
 package cryptomus
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"net/http"
 	"time"
 )
 
@@ -14,16 +16,16 @@ const (
 )
 
 type PayoutRequest struct {
-	Amount     string `json:"amount"`
-	Currency   string `json:"currency"`
-	OrderID    string `json:"order_id"`
-	Address    string `json:"address"`
-	IsSubtract bool   `json:"is_subtract"`
-	Network    string `json:"network"`
+	Amount     Amount       `json:"amount" validate:"amount"`
+	Currency   CurrencyCode `json:"currency" validate:"required,currency"`
+	OrderID    string       `json:"order_id" validate:"required,max=128"`
+	Address    string       `json:"address" validate:"required"`
+	IsSubtract bool         `json:"is_subtract"`
+	Network    Network      `json:"network" validate:"required"`
 }
 
 type PayoutRequestOptions struct {
-	UrlCallback  string `json:"url_callback,omitempty"`
+	UrlCallback  string `json:"url_callback,omitempty" validate:"url,max=255"`
 	ToCurrency   string `json:"to_currency,omitempty"`
 	CourseSource string `json:"course_source,omitempty"`
 	FromCurrency string `json:"from_currency,omitempty"`
@@ -32,47 +34,48 @@ type PayoutRequestOptions struct {
 }
 
 type Payout struct {
-	UUID          string `json:"uuid"`
-	OrderID       string `json:"order_id"`
-	Amount        string `json:"amount"`
-	Currency      string `json:"currency"`
-	Network       string `json:"network"`
-	Address       string `json:"address"`
-	TxId          string `json:"txid"`
-	Status        string `json:"status"`
-	IsFinal       bool   `json:"is_final"`
-	Balance       string `json:"balance"`
-	PayerCurrency string `json:"payer_currency"`
-	PayerAmount   string `json:"payer_amount"`
-}
-
-type payoutRawResponse struct {
-	Result *Payout
-	State  int8
+	UUID          UUID         `json:"uuid"`
+	OrderID       string       `json:"order_id"`
+	Amount        Amount       `json:"amount"`
+	Currency      string       `json:"currency"`
+	Network       string       `json:"network"`
+	Address       string       `json:"address"`
+	TxId          string       `json:"txid"`
+	Status        PayoutStatus `json:"status"`
+	IsFinal       bool         `json:"is_final"`
+	Balance       Amount       `json:"balance"`
+	PayerCurrency string       `json:"payer_currency"`
+	PayerAmount   Amount       `json:"payer_amount"`
 }
 
 type PayoutInfoRequest struct {
-	PayoutUUID string `json:"uuid,omitempty"`
+	PayoutUUID UUID   `json:"uuid,omitempty"`
 	OrderID    string `json:"order_id,omitempty"`
 }
 
 type PayoutHistoryResponse struct {
-	Payouts  []*Payout
-	Paginate *PayoutHistoryPaginate
+	MerchantUUID UUID
+	Payouts      []*Payout
+	Paginate     *PayoutHistoryPaginate
 }
 
 type PayoutHistoryPaginate struct {
-	Count          int16  `json:"count"`
+	Count          int    `json:"count"`
 	HasPages       bool   `json:"hasPages"`
 	NextCursor     string `json:"nextCursor,omitempty"`
 	PreviousCursor string `json:"previousCursor,omitempty"`
-	PerPage        int16  `json:"perPage"`
+	PerPage        int    `json:"perPage"`
 }
 
 type payoutHistoryRawResponse struct {
-	State    int8                   `json:"state"`
-	Result   []*Payout              `json:"result"`
-	Paginate *PayoutHistoryPaginate `json:"paginate"`
+	State  int8                 `json:"state"`
+	Result *payoutHistoryResult `json:"result"`
+}
+
+type payoutHistoryResult struct {
+	MerchantUUID UUID                   `json:"merchant_uuid"`
+	Items        []*Payout              `json:"items"`
+	Paginate     *PayoutHistoryPaginate `json:"paginate"`
 }
 
 type PayoutService struct {
@@ -84,87 +87,122 @@ type PayoutService struct {
 }
 
 type PayoutServiceLimit struct {
-	MinAmount string `json:"minAmount"`
-	MaxAmount string `json:"maxAmount"`
+	MinAmount Amount `json:"minAmount"`
+	MaxAmount Amount `json:"maxAmount"`
 }
 
 type PayoutServiceCommision struct {
-	FeeAmount string `json:"feeAmount"`
-	Percent   string `json:"percent"`
+	FeeAmount Amount      `json:"feeAmount"`
+	Percent   json.Number `json:"percent"`
 }
 
-type payoutServiceListRawResponse struct {
-	Result []*PayoutService `json:"result"`
-	State  int8             `json:"state"`
+func (c *Cryptomus) CreatePayout(payoutReq *PayoutRequest) (*Payout, error) {
+	return c.createPayout(context.Background(), payoutReq)
 }
 
-func (c *Cryptomus) CreatePayout(payoutReq *PayoutRequest) (*Payout, error) {
-	res, err := c.fetch("POST", createPayoutEndpoint, payoutReq)
-	if err != nil {
+func (c *Cryptomus) createPayout(ctx context.Context, payoutReq *PayoutRequest) (*Payout, error) {
+	if payoutReq == nil {
+		return nil, errors.New("payout request cannot be nil")
+	}
+	if err := validateNetwork(payoutReq.Currency, payoutReq.Network); err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	response := &payoutRawResponse{}
-	if err = json.NewDecoder(res.Body).Decode(response); err != nil {
+	// Express the amount with the precision of the currency, leaving the request of the caller as is
+	req := *payoutReq
+	amount, err := normalizeAmount("amount", req.Amount, req.Currency, req.Network)
+	if err != nil {
 		return nil, err
 	}
+	req.Amount = amount
 
-	return response.Result, nil
+	return call[*Payout](ctx, c, http.MethodPost, createPayoutEndpoint, &req, AuthPayout)
 }
 
 func (c *Cryptomus) GetPayoutInfo(payoutInfoReq *PayoutInfoRequest) (*Payout, error) {
-	if payoutInfoReq.PayoutUUID == "" && payoutInfoReq.OrderID == "" {
-		return nil, errors.New("you should pass one of required values [PayoutUUID, OrderID]")
-	}
+	return c.getPayoutInfo(context.Background(), payoutInfoReq)
+}
 
-	res, err := c.fetch("POST", payoutInfoEndpoint, payoutInfoReq)
-	if err != nil {
+func (c *Cryptomus) getPayoutInfo(ctx context.Context, payoutInfoReq *PayoutInfoRequest) (*Payout, error) {
+	if err := checkIdentifiers(payoutInfoReq.PayoutUUID, payoutInfoReq.OrderID, "you should pass one of required values [PayoutUUID, OrderID]"); err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	response := &payoutRawResponse{}
-	if err = json.NewDecoder(res.Body).Decode(response); err != nil {
-		return nil, err
+	// Concurrent lookups of the same payout share a single request
+	key := "payout:" + string(payoutInfoReq.PayoutUUID) + "\x00" + payoutInfoReq.OrderID
+	payout, err := coalesce(ctx, c, key, func(ctx context.Context) (*Payout, error) {
+		return call[*Payout](ctx, c, http.MethodPost, payoutInfoEndpoint, payoutInfoReq, AuthPayout)
+	})
+	if err != nil || payout == nil {
+		return payout, err
 	}
 
-	return response.Result, nil
+	copied := *payout
+	return &copied, nil
 }
 
 func (c *Cryptomus) GetPayoutHistory(dateFrom, dateTo time.Time) (*PayoutHistoryResponse, error) {
-	payload := map[string]any{"date_from": dateFrom, "date_to": dateTo}
-	res, err := c.fetch("POST", payoutHistoryEndpoint, payload)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+	return c.payoutHistoryPage(context.Background(), "", &HistoryOptions{}, DateWindow{From: dateFrom, To: dateTo})
+}
 
-	response := &payoutHistoryRawResponse{}
-	if err = json.NewDecoder(res.Body).Decode(response); err != nil {
-		return nil, err
+// PayoutHistory returns an iterator over the payouts selected by the options, following the cursors
+// of the history pages. Options may be nil, in which case the whole history is listed.
+// Call Stream on the iterator to decode the payouts of each page as they are read.
+func (c *Cryptomus) PayoutHistory(ctx context.Context, opts *HistoryOptions) *Iterator[*Payout] {
+	if opts == nil {
+		opts = &HistoryOptions{}
 	}
 
-	payoutHistory := &PayoutHistoryResponse{
-		Payouts:  response.Result,
-		Paginate: response.Paginate,
+	it := newIterator(ctx, opts.windows(), func(ctx context.Context, window DateWindow, cursor string) (*Page[*Payout], error) {
+		history, err := c.payoutHistoryPage(ctx, cursor, opts, window)
+		if err != nil {
+			return nil, err
+		}
+
+		page := &Page[*Payout]{Items: history.Payouts}
+		if history.Paginate != nil {
+			page.NextCursor, page.PreviousCursor = history.Paginate.NextCursor, history.Paginate.PreviousCursor
+		}
+		return page, nil
+	})
+	it.openStream = func(ctx context.Context, window DateWindow, cursor string) (*pageStream[*Payout], error) {
+		return openPageStream[*Payout](ctx, c, cursorEndpoint(payoutHistoryEndpoint, cursor), opts.payload(window), AuthPayout)
 	}
 
-	return payoutHistory, nil
+	return it
 }
 
-func (c *Cryptomus) GetPayoutServicesList() ([]*PayoutService, error) {
-	payload := make(map[string]any)
-	res, err := c.fetch("POST", payoutServicesListEndpoint, payload)
+// ListAllPayouts returns the payouts selected by the history options, following the cursors of the history
+// to completion. It fails with ErrListTruncated, along with the payouts collected so far, if the history
+// is longer than the caps of the options allow. Both options may be nil.
+func (c *Cryptomus) ListAllPayouts(ctx context.Context, historyOpts *HistoryOptions, opts *ListAllOptions) ([]*Payout, error) {
+	return collect(c.PayoutHistory(ctx, historyOpts), opts)
+}
+
+// payoutHistoryPage fetches the page of the payout history in the date window at the cursor, the first page if it is empty.
+func (c *Cryptomus) payoutHistoryPage(ctx context.Context, cursor string, opts *HistoryOptions, window DateWindow) (*PayoutHistoryResponse, error) {
+	payload := opts.payload(window)
+	res, err := c.do(ctx, http.MethodPost, cursorEndpoint(payoutHistoryEndpoint, cursor), payload, AuthPayout)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
-	response := &payoutServiceListRawResponse{}
-	if err = json.NewDecoder(res.Body).Decode(response); err != nil {
+	response := &payoutHistoryRawResponse{}
+	if err = decodeInto(res, response); err != nil {
 		return nil, err
 	}
 
-	return response.Result, nil
+	payoutHistory := &PayoutHistoryResponse{}
+	if response.Result != nil {
+		payoutHistory.MerchantUUID = response.Result.MerchantUUID
+		payoutHistory.Payouts = response.Result.Items
+		payoutHistory.Paginate = response.Result.Paginate
+	}
+
+	return payoutHistory, nil
+}
+
+func (c *Cryptomus) GetPayoutServicesList() ([]*PayoutService, error) {
+	return postPayout[[]*PayoutService](c, payoutServicesListEndpoint, map[string]any{})
 }
@@ -0,0 +1,84 @@
+package cryptomus
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	// defaultCryptoPrecision is the number of decimal places of cryptocurrencies not known to the SDK.
+	defaultCryptoPrecision = 8
+	// defaultFiatPrecision is the number of decimal places of fiat currencies.
+	defaultFiatPrecision = 2
+)
+
+// currencyPrecision lists the decimal places of the known cryptocurrencies, and of the fiat currencies
+// without minor units.
+var currencyPrecision = map[CurrencyCode]int32{
+	CurrencyUSDT: 6, CurrencyUSDC: 6, CurrencyDAI: 18, CurrencyBTC: 8, CurrencyETH: 18,
+	CurrencyLTC: 8, CurrencyBCH: 8, CurrencyDASH: 8, CurrencyDOGE: 8, CurrencyTRX: 6,
+	CurrencyTON: 9, CurrencyBNB: 18, CurrencySOL: 9, CurrencyXMR: 12, CurrencyPOL: 18,
+	CurrencyMATIC: 18, CurrencyAVAX: 18, CurrencySHIB: 18, CurrencyCGPT: 18, CurrencyVERSE: 18,
+	CurrencyHMSTR: 9, CurrencyDOGS: 9, CurrencyNOT: 9,
+	CurrencyJPY: 0, "KRW": 0, "VND": 0, "CLP": 0,
+}
+
+// networkPrecision lists the decimal places of tokens whose precision depends on the network.
+var networkPrecision = map[CurrencyCode]map[Network]int32{
+	CurrencyUSDT: {NetworkBSC: 18},
+	CurrencyUSDC: {NetworkBSC: 18},
+}
+
+// Precision returns the number of decimal places amounts of the currency are expressed with on the network,
+// e.g. 6 for USDT on Tron and 8 for BTC. The network may be empty for currencies that don't depend on it.
+// Unknown cryptocurrencies default to 8 decimal places, and fiat currencies to 2.
+func (c CurrencyCode) Precision(network Network) int32 {
+	c = c.Normalize()
+	if places, ok := networkPrecision[c][network.Normalize()]; ok {
+		return places
+	}
+	if places, ok := currencyPrecision[c]; ok {
+		return places
+	}
+	if c.IsFiat() {
+		return defaultFiatPrecision
+	}
+
+	return defaultCryptoPrecision
+}
+
+// FormatAmount formats the amount rounded to the precision of the currency on the network, without trailing zeros,
+// e.g. "10.123457" for 10.1234567 USDT on Tron.
+func FormatAmount(amount Amount, currency CurrencyCode, network Network) string {
+	return AmountFromDecimal(amount.Round(currency.Precision(network))).String()
+}
+
+// ParseAmount parses the amount of the currency on the network, failing if it has more decimal places
+// than the currency supports, rather than silently rounding it.
+func ParseAmount(s string, currency CurrencyCode, network Network) (Amount, error) {
+	amount, err := NewAmount(s)
+	if err != nil {
+		return Amount{}, err
+	}
+
+	if places := currency.Precision(network); -amount.Exponent() > places && !amount.Equal(amount.Round(places)) {
+		return Amount{}, fmt.Errorf("invalid amount %q: %s supports at most %d decimal places", s, currency.Normalize(), places)
+	}
+
+	return amount, nil
+}
+
+// normalizeAmount expresses the amount of a request field with the precision of the currency on the network.
+// Amounts with more significant decimal places than the currency supports are rejected with ValidationErrors
+// rather than rounded, as rounding up would e.g. pay out more than the caller asked for.
+func normalizeAmount(field string, amount Amount, currency CurrencyCode, network Network) (Amount, error) {
+	places := currency.Precision(network)
+	rounded := amount.Round(places)
+	if !amount.Equal(rounded) {
+		return Amount{}, ValidationErrors{field: {
+			"The " + field + " of " + string(currency.Normalize()) + " supports at most " + strconv.Itoa(int(places)) + " decimal places.",
+		}}
+	}
+
+	return AmountFromDecimal(rounded), nil
+}
@@ -0,0 +1,10 @@
+package cryptomus
+
+// Ptr returns a pointer to v, for setting the optional fields of requests inline,
+// e.g. IsPaymentMultiple: cryptomus.Ptr(false).
+//
+// Optional numeric and boolean fields are pointers, so that zero values such as a 0% discount
+// or a false flag are sent to the API rather than omitted in favor of its defaults.
+func Ptr[T any](v T) *T {
+	return &v
+}
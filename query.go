@@ -0,0 +1,52 @@
+package cryptomus
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// queryValues converts the payload of a read request into query parameters.
+// The payload may be url.Values, or anything encoding to a JSON object, whose scalar fields become
+// parameters and whose nested objects and arrays are sent JSON-encoded. Null fields are omitted.
+func queryValues(payload interface{}) (url.Values, error) {
+	switch p := payload.(type) {
+	case url.Values:
+		return p, nil
+	case map[string]string:
+		values := url.Values{}
+		for name, value := range p {
+			values.Set(name, value)
+		}
+		return values, nil
+	}
+
+	data, err := MarshalCanonical(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("payload is not a JSON object: %w", err)
+	}
+
+	values := url.Values{}
+	for name, raw := range fields {
+		switch {
+		case bytes.Equal(raw, []byte("null")):
+			continue
+		case len(raw) > 0 && raw[0] == '"':
+			var value string
+			if err := json.Unmarshal(raw, &value); err != nil {
+				return nil, err
+			}
+			values.Set(name, value)
+		default:
+			values.Set(name, string(raw))
+		}
+	}
+
+	return values, nil
+}
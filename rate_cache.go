@@ -0,0 +1,240 @@
+package cryptomus
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultExchangeRateCacheTTL is the period during which cached exchange rates are served without refreshing.
+const DefaultExchangeRateCacheTTL = time.Minute
+
+// ExchangeRateCacheOptions configures an ExchangeRateCache.
+type ExchangeRateCacheOptions struct {
+	TTL             time.Duration // Period during which cached rates are fresh, DefaultExchangeRateCacheTTL if zero
+	StaleTTL        time.Duration // Additional period during which stale rates are served while being refreshed in the background
+	RefreshInterval time.Duration // Interval of the background refresher, disabled if zero
+	Currencies      []string      // Currencies warmed up and kept fresh by the background refresher
+	Clock           Clock         // Ages the cached rates and ticks the refresher, SystemClock if nil
+}
+
+// ExchangeRateCache is a caching layer in front of ListExchangeRates.
+// It is safe for concurrent use.
+type ExchangeRateCache struct {
+	provider RateProvider
+	opts     ExchangeRateCacheOptions
+
+	mu      sync.Mutex
+	entries map[string]*rateCacheEntry
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// rateCacheEntry holds the cached exchange rates of a single currency.
+type rateCacheEntry struct {
+	rates      []ExchangeRate
+	fetchedAt  time.Time
+	refreshing bool
+}
+
+// NewExchangeRateCache creates a new exchange rate cache backed by the provider,
+// which is usually the *Cryptomus client.
+// Options may be nil, in which case the defaults are used.
+// If a refresh interval is configured, a background refresher is started and
+// must be stopped with Close.
+func NewExchangeRateCache(provider RateProvider, opts *ExchangeRateCacheOptions) *ExchangeRateCache {
+	rc := &ExchangeRateCache{
+		provider: provider,
+		entries:  make(map[string]*rateCacheEntry),
+		stop:     make(chan struct{}),
+	}
+	if opts != nil {
+		rc.opts = *opts
+	}
+	if rc.opts.TTL <= 0 {
+		rc.opts.TTL = DefaultExchangeRateCacheTTL
+	}
+	rc.opts.Clock = clockOrSystem(rc.opts.Clock)
+
+	if rc.opts.RefreshInterval > 0 {
+		rc.wg.Add(1)
+		go rc.refreshLoop()
+	}
+
+	return rc
+}
+
+// ListExchangeRates returns the exchange rates for the currency from the cache.
+// Fresh rates are returned as is, stale rates are returned while a background refresh
+// is triggered, and expired or missing rates are fetched from the provider synchronously.
+func (rc *ExchangeRateCache) ListExchangeRates(ctx context.Context, currency string, opts *ExchangeRateOptions) ([]ExchangeRate, error) {
+	key := rateCacheKey(currency)
+
+	rc.mu.Lock()
+	entry, ok := rc.entries[key]
+	if ok {
+		age := rc.opts.Clock.Now().Sub(entry.fetchedAt)
+		if age < rc.opts.TTL {
+			rates := cachedRates(entry.rates)
+			rc.mu.Unlock()
+			return opts.filter(rates), nil
+		}
+
+		if age < rc.opts.TTL+rc.opts.StaleTTL {
+			if !entry.refreshing && !rc.closed() {
+				entry.refreshing = true
+				rc.wg.Add(1)
+				go func() {
+					defer rc.wg.Done()
+					_ = rc.refresh(context.Background(), key)
+				}()
+			}
+			rates := cachedRates(entry.rates)
+			rc.mu.Unlock()
+			return opts.filter(rates), nil
+		}
+	}
+	rc.mu.Unlock()
+
+	rates, err := rc.fetch(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return opts.filter(rates), nil
+}
+
+// Invalidate removes the cached exchange rates of the currency.
+func (rc *ExchangeRateCache) Invalidate(currency string) {
+	rc.mu.Lock()
+	delete(rc.entries, rateCacheKey(currency))
+	rc.mu.Unlock()
+}
+
+// Close stops the background refresher and waits for in-flight refreshes to finish.
+// Stale rates are still served after Close, but no longer refreshed in the background.
+func (rc *ExchangeRateCache) Close() {
+	rc.stopOnce.Do(func() {
+		// Under mu, so that no refresh is started once Close waits for them
+		rc.mu.Lock()
+		close(rc.stop)
+		rc.mu.Unlock()
+	})
+	rc.wg.Wait()
+}
+
+// closed reports whether Close was called. It must be called with mu held.
+func (rc *ExchangeRateCache) closed() bool {
+	select {
+	case <-rc.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// fetch requests the exchange rates of the currency from the provider and stores them in the cache.
+func (rc *ExchangeRateCache) fetch(ctx context.Context, key string) ([]ExchangeRate, error) {
+	rates, err := rc.provider.ListExchangeRates(ctx, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchedAt := rc.opts.Clock.Now()
+	for _, rate := range rates {
+		if !rate.FetchedAt.IsZero() {
+			fetchedAt = rate.FetchedAt
+			break
+		}
+	}
+
+	rc.mu.Lock()
+	rc.entries[key] = &rateCacheEntry{rates: rates, fetchedAt: fetchedAt}
+	rc.mu.Unlock()
+
+	return rates, nil
+}
+
+// refresh fetches the exchange rates of the currency, keeping the cached ones on failure.
+func (rc *ExchangeRateCache) refresh(ctx context.Context, key string) error {
+	_, err := rc.fetch(ctx, key)
+	if err != nil {
+		rc.mu.Lock()
+		if entry, ok := rc.entries[key]; ok {
+			entry.refreshing = false
+		}
+		rc.mu.Unlock()
+	}
+
+	return err
+}
+
+// refreshLoop periodically refreshes the configured and previously requested currencies.
+func (rc *ExchangeRateCache) refreshLoop() {
+	defer rc.wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-rc.stop
+		cancel()
+	}()
+
+	ticker := rc.opts.Clock.NewTicker(rc.opts.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, key := range rc.keys() {
+			_ = rc.refresh(ctx, key)
+		}
+
+		select {
+		case <-rc.stop:
+			return
+		case <-ticker.C():
+		}
+	}
+}
+
+// keys returns the currencies to be refreshed by the background refresher.
+func (rc *ExchangeRateCache) keys() []string {
+	seen := make(map[string]bool)
+	keys := make([]string, 0, len(rc.opts.Currencies))
+	for _, currency := range rc.opts.Currencies {
+		key := rateCacheKey(currency)
+		if key != "" && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	rc.mu.Lock()
+	for key := range rc.entries {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	rc.mu.Unlock()
+
+	return keys
+}
+
+// rateCacheKey normalizes the currency code used as a cache key.
+func rateCacheKey(currency string) string {
+	return strings.ToUpper(strings.TrimSpace(currency))
+}
+
+// cachedRates copies the cached rates, marking them as served from the cache.
+func cachedRates(rates []ExchangeRate) []ExchangeRate {
+	copied := make([]ExchangeRate, len(rates))
+	for i, rate := range rates {
+		rate.CacheHit = true
+		copied[i] = rate
+	}
+
+	return copied
+}
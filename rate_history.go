@@ -0,0 +1,169 @@
+package cryptomus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrRateSnapshotNotFound is returned when no rate snapshot was recorded before the requested time.
+var ErrRateSnapshotNotFound = errors.New("rate snapshot not found")
+
+// RateSnapshot represents the exchange rates of a currency as they were fetched at a point in time.
+type RateSnapshot struct {
+	Currency  string
+	Rates     []ExchangeRate
+	FetchedAt time.Time
+}
+
+// RateSnapshotStore persists rate snapshots, e.g. in a SQL table keyed by currency and fetch time.
+type RateSnapshotStore interface {
+	// SaveRateSnapshot stores the snapshot.
+	SaveRateSnapshot(ctx context.Context, snapshot *RateSnapshot) error
+	// RateSnapshotAt returns the latest snapshot of the currency fetched at or before the time,
+	// or ErrRateSnapshotNotFound if there is none.
+	RateSnapshotAt(ctx context.Context, currency string, at time.Time) (*RateSnapshot, error)
+}
+
+// RecordingRateProvider is a RateProvider recording every fetched rate list into a store,
+// enabling lookups like "what was the USDT/EUR course when the invoice was created".
+// Rate lists served again from a cache, e.g. an ExchangeRateCache, are recorded once.
+type RecordingRateProvider struct {
+	Provider RateProvider
+	Store    RateSnapshotStore
+	// OnError is optionally invoked when a snapshot cannot be saved.
+	// Recording failures never fail the rate lookup itself.
+	OnError func(error)
+	// Clock optionally dates the snapshots of rates fetched without a time, SystemClock if nil.
+	Clock Clock
+
+	mu       sync.Mutex
+	recorded map[string]time.Time // Fetch time of the last snapshot saved for each currency
+}
+
+// NewRecordingRateProvider creates a new recording provider.
+func NewRecordingRateProvider(provider RateProvider, store RateSnapshotStore) *RecordingRateProvider {
+	return &RecordingRateProvider{Provider: provider, Store: store}
+}
+
+// ListExchangeRates fetches the full rate list of the currency, records it, and returns it filtered by opts.
+func (p *RecordingRateProvider) ListExchangeRates(ctx context.Context, currency string, opts *ExchangeRateOptions) ([]ExchangeRate, error) {
+	rates, err := p.Provider.ListExchangeRates(ctx, currency, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// The snapshot keeps its own copy, as the caller may modify the rates returned
+	snapshot := &RateSnapshot{
+		Currency:  rateCacheKey(currency),
+		Rates:     append([]ExchangeRate(nil), rates...),
+		FetchedAt: clockOrSystem(p.Clock).Now(),
+	}
+	if len(rates) > 0 && !rates[0].FetchedAt.IsZero() {
+		snapshot.FetchedAt = rates[0].FetchedAt
+	}
+	if p.record(snapshot, rates) {
+		if err := p.Store.SaveRateSnapshot(ctx, snapshot); err != nil {
+			p.forget(snapshot)
+			if p.OnError != nil {
+				p.OnError(fmt.Errorf("failed to save rate snapshot: %w", err))
+			}
+		}
+	}
+
+	return opts.filter(rates), nil
+}
+
+// record reports whether the snapshot is to be saved: cached rates that cannot be dated and rates
+// fetched at the same time as the last snapshot of the currency were already recorded.
+func (p *RecordingRateProvider) record(snapshot *RateSnapshot, rates []ExchangeRate) bool {
+	if len(rates) == 0 || rates[0].FetchedAt.IsZero() {
+		return len(rates) == 0 || !rates[0].CacheHit
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if last, ok := p.recorded[snapshot.Currency]; ok && last.Equal(snapshot.FetchedAt) {
+		return false
+	}
+	if p.recorded == nil {
+		p.recorded = make(map[string]time.Time)
+	}
+	p.recorded[snapshot.Currency] = snapshot.FetchedAt
+
+	return true
+}
+
+// forget lets the snapshot be recorded again after it failed to be saved.
+func (p *RecordingRateProvider) forget(snapshot *RateSnapshot) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if last, ok := p.recorded[snapshot.Currency]; ok && last.Equal(snapshot.FetchedAt) {
+		delete(p.recorded, snapshot.Currency)
+	}
+}
+
+// RateAt returns the recorded from/to exchange rate as it was known at the time,
+// together with the time it was fetched.
+func (p *RecordingRateProvider) RateAt(ctx context.Context, from, to string, at time.Time) (ExchangeRate, time.Time, error) {
+	snapshot, err := p.Store.RateSnapshotAt(ctx, rateCacheKey(from), at)
+	if err != nil {
+		return ExchangeRate{}, time.Time{}, err
+	}
+
+	rates := FilterExchangeRates(snapshot.Rates, to)
+	if len(rates) == 0 {
+		return ExchangeRate{}, time.Time{}, fmt.Errorf("%w: no %s/%s rate at %s", ErrRateSnapshotNotFound, from, to, at.Format(time.RFC3339))
+	}
+
+	return rates[0], snapshot.FetchedAt, nil
+}
+
+// MemoryRateSnapshotStore is an in-memory RateSnapshotStore, mainly useful for tests
+// and short-lived processes. It is safe for concurrent use.
+type MemoryRateSnapshotStore struct {
+	mu        sync.RWMutex
+	snapshots map[string][]*RateSnapshot
+}
+
+// NewMemoryRateSnapshotStore creates a new in-memory snapshot store.
+func NewMemoryRateSnapshotStore() *MemoryRateSnapshotStore {
+	return &MemoryRateSnapshotStore{snapshots: make(map[string][]*RateSnapshot)}
+}
+
+// SaveRateSnapshot stores the snapshot, keeping the snapshots of each currency ordered by fetch time.
+func (s *MemoryRateSnapshotStore) SaveRateSnapshot(ctx context.Context, snapshot *RateSnapshot) error {
+	key := rateCacheKey(snapshot.Currency)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := s.snapshots[key]
+	i := sort.Search(len(list), func(i int) bool { return list[i].FetchedAt.After(snapshot.FetchedAt) })
+	list = append(list, nil)
+	copy(list[i+1:], list[i:])
+	list[i] = snapshot
+	s.snapshots[key] = list
+
+	return nil
+}
+
+// RateSnapshotAt returns the latest snapshot of the currency fetched at or before the time.
+func (s *MemoryRateSnapshotStore) RateSnapshotAt(ctx context.Context, currency string, at time.Time) (*RateSnapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := s.snapshots[rateCacheKey(currency)]
+	i := sort.Search(len(list), func(i int) bool { return list[i].FetchedAt.After(at) })
+	if i == 0 {
+		return nil, fmt.Errorf("%w: no %s rates at %s", ErrRateSnapshotNotFound, strings.ToUpper(currency), at.Format(time.RFC3339))
+	}
+
+	return list[i-1], nil
+}
@@ -0,0 +1,92 @@
+package cryptomus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RateProvider is a source of exchange rates.
+// *Cryptomus is the default implementation, backed by the exchange rate endpoint,
+// while ExchangeRateCache and ChainedRateProvider can be layered on top of it.
+type RateProvider interface {
+	ListExchangeRates(ctx context.Context, currency string, opts *ExchangeRateOptions) ([]ExchangeRate, error)
+}
+
+// RateProviderFunc adapts an ordinary function to the RateProvider interface.
+type RateProviderFunc func(ctx context.Context, currency string, opts *ExchangeRateOptions) ([]ExchangeRate, error)
+
+// ListExchangeRates calls f(ctx, currency, opts).
+func (f RateProviderFunc) ListExchangeRates(ctx context.Context, currency string, opts *ExchangeRateOptions) ([]ExchangeRate, error) {
+	return f(ctx, currency, opts)
+}
+
+var (
+	_ RateProvider = (*Cryptomus)(nil)
+	_ RateProvider = (*ExchangeRateCache)(nil)
+	_ RateProvider = (*ChainedRateProvider)(nil)
+)
+
+// ChainedRateProvider queries its providers in order and returns the first usable result.
+// A result is unusable when the provider fails, returns no rates, or the rates are stale.
+type ChainedRateProvider struct {
+	Providers []RateProvider
+	// MaxAge optionally rejects rates fetched longer ago than the duration.
+	MaxAge time.Duration
+	// IsStale optionally reports whether rates returned by a provider are too old to be used.
+	IsStale func(rates []ExchangeRate) bool
+	// Clock ages the rates for MaxAge, SystemClock if nil. It must be the clock the rates were fetched with.
+	Clock Clock
+}
+
+// NewChainedRateProvider creates a chained provider falling back from the primary
+// provider (usually the *Cryptomus client) to the user-supplied fallbacks.
+func NewChainedRateProvider(primary RateProvider, fallbacks ...RateProvider) *ChainedRateProvider {
+	return &ChainedRateProvider{
+		Providers: append([]RateProvider{primary}, fallbacks...),
+	}
+}
+
+// ListExchangeRates returns the rates of the first provider with a usable result.
+// If none of the providers has one, the errors of all providers are joined.
+func (p *ChainedRateProvider) ListExchangeRates(ctx context.Context, currency string, opts *ExchangeRateOptions) ([]ExchangeRate, error) {
+	if len(p.Providers) == 0 {
+		return nil, errors.New("no rate providers configured")
+	}
+
+	var errs []error
+	for i, provider := range p.Providers {
+		rates, err := provider.ListExchangeRates(ctx, currency, opts)
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("rate provider %d: %w", i, err))
+		case len(rates) == 0:
+			errs = append(errs, fmt.Errorf("rate provider %d: no rates for %s", i, currency))
+		case p.stale(rates):
+			errs = append(errs, fmt.Errorf("rate provider %d: stale rates for %s", i, currency))
+		default:
+			return rates, nil
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// stale reports whether any of the rates is too old according to MaxAge or IsStale.
+func (p *ChainedRateProvider) stale(rates []ExchangeRate) bool {
+	if p.MaxAge > 0 {
+		now := clockOrSystem(p.Clock).Now()
+		for _, rate := range rates {
+			if rate.AgeAt(now) > p.MaxAge {
+				return true
+			}
+		}
+	}
+
+	return p.IsStale != nil && p.IsStale(rates)
+}
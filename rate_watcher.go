@@ -0,0 +1,146 @@
+package cryptomus
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultRateWatcherInterval is the polling interval used when none is configured.
+const DefaultRateWatcherInterval = time.Minute
+
+// RatePair represents a currency pair watched by a RateWatcher.
+type RatePair struct {
+	From string
+	To   string
+}
+
+// RateChange represents a rate movement reported by a RateWatcher.
+type RateChange struct {
+	Pair     RatePair
+	Previous decimal.Decimal // Course at the last report
+	Current  decimal.Decimal // Course that triggered the report
+	Change   decimal.Decimal // Relative change, e.g. -0.015 for a 1.5% drop
+	At       time.Time
+}
+
+// RateWatcherOptions configures a RateWatcher.
+type RateWatcherOptions struct {
+	Pairs     []RatePair
+	Interval  time.Duration         // Polling interval, DefaultRateWatcherInterval if zero
+	Threshold float64               // Minimal relative change reported, e.g. 0.01 for 1%
+	OnChange  func(RateChange)      // Required: Invoked when a rate moves beyond the threshold
+	OnError   func(RatePair, error) // Optional: Invoked when a rate cannot be resolved
+	Clock     Clock                 // Optional: Ticks the polls and dates the changes, SystemClock if nil
+}
+
+// RateWatcher polls exchange rates of selected pairs and reports movements
+// beyond a threshold, e.g. for re-pricing open invoices. It is safe for concurrent use.
+type RateWatcher struct {
+	provider  RateProvider
+	opts      RateWatcherOptions
+	threshold decimal.Decimal
+
+	mu   sync.Mutex
+	last map[RatePair]decimal.Decimal // Baseline course of each pair, guarded by mu
+}
+
+// NewRateWatcher creates a new rate watcher resolving rates through the provider.
+func NewRateWatcher(provider RateProvider, opts RateWatcherOptions) (*RateWatcher, error) {
+	if len(opts.Pairs) == 0 {
+		return nil, errors.New("at least one pair must be watched")
+	}
+	if opts.OnChange == nil {
+		return nil, errors.New("OnChange callback is required")
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultRateWatcherInterval
+	}
+	opts.Clock = clockOrSystem(opts.Clock)
+
+	pairs := make([]RatePair, len(opts.Pairs))
+	for i, pair := range opts.Pairs {
+		pairs[i] = RatePair{
+			From: strings.ToUpper(strings.TrimSpace(pair.From)),
+			To:   strings.ToUpper(strings.TrimSpace(pair.To)),
+		}
+	}
+	opts.Pairs = pairs
+
+	return &RateWatcher{
+		provider:  provider,
+		opts:      opts,
+		threshold: decimal.NewFromFloat(opts.Threshold).Abs(),
+		last:      make(map[RatePair]decimal.Decimal),
+	}, nil
+}
+
+// Run polls the rates until the context is done.
+// The first observed course of each pair is used as the baseline, and the baseline
+// moves to the current course every time a change is reported. Concurrent calls share
+// the baselines, so that a movement is reported once.
+func (w *RateWatcher) Run(ctx context.Context) error {
+	ticker := w.opts.Clock.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		w.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C():
+		}
+	}
+}
+
+// poll resolves the current course of every pair and reports the movements.
+func (w *RateWatcher) poll(ctx context.Context) {
+	for _, pair := range w.opts.Pairs {
+		_, course, _, err := resolveCourse(ctx, w.provider, pair.From, pair.To)
+		if err != nil {
+			if w.opts.OnError != nil && ctx.Err() == nil {
+				w.opts.OnError(pair, err)
+			}
+			continue
+		}
+
+		previous, change, ok := w.move(pair, course)
+		if !ok {
+			continue
+		}
+
+		w.opts.OnChange(RateChange{
+			Pair:     pair,
+			Previous: previous,
+			Current:  course,
+			Change:   change,
+			At:       w.opts.Clock.Now(),
+		})
+	}
+}
+
+// move compares the course of the pair to its baseline, moving the baseline to the course
+// and returning the previous one if the change is to be reported.
+func (w *RateWatcher) move(pair RatePair, course decimal.Decimal) (decimal.Decimal, decimal.Decimal, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	previous, ok := w.last[pair]
+	if !ok || previous.IsZero() {
+		w.last[pair] = course
+		return decimal.Decimal{}, decimal.Decimal{}, false
+	}
+
+	change := course.Sub(previous).Div(previous)
+	if change.Abs().LessThan(w.threshold) || change.IsZero() {
+		return decimal.Decimal{}, decimal.Decimal{}, false
+	}
+	w.last[pair] = course
+
+	return previous, change, true
+}
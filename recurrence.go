@@ -1,10 +1,11 @@
 package cryptomus
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
-	"time"
+	"net/http"
+	"strings"
 )
 
 // Endpoint constants for recurring payments
@@ -17,58 +18,46 @@ const (
 
 // RecurrenceRequest represents the request structure for creating a recurring payment.
 type RecurrenceRequest struct {
-	Amount         string `json:"amount"`                    // Required: Amount of the payment
-	Currency       string `json:"currency"`                  // Required: Currency code (e.g., "USD")
-	Name           string `json:"name"`                      // Required: Name or description of the payment
-	Period         string `json:"period"`                    // Required: Recurrence period (e.g., "monthly")
-	ToCurrency     string `json:"to_currency,omitempty"`     // Optional: Target currency
-	OrderID        string `json:"order_id,omitempty"`        // Optional: Order identifier in your system
-	UrlCallback    string `json:"url_callback,omitempty"`    // Optional: Callback URL for payment status updates
-	DiscountDays   int    `json:"discount_days,omitempty"`   // Optional: Number of days for discount eligibility
-	DiscountAmount string `json:"discount_amount,omitempty"` // Optional: Amount of discount
-	AdditionalData string `json:"additional_data,omitempty"` // Optional: Additional data for the payment
+	Amount         Amount         `json:"amount" validate:"amount"`                                                     // Required: Amount of the payment
+	Currency       CurrencyCode   `json:"currency" validate:"required,currency"`                                        // Required: Currency code (e.g., "USD")
+	Name           string         `json:"name" validate:"required,max=60"`                                              // Required: Name or description of the payment
+	Period         string         `json:"period" validate:"required,oneof=weekly monthly three_month half_year yearly"` // Required: Recurrence period (e.g., "monthly")
+	ToCurrency     string         `json:"to_currency,omitempty"`                                                        // Optional: Target currency
+	OrderID        string         `json:"order_id,omitempty" validate:"max=128"`                                        // Optional: Order identifier in your system
+	UrlCallback    string         `json:"url_callback,omitempty" validate:"url,max=255"`                                // Optional: Callback URL for payment status updates
+	DiscountDays   *int           `json:"discount_days,omitempty" validate:"min=0"`                                     // Optional: Number of days for discount eligibility
+	DiscountAmount *Amount        `json:"discount_amount,omitempty" validate:"amount"`                                  // Optional: Amount of discount
+	AdditionalData AdditionalData `json:"additional_data,omitempty" validate:"max=255"`                                 // Optional: Additional data for the payment
 }
 
 // Recurrence represents the response structure for a recurring payment.
 type Recurrence struct {
-	UUID           string    `json:"uuid"`                      // Unique identifier for the recurring payment
-	Name           string    `json:"name"`                      // Name or description of the payment
-	OrderID        string    `json:"order_id"`                  // Order identifier in your system
-	Amount         string    `json:"amount"`                    // Amount of the payment
-	Currency       string    `json:"currency"`                  // Currency code (e.g., "USD")
-	PayerCurrency  string    `json:"payer_currency"`            // Currency used by the payer
-	PayerAmountUSD string    `json:"payer_amount_usd"`          // Payer amount in USD
-	PayerAmount    string    `json:"payer_amount"`              // Amount paid by the payer
-	UrlCallback    string    `json:"url_callback"`              // Callback URL for payment status updates
-	Period         string    `json:"period"`                    // Recurrence period (e.g., "monthly")
-	Status         string    `json:"status"`                    // Current status of the payment
-	Url            string    `json:"url"`                       // URL for payment processing
-	LastPayOff     time.Time `json:"last_pay_off,omitempty"`    // Optional: Timestamp of the last payment
-	DiscountDays   int       `json:"discount_days,omitempty"`   // Optional: Number of discount days
-	DiscountAmount string    `json:"discount_amount,omitempty"` // Optional: Amount of discount
-	EndOfDiscount  time.Time `json:"end_of_discount,omitempty"` // Optional: Timestamp when the discount ends
-	AdditionalData string    `json:"additional_data,omitempty"` // Optional: Additional data for the payment
-}
-
-// recurrenceRawResponse represents the raw response structure from the API for recurring payments.
-type recurrenceRawResponse struct {
-	State  int8        `json:"state"`  // State code indicating success or error
-	Result *Recurrence `json:"result"` // Resulting Recurrence object on success
+	UUID           UUID             `json:"uuid"`                      // Unique identifier for the recurring payment
+	Name           string           `json:"name"`                      // Name or description of the payment
+	OrderID        string           `json:"order_id"`                  // Order identifier in your system
+	Amount         Amount           `json:"amount"`                    // Amount of the payment
+	Currency       string           `json:"currency"`                  // Currency code (e.g., "USD")
+	ToCurrency     string           `json:"to_currency,omitempty"`     // Optional: Target currency
+	PayerCurrency  string           `json:"payer_currency"`            // Currency used by the payer
+	PayerAmountUSD Amount           `json:"payer_amount_usd"`          // Payer amount in USD
+	PayerAmount    Amount           `json:"payer_amount"`              // Amount paid by the payer
+	UrlCallback    string           `json:"url_callback"`              // Callback URL for payment status updates
+	Period         string           `json:"period"`                    // Recurrence period (e.g., "monthly")
+	Status         RecurrenceStatus `json:"status"`                    // Current status of the payment
+	Url            string           `json:"url"`                       // URL for payment processing
+	LastPayOff     *CryptomusTime   `json:"last_pay_off,omitempty"`    // Optional: Timestamp of the last payment
+	DiscountDays   int              `json:"discount_days,omitempty"`   // Optional: Number of discount days
+	DiscountAmount Amount           `json:"discount_amount,omitempty"` // Optional: Amount of discount
+	EndOfDiscount  *CryptomusTime   `json:"end_of_discount,omitempty"` // Optional: Timestamp when the discount ends
+	AdditionalData AdditionalData   `json:"additional_data,omitempty"` // Optional: Additional data for the payment
 }
 
 // RecurrenceInfoRequest represents the request structure for retrieving information about a recurring payment.
 type RecurrenceInfoRequest struct {
-	UUID    string `json:"uuid,omitempty"`     // Optional: UUID of the recurring payment
+	UUID    UUID   `json:"uuid,omitempty"`     // Optional: UUID of the recurring payment
 	OrderID string `json:"order_id,omitempty"` // Optional: Order identifier in your system
 }
 
-// recurrenceInfoRawResponse represents the raw response structure from the API for retrieving recurring payment information.
-type recurrenceInfoRawResponse struct {
-	State  int8                `json:"state"`            // State code indicating success or error
-	Result *Recurrence         `json:"result,omitempty"` // Resulting Recurrence object on success
-	Errors map[string][]string `json:"errors,omitempty"` // Validation errors if any
-}
-
 // RecurrenceListResponse represents the response structure for listing recurring payments.
 type RecurrenceListResponse struct {
 	Items    []*Recurrence       `json:"items"`    // List of recurring payments
@@ -84,60 +73,89 @@ type RecurrencePaginate struct {
 	PerPage        int    `json:"perPage"`                  // Number of items per page
 }
 
-// recurrenceListRawResponse represents the raw response structure from the API for listing recurring payments.
-type recurrenceListRawResponse struct {
-	State  int                     `json:"state"`  // State code indicating success or error
-	Result *RecurrenceListResponse `json:"result"` // Resulting RecurrenceListResponse object on success
+// RecurrenceListOptions represents the options for listing recurring payments.
+// The API doesn't filter by status or currency, so these filters are applied to each page once it is
+// retrieved: a filtered page may hold fewer items than PerPage, or none at all, while its NextCursor is
+// still set. Keep following the cursors, or use Recurrences or ListAllRecurrences, which do so.
+type RecurrenceListOptions struct {
+	Cursor   string           // Optional: Cursor of the page to retrieve
+	Status   RecurrenceStatus // Optional: Only return recurring payments with this status
+	Currency string           // Optional: Only return recurring payments in this currency
+	PerPage  int              // Optional: Number of items per page requested from the API
+}
+
+// apply filters the listed recurring payments according to the options that are not supported by the API.
+func (o *RecurrenceListOptions) apply(list *RecurrenceListResponse) *RecurrenceListResponse {
+	if o.Status == "" && o.Currency == "" {
+		return list
+	}
+
+	items := make([]*Recurrence, 0, len(list.Items))
+	for _, item := range list.Items {
+		if o.Status != "" && item.Status != o.Status {
+			continue
+		}
+		if o.Currency != "" && !strings.EqualFold(item.Currency, o.Currency) {
+			continue
+		}
+		items = append(items, item)
+	}
+
+	return &RecurrenceListResponse{
+		Items:    items,
+		Paginate: list.Paginate,
+	}
 }
 
 // RecurrenceCancelRequest represents the request structure for canceling a recurring payment.
 type RecurrenceCancelRequest struct {
-	UUID    string `json:"uuid,omitempty"`     // Optional: UUID of the recurring payment to cancel
+	UUID    UUID   `json:"uuid,omitempty"`     // Optional: UUID of the recurring payment to cancel
 	OrderID string `json:"order_id,omitempty"` // Optional: Order identifier in your system
 }
 
-// recurrenceCancelRawResponse represents the raw response structure from the API for canceling a recurring payment.
-type recurrenceCancelRawResponse struct {
-	State  int8                `json:"state"`            // State code indicating success or error
-	Result *Recurrence         `json:"result,omitempty"` // Resulting Recurrence object on success
-	Errors map[string][]string `json:"errors,omitempty"` // Validation errors if any
-}
-
 // CreateRecurrence creates a new recurring payment.
 func (c *Cryptomus) CreateRecurrence(recReq *RecurrenceRequest) (*Recurrence, error) {
 	if recReq == nil {
 		return nil, errors.New("recurrence request cannot be nil")
 	}
 
-	// Send a POST request to create a recurring payment
-	res, err := c.fetch("POST", createRecurrenceEndpoint, recReq)
+	req, err := normalizeRecurrenceRequest(recReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, err
 	}
-	defer res.Body.Close()
 
-	// Check for unexpected HTTP status codes
-	if res.StatusCode != 200 {
-		return nil, fmt.Errorf("unexpected HTTP status: %s", res.Status)
+	// Send a POST request to create a recurring payment, failing with an *APIError on a non-200 status or a non-zero state
+	result, err := post[*Recurrence](c, createRecurrenceEndpoint, req)
+	if err != nil {
+		return nil, err
 	}
 
-	// Decode the JSON response
-	response := &recurrenceRawResponse{}
-	if err = json.NewDecoder(res.Body).Decode(response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	// Ensure the result is not nil
+	if result == nil {
+		return nil, errors.New("API response result is nil")
 	}
 
-	// Check the state of the response
-	if response.State != 0 {
-		return nil, fmt.Errorf("API returned non-zero state: %d", response.State)
-	}
+	return result, nil
+}
 
-	// Ensure the result is not nil
-	if response.Result == nil {
-		return nil, errors.New("API response result is nil")
+// normalizeRecurrenceRequest expresses the amounts of the request with the precision of the currency,
+// leaving the request of the caller as is.
+func normalizeRecurrenceRequest(recReq *RecurrenceRequest) (*RecurrenceRequest, error) {
+	req := *recReq
+	amount, err := normalizeAmount("amount", req.Amount, req.Currency, "")
+	if err != nil {
+		return nil, err
+	}
+	req.Amount = amount
+	if req.DiscountAmount != nil {
+		discount, err := normalizeAmount("discount_amount", *req.DiscountAmount, req.Currency, "")
+		if err != nil {
+			return nil, err
+		}
+		req.DiscountAmount = &discount
 	}
 
-	return response.Result, nil
+	return &req, nil
 }
 
 // GetRecurrenceInfo retrieves information about a specific recurring payment using UUID or OrderID.
@@ -146,84 +164,95 @@ func (c *Cryptomus) GetRecurrenceInfo(infoReq *RecurrenceInfoRequest) (*Recurren
 		return nil, errors.New("recurrence info request cannot be nil")
 	}
 
-	if infoReq.UUID == "" && infoReq.OrderID == "" {
-		return nil, errors.New("either uuid or order_id must be provided")
+	if err := checkIdentifiers(infoReq.UUID, infoReq.OrderID, "either uuid or order_id must be provided"); err != nil {
+		return nil, err
 	}
 
-	// Send a POST request to retrieve recurring payment information
-	res, err := c.fetch("POST", recurrenceInfoEndpoint, infoReq)
+	// Send a POST request to retrieve recurring payment information, failing with an *APIError on a non-200 status or a non-zero state
+	result, err := post[*Recurrence](c, recurrenceInfoEndpoint, infoReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, err
 	}
-	defer res.Body.Close()
 
-	// Handle non-200 HTTP status codes by attempting to decode validation errors
-	if res.StatusCode != 200 {
-		var errorResponse recurrenceInfoRawResponse
-		if decodeErr := json.NewDecoder(res.Body).Decode(&errorResponse); decodeErr == nil && errorResponse.Errors != nil {
-			return nil, fmt.Errorf("validation errors: %v", errorResponse.Errors)
-		}
-		return nil, fmt.Errorf("unexpected HTTP status: %s", res.Status)
+	// Ensure the result is not nil
+	if result == nil {
+		return nil, errors.New("API response result is nil")
 	}
 
-	// Decode the JSON response
-	response := &recurrenceInfoRawResponse{}
-	if err = json.NewDecoder(res.Body).Decode(response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	return result, nil
+}
+
+// ListRecurrences retrieves a list of recurring payments.
+// Options may be nil, in which case the first page is returned unfiltered.
+// The cursor and page size are passed through to the API, while the status and
+// currency filters are applied client-side to the returned page, which may therefore be empty even
+// though more pages follow.
+func (c *Cryptomus) ListRecurrences(opts *RecurrenceListOptions) (*RecurrenceListResponse, error) {
+	return c.listRecurrences(context.Background(), opts)
+}
+
+// Recurrences returns an iterator over the recurring payments, starting at the cursor of the options
+// and following the cursors of the pages. Options may be nil; the status and currency filters are
+// applied client-side to each page.
+func (c *Cryptomus) Recurrences(ctx context.Context, opts *RecurrenceListOptions) *Iterator[*Recurrence] {
+	listOpts := RecurrenceListOptions{}
+	if opts != nil {
+		listOpts = *opts
 	}
+	first := listOpts.Cursor
 
-	// Check the state of the response and handle validation errors
-	if response.State != 0 {
-		if response.Errors != nil {
-			return nil, fmt.Errorf("validation errors: %v", response.Errors)
+	return newIterator(ctx, nil, func(ctx context.Context, _ DateWindow, cursor string) (*Page[*Recurrence], error) {
+		listOpts.Cursor = cursor
+		if cursor == "" {
+			listOpts.Cursor = first
 		}
-		return nil, fmt.Errorf("API returned non-zero state: %d", response.State)
-	}
 
-	// Ensure the result is not nil
-	if response.Result == nil {
-		return nil, errors.New("API response result is nil")
-	}
+		list, err := c.listRecurrences(ctx, &listOpts)
+		if err != nil {
+			return nil, err
+		}
 
-	return response.Result, nil
+		page := &Page[*Recurrence]{Items: list.Items}
+		if list.Paginate != nil {
+			page.NextCursor, page.PreviousCursor = list.Paginate.NextCursor, list.Paginate.PreviousCursor
+		}
+		return page, nil
+	})
 }
 
-// ListRecurrences retrieves a list of all recurring payments with optional pagination using a cursor.
-func (c *Cryptomus) ListRecurrences(cursor string) (*RecurrenceListResponse, error) {
-	payload := make(map[string]interface{})
-	if cursor != "" {
-		payload["cursor"] = cursor
-	}
+// ListAllRecurrences returns the recurring payments selected by the list options, following the cursors
+// to completion. It fails with ErrListTruncated, along with the recurring payments collected so far,
+// if there are more than the caps of the options allow. Both options may be nil.
+func (c *Cryptomus) ListAllRecurrences(ctx context.Context, listOpts *RecurrenceListOptions, opts *ListAllOptions) ([]*Recurrence, error) {
+	return collect(c.Recurrences(ctx, listOpts), opts)
+}
 
-	// Send a POST request to list recurring payments
-	res, err := c.fetch("POST", recurrenceListEndpoint, payload)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+// listRecurrences retrieves the page of recurring payments selected by the options.
+func (c *Cryptomus) listRecurrences(ctx context.Context, opts *RecurrenceListOptions) (*RecurrenceListResponse, error) {
+	if opts == nil {
+		opts = &RecurrenceListOptions{}
 	}
-	defer res.Body.Close()
 
-	// Check for unexpected HTTP status codes
-	if res.StatusCode != 200 {
-		return nil, fmt.Errorf("unexpected HTTP status: %s", res.Status)
+	payload := make(map[string]interface{})
+	if opts.Cursor != "" {
+		payload["cursor"] = opts.Cursor
 	}
-
-	// Decode the JSON response
-	response := &recurrenceListRawResponse{}
-	if err = json.NewDecoder(res.Body).Decode(response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if opts.PerPage > 0 {
+		payload["per_page"] = opts.PerPage
 	}
 
-	// Check the state of the response
-	if response.State != 0 {
-		return nil, fmt.Errorf("API returned non-zero state: %d", response.State)
+	// Send a POST request to list recurring payments, failing with an *APIError on a non-200 status or a non-zero state
+	result, err := call[*RecurrenceListResponse](ctx, c, http.MethodPost, recurrenceListEndpoint, payload, AuthPayment)
+	if err != nil {
+		return nil, err
 	}
 
 	// Ensure the result is not nil
-	if response.Result == nil {
+	if result == nil {
 		return nil, errors.New("API response result is nil")
 	}
 
-	return response.Result, nil
+	return opts.apply(result), nil
 }
 
 // CancelRecurrence cancels a recurring payment using UUID or OrderID.
@@ -232,44 +261,109 @@ func (c *Cryptomus) CancelRecurrence(cancelReq *RecurrenceCancelRequest) (*Recur
 		return nil, errors.New("recurrence cancel request cannot be nil")
 	}
 
-	if cancelReq.UUID == "" && cancelReq.OrderID == "" {
-		return nil, errors.New("either uuid or order_id must be provided")
+	if err := checkIdentifiers(cancelReq.UUID, cancelReq.OrderID, "either uuid or order_id must be provided"); err != nil {
+		return nil, err
 	}
 
-	// Send a POST request to cancel the recurring payment
-	res, err := c.fetch("POST", recurrenceCancelEndpoint, cancelReq)
+	// Send a POST request to cancel the recurring payment, failing with an *APIError on a non-200 status or a non-zero state
+	result, err := post[*Recurrence](c, recurrenceCancelEndpoint, cancelReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, err
 	}
-	defer res.Body.Close()
 
-	// Handle non-200 HTTP status codes by attempting to decode validation errors
-	if res.StatusCode != 200 {
-		var errorResponse recurrenceCancelRawResponse
-		if decodeErr := json.NewDecoder(res.Body).Decode(&errorResponse); decodeErr == nil && errorResponse.Errors != nil {
-			return nil, fmt.Errorf("validation errors: %v", errorResponse.Errors)
-		}
-		return nil, fmt.Errorf("unexpected HTTP status: %s", res.Status)
+	// Ensure the result is not nil
+	if result == nil {
+		return nil, errors.New("API response result is nil")
 	}
 
-	// Decode the JSON response
-	response := &recurrenceCancelRawResponse{}
-	if err = json.NewDecoder(res.Body).Decode(response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	return result, nil
+}
+
+// RecurrencePlanChangeRequest represents the request structure for changing the plan of a recurring payment.
+type RecurrencePlanChangeRequest struct {
+	UUID    UUID    // Optional: UUID of the recurring payment to replace
+	OrderID string  // Optional: Order identifier in your system
+	Amount  *Amount // Optional: New amount of the payment, the current amount is kept if nil
+	Period  string  // Optional: New recurrence period, the current period is kept if empty
+}
+
+// RecurrencePlanChange represents the result of changing the plan of a recurring payment.
+type RecurrencePlanChange struct {
+	Old *Recurrence // Canceled recurring payment
+	New *Recurrence // Replacement recurring payment
+}
+
+// ChangeRecurrencePlan upgrades or downgrades a recurring payment.
+// Cryptomus has no native update call, so the existing recurring payment is canceled
+// and a replacement is created with the new amount and period, carrying over the
+// name, currencies, callback URL, discount, additional data and order_id linkage.
+// The replacement is validated before anything is canceled.
+// If the replacement cannot be created after the cancellation succeeded, the returned
+// change contains the canceled recurring payment alongside the error.
+func (c *Cryptomus) ChangeRecurrencePlan(changeReq *RecurrencePlanChangeRequest) (*RecurrencePlanChange, error) {
+	if changeReq == nil {
+		return nil, errors.New("recurrence plan change request cannot be nil")
 	}
 
-	// Check the state of the response and handle validation errors
-	if response.State != 0 {
-		if response.Errors != nil {
-			return nil, fmt.Errorf("validation errors: %v", response.Errors)
-		}
-		return nil, fmt.Errorf("API returned non-zero state: %d", response.State)
+	if err := checkIdentifiers(changeReq.UUID, changeReq.OrderID, "either uuid or order_id must be provided"); err != nil {
+		return nil, err
 	}
 
-	// Ensure the result is not nil
-	if response.Result == nil {
-		return nil, errors.New("API response result is nil")
+	if changeReq.Amount == nil && changeReq.Period == "" {
+		return nil, errors.New("either amount or period must be provided")
+	}
+
+	// Retrieve the current recurring payment to carry its settings over
+	current, err := c.GetRecurrenceInfo(&RecurrenceInfoRequest{UUID: changeReq.UUID, OrderID: changeReq.OrderID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current recurrence: %w", err)
+	}
+
+	recReq := &RecurrenceRequest{
+		Amount:         current.Amount,
+		Currency:       CurrencyCode(current.Currency),
+		Name:           current.Name,
+		Period:         current.Period,
+		ToCurrency:     current.ToCurrency,
+		OrderID:        current.OrderID,
+		UrlCallback:    current.UrlCallback,
+		AdditionalData: current.AdditionalData,
+	}
+	if changeReq.Amount != nil {
+		recReq.Amount = *changeReq.Amount
+	}
+	if changeReq.Period != "" {
+		recReq.Period = changeReq.Period
+	}
+	if current.DiscountDays > 0 {
+		recReq.DiscountDays = Ptr(current.DiscountDays)
+	}
+	if !current.DiscountAmount.IsZero() {
+		recReq.DiscountAmount = Ptr(current.DiscountAmount)
+	}
+
+	// Check the replacement before canceling, so an invalid one doesn't leave the customer without a plan
+	normalized, err := normalizeRecurrenceRequest(recReq)
+	if err != nil {
+		return nil, fmt.Errorf("invalid replacement recurrence: %w", err)
+	}
+	if err := validateRequest(normalized); err != nil {
+		return nil, fmt.Errorf("invalid replacement recurrence: %w", err)
+	}
+
+	// Cancel the current recurring payment before creating the replacement,
+	// so the order_id is free to be reused
+	old, err := c.CancelRecurrence(&RecurrenceCancelRequest{UUID: current.UUID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel current recurrence: %w", err)
+	}
+
+	change := &RecurrencePlanChange{Old: old}
+
+	change.New, err = c.CreateRecurrence(recReq)
+	if err != nil {
+		return change, fmt.Errorf("recurrence %s was canceled but the replacement could not be created: %w", old.UUID, err)
 	}
 
-	return response.Result, nil
+	return change, nil
 }
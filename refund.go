@@ -1,75 +1,41 @@
 package cryptomus
 
-import (
-	"encoding/json"
-	"errors"
-)
-
 const (
 	refundEndpoint               = "/payment/refund"
 	blockedAddressRefundEndpoint = "/wallet/blocked-address-refund"
 )
 
 type RefundRequest struct {
-	Address     string `json:"address"`
+	Address     string `json:"address" validate:"required"`
 	IsSubtract  bool   `json:"is_subtract"`
-	PaymentUUID string `json:"uuid,omitempty"`
+	PaymentUUID UUID   `json:"uuid,omitempty" validate:"uuid"`
 	OrderID     string `json:"order_id,omitempty"`
 }
 
-type refundRawResponse struct {
-	Result []string `json:"result,omitempty"`
-	State  int8     `json:"state"`
-}
-
 type BlockedAddressRefundRequest struct {
-	WalletUUID string `json:"uuid,omitempty"`
+	WalletUUID UUID   `json:"uuid,omitempty"`
 	OrderID    string `json:"order_id,omitempty"`
-	Address    string `json:"address"`
+	Address    string `json:"address" validate:"required"`
 }
 
 type BlockedAddressRefundResponse struct {
-	Commision string `json:"commision"`
-	Amount    string `json:"amount"`
-}
-
-type blockedAddressRefundRawResponse struct {
-	Result *BlockedAddressRefundResponse `json:"result"`
-	State  int8                          `json:"state"`
+	Commision Amount `json:"commision"`
+	Amount    Amount `json:"amount"`
 }
 
 func (c *Cryptomus) Refund(refundRequest *RefundRequest) (bool, error) {
-	res, err := c.fetch("POST", refundEndpoint, refundRequest)
+	result, err := post[[]string](c, refundEndpoint, refundRequest)
 	if err != nil {
 		return false, err
 	}
 
-	defer res.Body.Close()
-
-	response := &refundRawResponse{}
-	if err = json.NewDecoder(res.Body).Decode(response); err != nil {
-		return false, err
-	}
-
-	return len(response.Result) == 0, nil
+	return len(result) == 0, nil
 }
 
 func (c *Cryptomus) BlockedAddressRefund(refundRequest *BlockedAddressRefundRequest) (*BlockedAddressRefundResponse, error) {
-	if refundRequest.WalletUUID == "" && refundRequest.OrderID == "" {
-		return nil, errors.New("you should pass one of required values [WalletUUID, OrderID]")
-	}
-
-	res, err := c.fetch("POST", blockedAddressRefundEndpoint, refundRequest)
-	if err != nil {
-		return nil, err
-	}
-
-	defer res.Body.Close()
-
-	response := &blockedAddressRefundRawResponse{}
-	if err = json.NewDecoder(res.Body).Decode(response); err != nil {
+	if err := checkIdentifiers(refundRequest.WalletUUID, refundRequest.OrderID, "you should pass one of required values [WalletUUID, OrderID]"); err != nil {
 		return nil, err
 	}
 
-	return response.Result, nil
+	return post[*BlockedAddressRefundResponse](c, blockedAddressRefundEndpoint, refundRequest)
 }
@@ -0,0 +1,158 @@
+package cryptomus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrResumeTokenNotFound is returned by a ResumeStore when no token was saved under the key.
+var ErrResumeTokenNotFound = errors.New("resume token not found")
+
+// ResumeToken is the position of an iterator in a listing: the date window and the cursor of the next page to fetch,
+// and the direction of the iteration. It encodes to JSON, so it can be persisted anywhere.
+type ResumeToken struct {
+	Window  DateWindow `json:"window"`
+	Cursor  string     `json:"cursor,omitempty"`
+	Reverse bool       `json:"reverse,omitempty"`
+}
+
+// ResumeStore persists the resume tokens of long-running listings, e.g. exports, so that they can
+// resume where they stopped after a crash, rather than re-reading the listing from the beginning.
+type ResumeStore interface {
+	// SaveResumeToken stores the token under the key, replacing any previous one.
+	SaveResumeToken(ctx context.Context, key string, token *ResumeToken) error
+	// LoadResumeToken returns the token stored under the key, or ErrResumeTokenNotFound if there is none.
+	LoadResumeToken(ctx context.Context, key string) (*ResumeToken, error)
+	// DeleteResumeToken removes the token stored under the key, if any.
+	DeleteResumeToken(ctx context.Context, key string) error
+}
+
+// ResumeToken returns the position of the next page to fetch, or nil once the listing is exhausted.
+func (it *Iterator[T]) ResumeToken() *ResumeToken {
+	if it.done {
+		return nil
+	}
+
+	return &ResumeToken{Window: it.windows[it.window], Cursor: it.cursor, Reverse: it.reverse}
+}
+
+// Resume moves the iterator to the position of the token, so the next page fetched is the one it points at.
+// The token must come from an iterator over the same listing with the same options, iterating in the same
+// direction, except for tokens built by hand to start iterating backwards from a cursor. A nil token restarts
+// the listing from the beginning.
+func (it *Iterator[T]) Resume(token *ResumeToken) error {
+	window, cursor := 0, ""
+	if token != nil {
+		if token.Reverse && !it.reverse {
+			return errors.New("resume token was saved iterating backwards, call Reverse first")
+		}
+		// Windows are matched on their start only, as the end of the last one moves with the time
+		// when the range is open-ended
+		window = -1
+		for i, w := range it.windows {
+			if w.From.Equal(token.Window.From) {
+				window = i
+				break
+			}
+		}
+		if window < 0 {
+			return fmt.Errorf("resume token window starting at %s is not part of the listing", token.Window.From)
+		}
+		cursor = token.Cursor
+	}
+
+	it.closeStream()
+	it.window, it.cursor = window, cursor
+	it.page, it.index, it.done, it.err, it.pending = nil, 0, false, nil, nil
+
+	return nil
+}
+
+// ResumeFrom moves the iterator to the position saved in the store under the key,
+// leaving it at the beginning of the listing if no token was saved.
+func (it *Iterator[T]) ResumeFrom(ctx context.Context, store ResumeStore, key string) error {
+	token, err := store.LoadResumeToken(ctx, key)
+	if errors.Is(err, ErrResumeTokenNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load resume token: %w", err)
+	}
+
+	return it.Resume(token)
+}
+
+// Checkpoint saves the position of the iterator in the store under the key, or deletes the saved
+// position once the listing is exhausted. Call it after the current page was fully processed:
+//
+//	if err := it.ResumeFrom(ctx, store, "payments-export"); err != nil {
+//		// ...
+//	}
+//	for it.NextPage() {
+//		export(it.Page().Items)
+//		if err := it.Checkpoint(ctx, store, "payments-export"); err != nil {
+//			// ...
+//		}
+//	}
+func (it *Iterator[T]) Checkpoint(ctx context.Context, store ResumeStore, key string) error {
+	token := it.ResumeToken()
+	if token == nil {
+		if err := store.DeleteResumeToken(ctx, key); err != nil {
+			return fmt.Errorf("failed to delete resume token: %w", err)
+		}
+		return nil
+	}
+
+	if err := store.SaveResumeToken(ctx, key, token); err != nil {
+		return fmt.Errorf("failed to save resume token: %w", err)
+	}
+
+	return nil
+}
+
+// MemoryResumeStore is an in-memory ResumeStore, which only survives iterator restarts within the process.
+// It is safe for concurrent use.
+type MemoryResumeStore struct {
+	mu     sync.Mutex
+	tokens map[string]ResumeToken
+}
+
+// NewMemoryResumeStore creates a new in-memory store.
+func NewMemoryResumeStore() *MemoryResumeStore {
+	return &MemoryResumeStore{tokens: make(map[string]ResumeToken)}
+}
+
+// SaveResumeToken stores the token under the key.
+func (s *MemoryResumeStore) SaveResumeToken(ctx context.Context, key string, token *ResumeToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens[key] = *token
+
+	return nil
+}
+
+// LoadResumeToken returns the token stored under the key, or ErrResumeTokenNotFound.
+func (s *MemoryResumeStore) LoadResumeToken(ctx context.Context, key string) (*ResumeToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.tokens[key]
+	if !ok {
+		return nil, ErrResumeTokenNotFound
+	}
+
+	return &token, nil
+}
+
+// DeleteResumeToken removes the token stored under the key.
+func (s *MemoryResumeStore) DeleteResumeToken(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.tokens, key)
+
+	return nil
+}
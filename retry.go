@@ -0,0 +1,121 @@
+package cryptomus
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultRetryMaxWait is the longest wait for a retry honored unless RetryPolicy.MaxWait tells otherwise.
+const DefaultRetryMaxWait = time.Minute
+
+// RetryPolicy configures how requests rejected with 429 Too Many Requests are retried.
+// A rate-limited request has not been processed, so it is safe to retry even for endpoints
+// that are not idempotent, such as creating a payment.
+type RetryPolicy struct {
+	MaxRetries  int           // Retries after the first attempt; 0 disables retrying
+	DefaultWait time.Duration // Wait used when the response tells none, 1 second if zero
+	MaxWait     time.Duration // Longest wait honored; longer ones are returned as a *RateLimitError. DefaultRetryMaxWait if zero, unlimited if negative
+}
+
+// WithRetry makes the client retry rate-limited requests according to the policy,
+// waiting as long as the Retry-After header of the response asks.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Cryptomus) {
+		c.retry = policy
+	}
+}
+
+// RateLimitError is returned when the API responds with 429 Too Many Requests.
+// It wraps the *APIError of the response, so it also matches ErrRateLimited.
+type RateLimitError struct {
+	*APIError
+	RetryAfter time.Duration // Wait asked for by the response, 0 if it didn't tell
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter <= 0 {
+		return e.APIError.Error()
+	}
+
+	return e.APIError.Error() + ": retry after " + e.RetryAfter.String()
+}
+
+// Unwrap returns the *APIError of the response.
+func (e *RateLimitError) Unwrap() error {
+	return e.APIError
+}
+
+// retryWait returns how long to wait before retrying the rate-limited response,
+// or false if the request must not be retried.
+func (p RetryPolicy) retryWait(res *http.Response, attempt int, now time.Time) (time.Duration, bool) {
+	if res.StatusCode != http.StatusTooManyRequests || attempt >= p.MaxRetries {
+		return 0, false
+	}
+
+	wait := parseRetryAfter(res.Header, now)
+	if wait <= 0 {
+		wait = p.DefaultWait
+		if wait <= 0 {
+			wait = time.Second
+		}
+	}
+	maxWait := p.MaxWait
+	if maxWait == 0 {
+		maxWait = DefaultRetryMaxWait
+	}
+	if maxWait > 0 && wait > maxWait {
+		return 0, false
+	}
+
+	return wait, true
+}
+
+// parseRetryAfter returns the wait asked for by the Retry-After header, given either in seconds
+// or as an HTTP date, falling back to X-RateLimit-Reset, given either in seconds or as a Unix timestamp.
+// It returns 0 if there is none.
+func parseRetryAfter(h http.Header, now time.Time) time.Duration {
+	if v := strings.TrimSpace(h.Get("Retry-After")); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+		if t, err := http.ParseTime(v); err == nil && t.After(now) {
+			return t.Sub(now)
+		}
+	}
+
+	if v := strings.TrimSpace(h.Get("X-RateLimit-Reset")); v != "" {
+		if seconds, err := strconv.ParseInt(v, 10, 64); err == nil {
+			// Values past the current time are the time of the reset rather than the seconds until it
+			if seconds > now.Unix() {
+				return time.Unix(seconds, 0).Sub(now)
+			}
+			return time.Duration(seconds) * time.Second
+		}
+	}
+
+	return 0
+}
+
+// discardResponse drains and closes the body of a response that won't be decoded,
+// so the connection can be reused.
+func discardResponse(res *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, maxErrorBodySize))
+	res.Body.Close()
+}
+
+// sleepContext waits for the duration on the clock, returning early with the error of ctx if it is done.
+func sleepContext(ctx context.Context, clock Clock, d time.Duration) error {
+	timer := clock.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,109 @@
+package cryptomus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// DefaultSandboxBaseURL is the base URL of the sandbox unless configured otherwise: a mock of the API
+	// running locally.
+	DefaultSandboxBaseURL = "http://localhost:8080/v1"
+	// SandboxBaseURLEnv is the environment variable overriding DefaultSandboxBaseURL.
+	SandboxBaseURLEnv = "CRYPTOMUS_SANDBOX_BASE_URL"
+	// SandboxHeader tags the requests of a sandboxed client as test traffic.
+	SandboxHeader = "X-Cryptomus-Sandbox"
+)
+
+// DefaultSandboxMaxAmounts are the largest payouts a sandboxed client sends unless configured otherwise:
+// 10 of the US dollar and its stablecoins. Payouts in other currencies are refused.
+var DefaultSandboxMaxAmounts = map[CurrencyCode]Amount{
+	CurrencyUSD:  MustAmount("10"),
+	CurrencyUSDT: MustAmount("10"),
+	CurrencyUSDC: MustAmount("10"),
+}
+
+// ErrSandboxLimit is returned when a sandboxed client refuses a money-moving call.
+var ErrSandboxLimit = errors.New("refused by sandbox")
+
+// SandboxOptions configures the guardrails of WithSandbox.
+type SandboxOptions struct {
+	// BaseURL of the test environment, read from CRYPTOMUS_SANDBOX_BASE_URL, or DefaultSandboxBaseURL if empty.
+	BaseURL string
+	// MaxAmounts are the largest amounts of a payout by its currency, DefaultSandboxMaxAmounts if nil.
+	// Payouts in a currency without a limit are refused, so that a limit meant for USDT doesn't let BTC through.
+	MaxAmounts map[CurrencyCode]Amount
+	// AllowRefunds lets refunds through. They are refused by default, as their amount isn't known up front.
+	AllowRefunds bool
+}
+
+// sandbox holds the guardrails of a sandboxed client.
+type sandbox struct {
+	maxAmounts   map[CurrencyCode]Amount
+	allowRefunds bool
+}
+
+// WithSandbox switches the client to a test environment, tags its requests with the SandboxHeader,
+// and refuses the money-moving calls above the limits of the options with ErrSandboxLimit,
+// so that a staging deployment cannot pay out real funds by accident. Options may be nil.
+func WithSandbox(opts *SandboxOptions) Option {
+	if opts == nil {
+		opts = &SandboxOptions{}
+	}
+
+	return func(c *Cryptomus) {
+		c.baseURL = opts.BaseURL
+		if c.baseURL == "" {
+			c.baseURL = os.Getenv(SandboxBaseURLEnv)
+		}
+		if c.baseURL == "" {
+			c.baseURL = DefaultSandboxBaseURL
+		}
+
+		maxAmounts := opts.MaxAmounts
+		if maxAmounts == nil {
+			maxAmounts = DefaultSandboxMaxAmounts
+		}
+		c.sandbox = &sandbox{maxAmounts: make(map[CurrencyCode]Amount, len(maxAmounts)), allowRefunds: opts.AllowRefunds}
+		for currency, amount := range maxAmounts {
+			c.sandbox.maxAmounts[currency.Normalize()] = amount
+		}
+	}
+}
+
+// IsSandbox reports whether the client was configured with WithSandbox.
+func (c *Cryptomus) IsSandbox() bool {
+	return c.sandbox != nil
+}
+
+// check refuses the request to the endpoint if it moves money beyond the limits. The endpoint is checked
+// rather than the method, so requests sent with Call are guarded too.
+func (s *sandbox) check(endpoint string, body []byte) error {
+	endpoint, _, _ = strings.Cut(endpoint, "?")
+	switch "/" + strings.Trim(endpoint, "/") {
+	case createPayoutEndpoint:
+		var payload struct {
+			Amount   Amount `json:"amount"`
+			Currency string `json:"currency"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return fmt.Errorf("%w: failed to read payout amount: %s", ErrSandboxLimit, err)
+		}
+		maxAmount, ok := s.maxAmounts[CurrencyCode(payload.Currency).Normalize()]
+		if !ok {
+			return fmt.Errorf("%w: payouts in %q have no limit", ErrSandboxLimit, payload.Currency)
+		}
+		if payload.Amount.GreaterThan(maxAmount.Decimal) {
+			return fmt.Errorf("%w: payout of %s %s is above the limit of %s", ErrSandboxLimit, payload.Amount, payload.Currency, maxAmount)
+		}
+	case refundEndpoint, blockedAddressRefundEndpoint:
+		if !s.allowRefunds {
+			return fmt.Errorf("%w: refunds are not allowed", ErrSandboxLimit)
+		}
+	}
+
+	return nil
+}
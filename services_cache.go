@@ -0,0 +1,164 @@
+package cryptomus
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultServicesCacheTTL is the period during which cached services lists are served without refetching them.
+const DefaultServicesCacheTTL = 10 * time.Minute
+
+// ServicesProvider is a source of the payment and payout services lists.
+// *Cryptomus is the default implementation, while ServicesCache can be layered on top of it.
+type ServicesProvider interface {
+	GetPaymentServicesList() ([]*PaymentService, error)
+	GetPayoutServicesList() ([]*PayoutService, error)
+}
+
+// ServicesCacheOptions configures a ServicesCache.
+type ServicesCacheOptions struct {
+	TTL   time.Duration // Period during which a cached list is fresh, DefaultServicesCacheTTL if zero
+	Clock Clock         // Ages the cached lists, SystemClock if nil
+}
+
+// ServicesCache is a caching layer in front of GetPaymentServicesList and GetPayoutServicesList, whose results
+// change rarely but are needed on every checkout render and payout validation. A list is fetched again once
+// its TTL has passed or after Invalidate; concurrent fetches of the same list share a single request.
+// The lists returned are copies, which the caller may modify. It is safe for concurrent use.
+type ServicesCache struct {
+	provider ServicesProvider
+	opts     ServicesCacheOptions
+
+	mu         sync.Mutex
+	payment    servicesCacheEntry[*PaymentService]
+	payout     servicesCacheEntry[*PayoutService]
+	generation int // Incremented by Invalidate, so that fetches started before it aren't cached
+	flight     singleflight.Group
+}
+
+// servicesCacheEntry holds a cached services list.
+type servicesCacheEntry[T any] struct {
+	services  []T
+	fetchedAt time.Time
+	cached    bool
+}
+
+// NewServicesCache creates a new services cache backed by the provider, which is usually the *Cryptomus client.
+// Options may be nil, in which case the defaults are used.
+func NewServicesCache(provider ServicesProvider, opts *ServicesCacheOptions) *ServicesCache {
+	sc := &ServicesCache{provider: provider}
+	if opts != nil {
+		sc.opts = *opts
+	}
+	if sc.opts.TTL <= 0 {
+		sc.opts.TTL = DefaultServicesCacheTTL
+	}
+	sc.opts.Clock = clockOrSystem(sc.opts.Clock)
+
+	return sc
+}
+
+// GetPaymentServicesList returns the payment services from the cache, fetching them if they are missing or expired.
+func (sc *ServicesCache) GetPaymentServicesList() ([]*PaymentService, error) {
+	return cachedServices(sc, "payment", &sc.payment, sc.provider.GetPaymentServicesList, (*PaymentService).clone)
+}
+
+// GetPayoutServicesList returns the payout services from the cache, fetching them if they are missing or expired.
+func (sc *ServicesCache) GetPayoutServicesList() ([]*PayoutService, error) {
+	return cachedServices(sc, "payout", &sc.payout, sc.provider.GetPayoutServicesList, (*PayoutService).clone)
+}
+
+// Invalidate removes the cached services lists, so that they are fetched again on their next use.
+func (sc *ServicesCache) Invalidate() {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.payment = servicesCacheEntry[*PaymentService]{}
+	sc.payout = servicesCacheEntry[*PayoutService]{}
+	sc.generation++
+}
+
+// cachedServices returns a copy of the cached list of the entry, fetching it first if it is missing or expired.
+func cachedServices[T any](sc *ServicesCache, key string, entry *servicesCacheEntry[T], fetch func() ([]T, error), clone func(T) T) ([]T, error) {
+	sc.mu.Lock()
+	if entry.cached && sc.opts.Clock.Now().Sub(entry.fetchedAt) < sc.opts.TTL {
+		services := entry.services
+		sc.mu.Unlock()
+		return cloneServices(services, clone), nil
+	}
+	sc.mu.Unlock()
+
+	v, err, _ := sc.flight.Do(key, func() (interface{}, error) {
+		sc.mu.Lock()
+		generation := sc.generation
+		sc.mu.Unlock()
+
+		services, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+
+		sc.mu.Lock()
+		if generation == sc.generation {
+			*entry = servicesCacheEntry[T]{services: services, fetchedAt: sc.opts.Clock.Now(), cached: true}
+		}
+		sc.mu.Unlock()
+
+		return services, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cloneServices(v.([]T), clone), nil
+}
+
+// cloneServices copies the services list.
+func cloneServices[T any](services []T, clone func(T) T) []T {
+	copied := make([]T, len(services))
+	for i, service := range services {
+		copied[i] = clone(service)
+	}
+
+	return copied
+}
+
+// clone copies the service along with its limit and commission.
+func (s *PaymentService) clone() *PaymentService {
+	if s == nil {
+		return nil
+	}
+
+	copied := *s
+	if s.Limit != nil {
+		limit := *s.Limit
+		copied.Limit = &limit
+	}
+	if s.Commision != nil {
+		commision := *s.Commision
+		copied.Commision = &commision
+	}
+
+	return &copied
+}
+
+// clone copies the service along with its limit and commission.
+func (s *PayoutService) clone() *PayoutService {
+	if s == nil {
+		return nil
+	}
+
+	copied := *s
+	if s.Limit != nil {
+		limit := *s.Limit
+		copied.Limit = &limit
+	}
+	if s.Commision != nil {
+		commision := *s.Commision
+		copied.Commision = &commision
+	}
+
+	return &copied
+}
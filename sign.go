@@ -2,71 +2,316 @@
 package cryptomus
 
 import (
+	"bytes"
 	"crypto/md5"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"sync"
 )
 
-// signRequest generates a signature for the request using the provided API key and request body.
-// The signature is a hexadecimal MD5 hash of the base64-encoded request body concatenated with the API key.
-func (c *Cryptomus) signRequest(apiKey string, reqBody []byte) (string, error) {
+// Signer computes the signatures of request and webhook bodies.
+// It is used both to sign API requests and to verify webhooks, so a change of the signing scheme
+// or a custom signer for tests can be plugged in with WithSigner.
+type Signer interface {
+	Sign(apiKey string, body []byte) (string, error)
+}
+
+// SignerFunc adapts a function to the Signer interface.
+type SignerFunc func(apiKey string, body []byte) (string, error)
+
+// Sign calls f(apiKey, body).
+func (f SignerFunc) Sign(apiKey string, body []byte) (string, error) {
+	return f(apiKey, body)
+}
+
+// DefaultSigner is the MD5-of-base64 scheme Cryptomus uses, implemented by Sign.
+var DefaultSigner Signer = SignerFunc(Sign)
+
+// WithSigner sets the signer used to sign requests and verify webhooks. DefaultSigner is used if nil.
+func WithSigner(signer Signer) Option {
+	return func(c *Cryptomus) {
+		if signer != nil {
+			c.signer = signer
+		}
+	}
+}
+
+// WithPreviousPaymentKeys makes webhooks signed with the previous payment keys pass verification
+// alongside the current one, so no callbacks are rejected while the key is rotated.
+func WithPreviousPaymentKeys(keys ...string) Option {
+	return func(c *Cryptomus) {
+		c.previousPaymentKeys = append(c.previousPaymentKeys, keys...)
+	}
+}
+
+// WithPreviousPayoutKeys makes webhooks signed with the previous payout keys pass verification
+// alongside the current one, so no callbacks are rejected while the key is rotated.
+func WithPreviousPayoutKeys(keys ...string) Option {
+	return func(c *Cryptomus) {
+		c.previousPayoutKeys = append(c.previousPayoutKeys, keys...)
+	}
+}
+
+// Sign generates the signature of the request or webhook body with the API key.
+// The signature is a hexadecimal MD5 hash of the base64-encoded body concatenated with the API key.
+// It needs no client, so webhook-only services can use it without credentials of their own.
+func Sign(apiKey string, body []byte) (string, error) {
 	if apiKey == "" {
 		return "", errors.New("API key cannot be empty")
 	}
 
-	// Encode the request body using base64.
-	data := base64.StdEncoding.EncodeToString(reqBody)
+	s := signerStatePool.Get().(*signerState)
+	defer signerStatePool.Put(s)
+	s.hash.Reset()
+
+	// Hash the base64 encoding of the body as it is encoded, chunk by chunk, rather than encoding it whole.
+	// The chunks are multiples of 3 bytes, so that only the last one is padded.
+	for len(body) > 0 {
+		n := min(len(body), signChunkSize)
+		base64.StdEncoding.Encode(s.encoded[:], body[:n])
+		s.hash.Write(s.encoded[:base64.StdEncoding.EncodedLen(n)])
+		body = body[n:]
+	}
+	for len(apiKey) > 0 {
+		n := copy(s.encoded[:], apiKey)
+		s.hash.Write(s.encoded[:n])
+		apiKey = apiKey[n:]
+	}
+
+	s.sum = s.hash.Sum(s.sum[:0])
+	var sign [2 * md5.Size]byte
+	hex.Encode(sign[:], s.sum)
 
-	// Compute the MD5 hash of the concatenated data and API key.
-	hash := md5.Sum([]byte(data + apiKey))
+	return string(sign[:]), nil
+}
+
+// signChunkSize is the number of body bytes Sign encodes at a time.
+const signChunkSize = 3 * 1024
+
+// signerState is the scratch state of Sign, pooled so that signing allocates nothing but the signature.
+type signerState struct {
+	hash    hash.Hash
+	sum     []byte
+	encoded [4 * signChunkSize / 3]byte
+}
+
+var signerStatePool = sync.Pool{
+	New: func() interface{} {
+		return &signerState{hash: md5.New(), sum: make([]byte, 0, md5.Size)}
+	},
+}
+
+// SignWebhook signs the JSON webhook payload with the API key as Cryptomus does for callbacks: the payload is
+// re-encoded with sorted keys and without its 'sign' field, and the signature is appended as its last field.
+// The result passes Verify, so webhook handlers can be tested with payloads identical to production ones.
+func SignWebhook(apiKey string, body []byte) ([]byte, error) {
+	return signWebhookPayload(DefaultSigner, apiKey, body)
+}
+
+// Verify verifies the signature embedded in the raw webhook body with the API key.
+// The 'sign' field is cut out of the raw body, so the payload is verified exactly as it was signed.
+// It returns an error wrapping ErrInvalidSignature if the signature doesn't match.
+func Verify(apiKey string, body []byte) error {
+	return verifyPayload(DefaultSigner, apiKey, body, false)
+}
+
+// VerifyDetached verifies a signature that arrived separately from the body, e.g. in a header
+// or stored alongside the raw payload. The body is hashed exactly as given.
+// It returns an error wrapping ErrInvalidSignature if the signature doesn't match.
+func VerifyDetached(apiKey string, body []byte, sign string) error {
+	expectedSign, err := Sign(apiKey, body)
+	if err != nil {
+		return fmt.Errorf("failed to generate expected signature: %w", err)
+	}
+
+	if sign != expectedSign {
+		return signatureMismatch(apiKey, body, sign, false)
+	}
+
+	return nil
+}
+
+// VerifyAny verifies the signature embedded in the raw webhook body, succeeding if it matches any of
+// the API keys, so callbacks signed with the old key keep being accepted while the key is rotated.
+func VerifyAny(apiKeys []string, body []byte) error {
+	return verifyPayloadAny(DefaultSigner, apiKeys, body, false)
+}
 
-	// Return the hexadecimal representation of the hash.
-	return hex.EncodeToString(hash[:]), nil
+// SignWithPaymentKey signs the body with the payment API key, for requests to payment endpoints built manually.
+func (c *Cryptomus) SignWithPaymentKey(body []byte) (string, error) {
+	return c.signRequest(c.currentCredentials().paymentApiKey, body)
+}
+
+// SignWithPayoutKey signs the body with the payout API key, for requests to payout endpoints built manually.
+func (c *Cryptomus) SignWithPayoutKey(body []byte) (string, error) {
+	return c.signRequest(c.currentCredentials().payoutApiKey, body)
+}
+
+// signRequest generates a signature for the request using the provided API key and request body.
+func (c *Cryptomus) signRequest(apiKey string, reqBody []byte) (string, error) {
+	return c.signer.Sign(apiKey, reqBody)
 }
 
 // VerifySign verifies the signature of the incoming request.
 // It checks whether the 'sign' field in the JSON body matches the expected signature.
+// The field is cut out of the raw body and the remaining original bytes are verified,
+// so key order, number formatting and escaping stay exactly as they were signed.
 // Parameters:
 // - apiKey: The API key used for signing.
 // - reqBody: The raw request body bytes.
 // Returns:
 // - error: Returns an error if the signature is invalid or if required fields are missing.
 func (c *Cryptomus) VerifySign(apiKey string, reqBody []byte) error {
-	// Unmarshal the request body into a generic map.
-	var jsonBody map[string]interface{}
-	err := json.Unmarshal(reqBody, &jsonBody)
+	return verifyPayload(c.signer, apiKey, reqBody, c.debug)
+}
+
+// VerifyRaw verifies the signature of a webhook payload against its original bytes.
+// The API key is selected by the webhook type: payout callbacks are signed with the payout key,
+// every other callback with the payment key.
+func (c *Cryptomus) VerifyRaw(body []byte) error {
+	_, err := c.VerifyWebhook(body)
+	return err
+}
+
+// VerifyWebhook detects the type of the raw webhook and verifies its signature with the matching key:
+// the payout key for payout callbacks and the payment key for the others.
+// The detected type is returned, so the caller can decode the payload accordingly.
+func (c *Cryptomus) VerifyWebhook(body []byte) (WebhookType, error) {
+	typ, err := detectWebhookType(body)
 	if err != nil {
-		return fmt.Errorf("failed to unmarshal request body: %w", err)
+		return "", err
 	}
 
-	// Extract the 'sign' field from the JSON body.
-	reqSign, ok := jsonBody["sign"].(string)
-	if !ok {
-		return errors.New("missing signature field in request body")
+	return typ, verifyPayloadAny(c.signer, c.webhookKeys(typ), body, c.debug)
+}
+
+// VerifyWebhookRequest reads the body of the webhook request and verifies it with VerifyRaw.
+// The body is returned and restored on the request, so it can be read again by the caller.
+func (c *Cryptomus) VerifyWebhookRequest(r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
 	}
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
 
-	// Remove the 'sign' field from the JSON body before generating the expected signature.
-	delete(jsonBody, "sign")
+	if err := c.VerifyRaw(body); err != nil {
+		return nil, err
+	}
 
-	// Marshal the modified JSON body back to bytes.
-	modifiedBody, err := json.Marshal(jsonBody)
-	if err != nil {
-		return fmt.Errorf("failed to marshal modified request body: %w", err)
+	return body, nil
+}
+
+// verifyPayload verifies the signature embedded in the raw body with the signer and API key,
+// adding diagnostics to the error in debug mode.
+func verifyPayload(signer Signer, apiKey string, body []byte, debug bool) error {
+	return verifyPayloadAny(signer, []string{apiKey}, body, debug)
+}
+
+// verifyPayloadAny verifies the signature embedded in the raw body with the signer, succeeding if it
+// matches any of the API keys. The diagnostics added in debug mode refer to the first key.
+func verifyPayloadAny(signer Signer, apiKeys []string, body []byte, debug bool) error {
+	if len(apiKeys) == 0 {
+		return errors.New("API key cannot be empty")
 	}
 
-	// Generate the expected signature using the modified request body.
-	expectedSign, err := c.signRequest(apiKey, modifiedBody)
+	unsigned, reqSign, err := stripSignField(body)
 	if err != nil {
-		return fmt.Errorf("failed to generate expected signature: %w", err)
+		return err
 	}
 
-	// Compare the expected signature with the one provided in the request.
-	if reqSign != expectedSign {
-		return errors.New("invalid signature")
+	for _, apiKey := range apiKeys {
+		expectedSign, err := signer.Sign(apiKey, unsigned)
+		if err != nil {
+			return fmt.Errorf("failed to generate expected signature: %w", err)
+		}
+		if reqSign == expectedSign {
+			return nil
+		}
 	}
 
-	return nil
+	return signatureMismatch(apiKeys[0], unsigned, reqSign, debug)
+}
+
+// appendSignField adds the 'sign' field as the last field of the JSON object, leaving every other byte intact.
+func appendSignField(body []byte, sign string) []byte {
+	trimmed := bytes.TrimRight(body, " \t\r\n")
+	inner := bytes.TrimSpace(trimmed[1 : len(trimmed)-1])
+
+	signed := make([]byte, 0, len(trimmed)+len(sign)+10)
+	signed = append(signed, trimmed[:len(trimmed)-1]...)
+	if len(inner) > 0 {
+		signed = append(signed, ',')
+	}
+	signed = append(signed, `"sign":"`...)
+	signed = append(signed, sign...)
+	signed = append(signed, `"}`...)
+
+	return signed
+}
+
+// WithSignInBody makes the client add the signature as a 'sign' field to the JSON object bodies
+// of signed requests, in addition to the sign header, for flows expecting it inside the payload.
+// The signature is computed over the body without the field.
+func WithSignInBody() Option {
+	return func(c *Cryptomus) {
+		c.signInBody = true
+	}
+}
+
+// stripSignField cuts the top-level 'sign' field out of the raw JSON object, leaving every other byte intact.
+// It returns the remaining body and the value of the removed field.
+func stripSignField(body []byte) ([]byte, string, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, "", errors.New("request body is not a JSON object")
+	}
+
+	first := true
+	for dec.More() {
+		start := dec.InputOffset()
+
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to parse request body: %w", err)
+		}
+		key, _ := tok.(string)
+
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, "", fmt.Errorf("failed to parse request body: %w", err)
+		}
+		end := dec.InputOffset()
+
+		if key != "sign" {
+			first = false
+			continue
+		}
+
+		var sign string
+		if err := json.Unmarshal(value, &sign); err != nil {
+			return nil, "", errors.New("signature field is not a string")
+		}
+
+		if first {
+			// The field is preceded by the opening brace, so drop the comma following it instead
+			rest := bytes.TrimLeft(body[end:], " \t\r\n")
+			if len(rest) > 0 && rest[0] == ',' {
+				end = int64(len(body) - len(rest) + 1)
+			}
+		}
+
+		unsigned := make([]byte, 0, len(body)-int(end-start))
+		unsigned = append(unsigned, body[:start]...)
+		unsigned = append(unsigned, body[end:]...)
+		return unsigned, sign, nil
+	}
+
+	return nil, "", errors.New("missing signature field in request body")
 }
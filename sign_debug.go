@@ -0,0 +1,107 @@
+package cryptomus
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// WithDebug makes signature verification failures return a *SignatureError carrying the canonical
+// string that was hashed and a masked view of the key used. It must not be enabled in production,
+// as the diagnostics end up in logs.
+func WithDebug() Option {
+	return func(c *Cryptomus) {
+		c.debug = true
+	}
+}
+
+// SignatureError describes a signature mismatch in debug mode.
+// Comparing Canonical with the base64 of the body the sender signed tells whether the body or its
+// encoding differs, while KeyFingerprint and MaskedKey tell whether the expected key was used.
+// The signature the key would have produced is deliberately left out: handing it back to whoever
+// sent the payload would let them forge any body.
+type SignatureError struct {
+	Received       string // Signature carried by the payload
+	Canonical      string // Base64 of the payload without its sign field, as hashed together with the key
+	KeyFingerprint string // First 8 bytes of the SHA-256 of the key used, in hex
+	MaskedKey      string // Key used, masked but for its first and last 4 characters, or entirely if 12 or shorter, and its length
+}
+
+func (e *SignatureError) Error() string {
+	return fmt.Sprintf("invalid signature: received %q for base64 %q and key %s (fingerprint %s)",
+		e.Received, e.Canonical, e.MaskedKey, e.KeyFingerprint)
+}
+
+// Is makes errors.Is(err, ErrInvalidSignature) report true.
+func (e *SignatureError) Is(target error) bool {
+	return target == ErrInvalidSignature
+}
+
+// signatureMismatch returns the error reported when the received signature differs from the expected one.
+func signatureMismatch(apiKey string, unsigned []byte, received string, debug bool) error {
+	if !debug {
+		return ErrInvalidSignature
+	}
+
+	return &SignatureError{
+		Received:       received,
+		Canonical:      base64.StdEncoding.EncodeToString(unsigned),
+		KeyFingerprint: keyFingerprint(apiKey),
+		MaskedKey:      maskKey(apiKey),
+	}
+}
+
+// keyFingerprint identifies the key without revealing it, so the configured keys can be told apart.
+func keyFingerprint(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:8])
+}
+
+// maskKey masks the key with maskSecret and appends its length.
+func maskKey(key string) string {
+	return fmt.Sprintf("%s (%d chars)", maskSecret(key), len(key))
+}
+
+// maskSecret hides all but the first and last four characters of the key, and the whole key if it is short.
+func maskSecret(key string) string {
+	if len(key) <= 12 {
+		return strings.Repeat("*", len(key))
+	}
+
+	return key[:4] + strings.Repeat("*", len(key)-8) + key[len(key)-4:]
+}
+
+// SignaturePreview shows what is hashed to sign a body, without exposing the key.
+// It is safe to paste into support tickets and logs.
+type SignaturePreview struct {
+	Base64    string // Base64 of the body
+	MaskedKey string // Key, masked but for its first and last 4 characters, or entirely if 12 or shorter, and its length
+	Hashed    string // Exact concatenation that is hashed, with the key masked
+	Sign      string // Resulting signature, computed with the real key
+}
+
+// String formats the preview on multiple lines.
+func (p SignaturePreview) String() string {
+	return fmt.Sprintf("base64: %s\nkey: %s\nhashed: md5(%s)\nsign: %s", p.Base64, p.MaskedKey, p.Hashed, p.Sign)
+}
+
+// PreviewSignature returns the base64 + key concatenation hashed by Sign for the body, with the key masked,
+// so signature mismatches can be troubleshot by comparing it to the sender's without sharing the key.
+func PreviewSignature(apiKey string, body []byte) (SignaturePreview, error) {
+	sign, err := Sign(apiKey, body)
+	if err != nil {
+		return SignaturePreview{}, err
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(body)
+
+	return SignaturePreview{
+		Base64:    encoded,
+		MaskedKey: maskKey(apiKey),
+		Hashed:    encoded + maskSecret(apiKey),
+		Sign:      sign,
+	}, nil
+}
@@ -1,8 +1,9 @@
 package cryptomus
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
+	"net/http"
 )
 
 const (
@@ -12,104 +13,71 @@ const (
 )
 
 type StaticWalletRequest struct {
-	Currency string `json:"currency"`
-	Network  string `json:"network"`
-	OrderID  string `json:"order_id"`
+	Currency CurrencyCode `json:"currency" validate:"required,currency,crypto"`
+	Network  Network      `json:"network" validate:"required"`
+	OrderID  string       `json:"order_id" validate:"required,max=128"`
 	*StaticWalletRequestOptions
 }
 
 type StaticWalletRequestOptions struct {
-	UrlCallback      string `json:"url_callback,omitempty"`
+	UrlCallback      string `json:"url_callback,omitempty" validate:"url,max=255"`
 	FromReferralCode string `json:"from_referral_code,omitempty"`
 }
 
 type StaticWalletResponse struct {
 	OrderID    string `json:"order_id"`
-	WalletUUID string `json:"wallet_uuid"`
-	UUID       string `json:"uuid"`
+	WalletUUID UUID   `json:"wallet_uuid"`
+	UUID       UUID   `json:"uuid"`
 	Address    string `json:"address"`
 	Network    string `json:"network"`
 	Currency   string `json:"currency"`
 	Url        string `json:"url"`
 }
 
-type staticWalletRawResponse struct {
-	Result *StaticWalletResponse `json:"result"`
-	State  int8                  `json:"state"`
-}
-
-type staticWalletQRCodeRawResponse struct {
-	Result struct {
-		Image string `json:"image"`
-	} `json:"result"`
-	State int8 `json:"state"`
-}
-
 type BlockAddressRequest struct {
-	WalletUUID    string `json:"uuid,omitempty"`
+	WalletUUID    UUID   `json:"uuid,omitempty"`
 	OrderID       string `json:"order_id,omitempty"`
-	IsForceRefund bool   `json:"is_force_refund,omitempty"`
+	IsForceRefund *bool  `json:"is_force_refund,omitempty"`
 }
 
 type BlockAddressResponse struct {
-	WalletUUID string `json:"uuid"`
-	Status     string `json:"status"`
+	WalletUUID UUID         `json:"uuid"`
+	Status     WalletStatus `json:"status"`
 }
 
-type blockAddressRawResponse struct {
-	Result *BlockAddressResponse
-	State  int8
+func (c *Cryptomus) CreateStaticWallet(staticWalletReq *StaticWalletRequest) (*StaticWalletResponse, error) {
+	return c.createStaticWallet(context.Background(), staticWalletReq)
 }
 
-func (c *Cryptomus) CreateStaticWallet(staticWalletReq *StaticWalletRequest) (*StaticWalletResponse, error) {
-	res, err := c.fetch("POST", createStaticWalletEndpoint, staticWalletReq)
-	if err != nil {
-		return nil, err
+func (c *Cryptomus) createStaticWallet(ctx context.Context, staticWalletReq *StaticWalletRequest) (*StaticWalletResponse, error) {
+	if staticWalletReq == nil {
+		return nil, errors.New("static wallet request cannot be nil")
 	}
-
-	defer res.Body.Close()
-
-	response := &staticWalletRawResponse{}
-	if err = json.NewDecoder(res.Body).Decode(response); err != nil {
+	if err := validateNetwork(staticWalletReq.Currency, staticWalletReq.Network); err != nil {
 		return nil, err
 	}
 
-	return response.Result, nil
+	return call[*StaticWalletResponse](ctx, c, http.MethodPost, createStaticWalletEndpoint, staticWalletReq, AuthPayment)
 }
 
-func (c *Cryptomus) GenerateStaticWalletQRCode(walletUUID string) (string, error) {
-	payload := map[string]any{"wallet_address_uuid": walletUUID}
-	res, err := c.fetch("POST", generateStaticWalletQRCodeEndpoint, payload)
-	if err != nil {
+func (c *Cryptomus) GenerateStaticWalletQRCode(walletUUID UUID) (string, error) {
+	if err := validateUUID("wallet_address_uuid", walletUUID); err != nil {
 		return "", err
 	}
 
-	defer res.Body.Close()
-
-	response := &staticWalletQRCodeRawResponse{}
-	if err = json.NewDecoder(res.Body).Decode(response); err != nil {
+	payload := map[string]any{"wallet_address_uuid": walletUUID}
+	qrCode, err := post[qrCodeResult](c, generateStaticWalletQRCodeEndpoint, payload)
+	if err != nil {
 		return "", err
 	}
 
-	return response.Result.Image, nil
+	return qrCode.Image, nil
 }
 
 func (c *Cryptomus) BlockAddress(blockAddressReq *BlockAddressRequest) (*BlockAddressResponse, error) {
-	if blockAddressReq.WalletUUID == "" && blockAddressReq.OrderID == "" {
-		return nil, errors.New("you should pass one of required values [WalletUUID, OrderID]")
-	}
-
-	res, err := c.fetch("POST", blockWalletAddressEndpoint, blockAddressReq)
-	if err != nil {
-		return nil, err
-	}
-
-	defer res.Body.Close()
-
-	response := &blockAddressRawResponse{}
-	if err = json.NewDecoder(res.Body).Decode(response); err != nil {
+	if err := checkIdentifiers(blockAddressReq.WalletUUID, blockAddressReq.OrderID, "you should pass one of required values [WalletUUID, OrderID]"); err != nil {
 		return nil, err
 	}
 
-	return response.Result, nil
+	return post[*BlockAddressResponse](c, blockWalletAddressEndpoint, blockAddressReq)
 }
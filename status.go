@@ -0,0 +1,218 @@
+package cryptomus
+
+// PaymentStatus is the status of an invoice, also reported for static wallet deposits.
+//
+// The status types of this file keep the values unknown to the SDK as they are, so that statuses
+// introduced by Cryptomus are passed through rather than rejected; IsKnown tells them apart.
+// Unknown statuses are neither final nor successful.
+type PaymentStatus string
+
+// Payment statuses.
+const (
+	PaymentStatusPaid               PaymentStatus = "paid"
+	PaymentStatusPaidOver           PaymentStatus = "paid_over"
+	PaymentStatusWrongAmount        PaymentStatus = "wrong_amount"
+	PaymentStatusProcess            PaymentStatus = "process"
+	PaymentStatusConfirmCheck       PaymentStatus = "confirm_check"
+	PaymentStatusWrongAmountWaiting PaymentStatus = "wrong_amount_waiting"
+	PaymentStatusCheck              PaymentStatus = "check"
+	PaymentStatusFail               PaymentStatus = "fail"
+	PaymentStatusCancel             PaymentStatus = "cancel"
+	PaymentStatusSystemFail         PaymentStatus = "system_fail"
+	PaymentStatusRefundProcess      PaymentStatus = "refund_process"
+	PaymentStatusRefundFail         PaymentStatus = "refund_fail"
+	PaymentStatusRefundPaid         PaymentStatus = "refund_paid"
+	PaymentStatusLocked             PaymentStatus = "locked"
+)
+
+// IsKnown reports whether the status is one of the payment statuses known to the SDK.
+func (s PaymentStatus) IsKnown() bool {
+	switch s {
+	case PaymentStatusPaid, PaymentStatusPaidOver, PaymentStatusWrongAmount, PaymentStatusProcess,
+		PaymentStatusConfirmCheck, PaymentStatusWrongAmountWaiting, PaymentStatusCheck, PaymentStatusFail,
+		PaymentStatusCancel, PaymentStatusSystemFail, PaymentStatusRefundProcess, PaymentStatusRefundFail,
+		PaymentStatusRefundPaid, PaymentStatusLocked:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsFinal reports whether the status ends the lifecycle of the payment.
+func (s PaymentStatus) IsFinal() bool {
+	switch s {
+	case PaymentStatusPaid, PaymentStatusPaidOver, PaymentStatusWrongAmount, PaymentStatusFail,
+		PaymentStatusCancel, PaymentStatusSystemFail, PaymentStatusRefundFail, PaymentStatusRefundPaid:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsSuccessful reports whether the payment was paid in full, possibly over.
+func (s PaymentStatus) IsSuccessful() bool {
+	return s == PaymentStatusPaid || s == PaymentStatusPaidOver
+}
+
+// IsRefund reports whether the status describes the refund of the payment.
+func (s PaymentStatus) IsRefund() bool {
+	return s == PaymentStatusRefundProcess || s == PaymentStatusRefundFail || s == PaymentStatusRefundPaid
+}
+
+// RefundStatus returns the status of the refund of the payment, or "" if it is not being refunded.
+func (s PaymentStatus) RefundStatus() RefundStatus {
+	switch s {
+	case PaymentStatusRefundProcess:
+		return RefundStatusProcess
+	case PaymentStatusRefundFail:
+		return RefundStatusFail
+	case PaymentStatusRefundPaid:
+		return RefundStatusPaid
+	default:
+		return ""
+	}
+}
+
+// PayoutStatus is the status of a payout.
+type PayoutStatus string
+
+// Payout statuses.
+const (
+	PayoutStatusProcess    PayoutStatus = "process"
+	PayoutStatusCheck      PayoutStatus = "check"
+	PayoutStatusPaid       PayoutStatus = "paid"
+	PayoutStatusFail       PayoutStatus = "fail"
+	PayoutStatusCancel     PayoutStatus = "cancel"
+	PayoutStatusSystemFail PayoutStatus = "system_fail"
+)
+
+// IsKnown reports whether the status is one of the payout statuses known to the SDK.
+func (s PayoutStatus) IsKnown() bool {
+	switch s {
+	case PayoutStatusProcess, PayoutStatusCheck, PayoutStatusPaid, PayoutStatusFail, PayoutStatusCancel, PayoutStatusSystemFail:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsFinal reports whether the status ends the lifecycle of the payout.
+func (s PayoutStatus) IsFinal() bool {
+	return s.IsKnown() && s != PayoutStatusProcess && s != PayoutStatusCheck
+}
+
+// IsSuccessful reports whether the payout was sent.
+func (s PayoutStatus) IsSuccessful() bool {
+	return s == PayoutStatusPaid
+}
+
+// RefundStatus is the status of the refund of a payment, reported as the refund_* payment statuses.
+type RefundStatus string
+
+// Refund statuses.
+const (
+	RefundStatusProcess RefundStatus = "process"
+	RefundStatusPaid    RefundStatus = "paid"
+	RefundStatusFail    RefundStatus = "fail"
+)
+
+// IsKnown reports whether the status is one of the refund statuses known to the SDK.
+func (s RefundStatus) IsKnown() bool {
+	return s == RefundStatusProcess || s == RefundStatusPaid || s == RefundStatusFail
+}
+
+// IsFinal reports whether the status ends the lifecycle of the refund.
+func (s RefundStatus) IsFinal() bool {
+	return s == RefundStatusPaid || s == RefundStatusFail
+}
+
+// IsSuccessful reports whether the refund was sent.
+func (s RefundStatus) IsSuccessful() bool {
+	return s == RefundStatusPaid
+}
+
+// TransferStatus is the status of a transfer between the merchant balance and a personal or business wallet.
+type TransferStatus string
+
+// Transfer statuses.
+const (
+	TransferStatusProcess TransferStatus = "process"
+	TransferStatusPaid    TransferStatus = "paid"
+	TransferStatusFail    TransferStatus = "fail"
+	TransferStatusCancel  TransferStatus = "cancel"
+)
+
+// IsKnown reports whether the status is one of the transfer statuses known to the SDK.
+func (s TransferStatus) IsKnown() bool {
+	switch s {
+	case TransferStatusProcess, TransferStatusPaid, TransferStatusFail, TransferStatusCancel:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsFinal reports whether the status ends the lifecycle of the transfer.
+func (s TransferStatus) IsFinal() bool {
+	return s.IsKnown() && s != TransferStatusProcess
+}
+
+// IsSuccessful reports whether the transfer was credited to the destination wallet.
+func (s TransferStatus) IsSuccessful() bool {
+	return s == TransferStatusPaid
+}
+
+// WalletStatus is the status of the address of a static wallet.
+type WalletStatus string
+
+// Static wallet statuses.
+const (
+	WalletStatusActive  WalletStatus = "active"
+	WalletStatusBlocked WalletStatus = "blocked"
+)
+
+// IsKnown reports whether the status is one of the static wallet statuses known to the SDK.
+func (s WalletStatus) IsKnown() bool {
+	return s == WalletStatusActive || s == WalletStatusBlocked
+}
+
+// IsFinal reports whether the status ends the lifecycle of the address: a blocked address can't be reused.
+func (s WalletStatus) IsFinal() bool {
+	return s == WalletStatusBlocked
+}
+
+// IsSuccessful reports whether the address accepts deposits.
+func (s WalletStatus) IsSuccessful() bool {
+	return s == WalletStatusActive
+}
+
+// RecurrenceStatus is the status of a recurring payment.
+type RecurrenceStatus string
+
+// Recurring payment statuses.
+const (
+	RecurrenceStatusWaitAccept       RecurrenceStatus = "wait_accept"
+	RecurrenceStatusActive           RecurrenceStatus = "active"
+	RecurrenceStatusCancelByMerchant RecurrenceStatus = "cancel_by_merchant"
+	RecurrenceStatusCancelByUser     RecurrenceStatus = "cancel_by_user"
+)
+
+// IsKnown reports whether the status is one of the recurring payment statuses known to the SDK.
+func (s RecurrenceStatus) IsKnown() bool {
+	switch s {
+	case RecurrenceStatusWaitAccept, RecurrenceStatusActive, RecurrenceStatusCancelByMerchant, RecurrenceStatusCancelByUser:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsFinal reports whether the status ends the lifecycle of the recurring payment.
+func (s RecurrenceStatus) IsFinal() bool {
+	return s == RecurrenceStatusCancelByMerchant || s == RecurrenceStatusCancelByUser
+}
+
+// IsSuccessful reports whether the recurring payment was accepted by the payer and is being charged.
+func (s RecurrenceStatus) IsSuccessful() bool {
+	return s == RecurrenceStatusActive
+}
@@ -0,0 +1,320 @@
+package cryptomus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// pageStreamer opens the page of a listing in the date window at the cursor, the first page if it is empty,
+// to decode its items one by one.
+type pageStreamer[T any] func(ctx context.Context, window DateWindow, cursor string) (*pageStream[T], error)
+
+// pageCursors is the pagination of the history endpoints, the same for payments and payouts.
+type pageCursors struct {
+	Count          int    `json:"count"`
+	HasPages       bool   `json:"hasPages"`
+	NextCursor     string `json:"nextCursor,omitempty"`
+	PreviousCursor string `json:"previousCursor,omitempty"`
+	PerPage        int    `json:"perPage"`
+}
+
+// pageStream decodes the items of a history page from the response as it is read, so that only
+// the item being decoded is held in memory rather than the whole page. The cursors of the page are
+// known once its items are exhausted, as the API sends them after the items in result.paginate.
+type pageStream[T any] struct {
+	res     *http.Response
+	info    *requestInfo
+	body    *readRecorder
+	dec     *json.Decoder
+	state   int
+	cursors pageCursors
+	done    bool
+}
+
+// openPageStream performs the request of a history page and starts decoding its response, failing like
+// decodeInto if the API responded with an error. The stream must be closed.
+func openPageStream[T any](ctx context.Context, c *Cryptomus, endpoint string, payload interface{}, auth RequestAuth) (*pageStream[T], error) {
+	res, err := c.do(ctx, http.MethodPost, endpoint, payload, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	// Error responses are small, decode them as usual
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, decodeInto(res, &json.RawMessage{})
+	}
+
+	body := &readRecorder{r: res.Body}
+	s := &pageStream[T]{res: res, info: responseRequestInfo(res), body: body, dec: json.NewDecoder(body)}
+	if s.info.strict {
+		s.dec.DisallowUnknownFields()
+	}
+	if err := s.open(); err != nil {
+		s.close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// open reads the envelope up to the first item.
+func (s *pageStream[T]) open() error {
+	if err := s.expectDelim('{'); err != nil {
+		return err
+	}
+	for s.dec.More() {
+		key, err := s.key()
+		if err != nil {
+			return err
+		}
+		if key == "result" {
+			return s.openResult()
+		}
+		if err := s.envelopeField(key); err != nil {
+			return err
+		}
+	}
+
+	// No result: the envelope is over, check it
+	s.done = true
+	return s.finish()
+}
+
+// openResult reads the result object up to the start of its items, treating a null result
+// or one without items as no items.
+func (s *pageStream[T]) openResult() error {
+	tok, err := s.dec.Token()
+	if err != nil {
+		return s.fail(err)
+	}
+	switch tok {
+	case json.Delim('{'):
+	case nil:
+		s.done = true
+		return s.rest()
+	default:
+		return s.fail(fmt.Errorf("json: cannot unmarshal %v into the result of the page", tok))
+	}
+
+	for s.dec.More() {
+		key, err := s.key()
+		if err != nil {
+			return err
+		}
+		if key != "items" {
+			if err := s.resultField(key); err != nil {
+				return err
+			}
+			continue
+		}
+
+		tok, err := s.dec.Token()
+		if err != nil {
+			return s.fail(err)
+		}
+		switch tok {
+		case json.Delim('['):
+			return nil
+		case nil:
+			s.done = true
+			return s.restResult()
+		}
+		return s.fail(fmt.Errorf("json: cannot unmarshal %v into the items of the page", tok))
+	}
+
+	// No items: the result is over
+	s.done = true
+	if err := s.expectDelim('}'); err != nil {
+		return err
+	}
+	return s.rest()
+}
+
+// next decodes the next item. It returns false once the items are exhausted, after reading
+// the rest of the envelope, or on error.
+func (s *pageStream[T]) next() (T, bool, error) {
+	var item T
+	if s.done {
+		return item, false, nil
+	}
+	if !s.dec.More() {
+		s.done = true
+		if err := s.expectDelim(']'); err != nil {
+			return item, false, err
+		}
+		return item, false, s.restResult()
+	}
+	if err := s.dec.Decode(&item); err != nil {
+		return item, false, s.fail(err)
+	}
+
+	return item, true, nil
+}
+
+// restResult reads the fields of the result after the items, then the rest of the envelope.
+func (s *pageStream[T]) restResult() error {
+	for s.dec.More() {
+		key, err := s.key()
+		if err != nil {
+			return err
+		}
+		if err := s.resultField(key); err != nil {
+			return err
+		}
+	}
+	if err := s.expectDelim('}'); err != nil {
+		return err
+	}
+
+	return s.rest()
+}
+
+// rest reads the fields of the envelope after the result.
+func (s *pageStream[T]) rest() error {
+	for s.dec.More() {
+		key, err := s.key()
+		if err != nil {
+			return err
+		}
+		if err := s.envelopeField(key); err != nil {
+			return err
+		}
+	}
+	if err := s.expectDelim('}'); err != nil {
+		return err
+	}
+
+	return s.finish()
+}
+
+// envelopeField decodes a field of the envelope other than the result.
+func (s *pageStream[T]) envelopeField(key string) error {
+	var err error
+	if key == "state" {
+		err = s.dec.Decode(&s.state)
+	} else {
+		err = s.unknownField(key)
+	}
+	if err != nil {
+		return s.fail(err)
+	}
+	if s.state != 0 {
+		return s.finish()
+	}
+
+	return nil
+}
+
+// resultField decodes a field of the result other than the items.
+func (s *pageStream[T]) resultField(key string) error {
+	var err error
+	switch key {
+	case "paginate":
+		err = s.dec.Decode(&s.cursors)
+	case "merchant_uuid":
+		err = s.dec.Decode(&json.RawMessage{})
+	default:
+		err = s.unknownField(key)
+	}
+	if err != nil {
+		return s.fail(err)
+	}
+
+	return nil
+}
+
+// unknownField skips the value of a field the stream has no place for, failing in strict mode.
+func (s *pageStream[T]) unknownField(key string) error {
+	if s.info.strict {
+		return fmt.Errorf("json: unknown field %q", key)
+	}
+
+	return s.dec.Decode(&json.RawMessage{})
+}
+
+// finish fails with an *APIError if the envelope has a non-zero state.
+func (s *pageStream[T]) finish() error {
+	if s.state == 0 {
+		return nil
+	}
+
+	// Read the rest of the response for the error to describe it
+	_, _ = io.Copy(io.Discard, s.body)
+	body := s.body.prefix
+	envelope := apiEnvelope{}
+	if json.Unmarshal(body, &envelope) != nil {
+		envelope = apiEnvelope{State: s.state}
+	}
+
+	return s.info.report(newAPIError(s.res, body, envelope))
+}
+
+// key reads the key of the next field of the envelope.
+func (s *pageStream[T]) key() (string, error) {
+	tok, err := s.dec.Token()
+	if err != nil {
+		return "", s.fail(err)
+	}
+	key, ok := tok.(string)
+	if !ok {
+		return "", s.fail(fmt.Errorf("json: unexpected %v in the envelope", tok))
+	}
+
+	return key, nil
+}
+
+// expectDelim reads the delimiter.
+func (s *pageStream[T]) expectDelim(delim json.Delim) error {
+	tok, err := s.dec.Token()
+	if err != nil {
+		return s.fail(err)
+	}
+	if tok != delim {
+		return s.fail(fmt.Errorf("json: expected %v, got %v", delim, tok))
+	}
+
+	return nil
+}
+
+// fail describes an error decoding the response: a *TransportError if the response couldn't be read,
+// a *DecodeError with the beginning of the body otherwise.
+func (s *pageStream[T]) fail(err error) error {
+	if s.body.err != nil {
+		return s.info.fail(&TransportError{Err: fmt.Errorf("failed to read response: %w", s.body.err)})
+	}
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return s.info.fail(&DecodeError{Err: err, Body: append([]byte(nil), s.body.prefix...)})
+}
+
+// close releases the response.
+func (s *pageStream[T]) close() {
+	_ = s.res.Body.Close()
+}
+
+// readRecorder keeps the beginning of what is read, for the errors to show it,
+// and the error reading failed with, other than io.EOF.
+type readRecorder struct {
+	r      io.Reader
+	prefix []byte
+	err    error
+}
+
+func (r *readRecorder) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if room := maxErrorBodySize - len(r.prefix); room > 0 {
+		r.prefix = append(r.prefix, p[:min(n, room)]...)
+	}
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+
+	return n, err
+}
@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/backtrac3r/go-cryptomus"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdditionalData(t *testing.T) {
+	type metadata struct {
+		UserID int    `json:"user_id"`
+		Plan   string `json:"plan"`
+	}
+
+	data, err := cryptomus.NewAdditionalData(metadata{UserID: 42, Plan: "pro"})
+	require.NoError(t, err)
+	require.True(t, data.IsJSON())
+
+	body, err := json.Marshal(&cryptomus.RecurrenceRequest{Name: "Plan", AdditionalData: data})
+	require.NoError(t, err)
+	require.Contains(t, string(body), `"additional_data":"{\"user_id\":42,\"plan\":\"pro\"}"`)
+
+	payment := &cryptomus.Payment{}
+	require.NoError(t, json.Unmarshal([]byte(`{"additional_data":"{\"user_id\":42,\"plan\":\"pro\"}"}`), payment))
+	var decoded metadata
+	require.NoError(t, payment.AdditionalData.Decode(&decoded))
+	require.Equal(t, metadata{UserID: 42, Plan: "pro"}, decoded)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"additional_data":null}`), payment))
+	require.Nil(t, payment.AdditionalData)
+	require.NoError(t, json.Unmarshal([]byte(`{"additional_data":"order 5"}`), payment))
+	require.Equal(t, "order 5", payment.AdditionalData.String())
+	require.False(t, payment.AdditionalData.IsJSON())
+
+	body, err = json.Marshal(&cryptomus.InvoiceRequest{InvoiceRequestOptions: &cryptomus.InvoiceRequestOptions{}})
+	require.NoError(t, err)
+	require.NotContains(t, string(body), "additional_data")
+
+	_, err = cryptomus.NewAdditionalData(strings.Repeat("x", cryptomus.MaxAdditionalDataLength))
+	require.EqualError(t, err, "additional data is 257 characters long, at most 255 are allowed")
+
+	client := cryptomus.New(nil, "merchant", testPaymentKey, testPayoutKey)
+	invoice := validInvoice("o1")
+	invoice.InvoiceRequestOptions = &cryptomus.InvoiceRequestOptions{
+		AdditionalData: cryptomus.AdditionalData(strings.Repeat("é", 256)),
+	}
+	_, err = client.CreateInvoice(invoice)
+	var validation cryptomus.ValidationErrors
+	require.ErrorAs(t, err, &validation)
+	require.Equal(t, []string{"The additional data may not be greater than 255 characters."}, validation.Field("additional_data"))
+}
@@ -0,0 +1,134 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/backtrac3r/go-cryptomus"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAmountJSON(t *testing.T) {
+	payment := &cryptomus.Payment{}
+	err := json.Unmarshal([]byte(`{
+		"amount": "10.000000000000000001",
+		"payment_amount": 9.99,
+		"payer_amount": "",
+		"merchant_amount": null
+	}`), payment)
+	require.NoError(t, err)
+	require.Equal(t, "10.000000000000000001", payment.Amount.String())
+	require.Equal(t, "9.99", payment.PaymentAmount.String())
+	require.True(t, payment.PayerAmount.IsZero())
+	require.True(t, payment.MerchantAmount.IsZero())
+
+	data, err := json.Marshal(&cryptomus.PayoutRequest{Amount: cryptomus.MustAmount("0.10"), Currency: "USDT"})
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"amount":"0.1"`)
+
+	var amount cryptomus.Amount
+	require.Error(t, json.Unmarshal([]byte(`"ten"`), &amount))
+	_, err = cryptomus.NewAmount("1,5")
+	require.Error(t, err)
+	require.Panics(t, func() { cryptomus.MustAmount("") })
+}
+
+func TestAmountPrecision(t *testing.T) {
+	require.Equal(t, int32(6), cryptomus.CurrencyUSDT.Precision(cryptomus.NetworkTron))
+	require.Equal(t, int32(18), cryptomus.CurrencyUSDT.Precision("BSC"))
+	require.Equal(t, int32(8), cryptomus.CurrencyBTC.Precision(""))
+	require.Equal(t, int32(2), cryptomus.CurrencyUSD.Precision(""))
+	require.Equal(t, int32(0), cryptomus.CurrencyJPY.Precision(""))
+	require.Equal(t, int32(8), cryptomus.CurrencyCode("NEWCOIN").Precision(""))
+
+	require.Equal(t, "10.123457", cryptomus.FormatAmount(cryptomus.MustAmount("10.1234567"), "usdt", cryptomus.NetworkTron))
+	require.Equal(t, "10.5", cryptomus.FormatAmount(cryptomus.MustAmount("10.50"), cryptomus.CurrencyUSD, ""))
+
+	amount, err := cryptomus.ParseAmount("0.12345678", cryptomus.CurrencyBTC, cryptomus.NetworkBitcoin)
+	require.NoError(t, err)
+	require.Equal(t, "0.12345678", amount.String())
+	_, err = cryptomus.ParseAmount("1.500", cryptomus.CurrencyUSD, "")
+	require.NoError(t, err)
+	_, err = cryptomus.ParseAmount("0.123456789", cryptomus.CurrencyBTC, "")
+	require.EqualError(t, err, `invalid amount "0.123456789": BTC supports at most 8 decimal places`)
+
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Amount string `json:"amount"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		sent = append(sent, req.Amount)
+		_, _ = w.Write([]byte(`{"state":0,"result":{}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey)
+	client.SetBaseURL(server.URL + "/v1")
+
+	payoutReq := validPayout("o1")
+	payoutReq.Amount = cryptomus.MustAmount("12.345600000")
+	_, err = client.CreatePayout(payoutReq)
+	require.NoError(t, err)
+	require.Equal(t, []string{"12.3456"}, sent)
+	require.Equal(t, "12.3456", payoutReq.Amount.String())
+}
+
+func TestPayoutAmountNeverIncreased(t *testing.T) {
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.URL.Path)
+		_, _ = w.Write([]byte(`{"state":0,"result":{}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey)
+	client.SetBaseURL(server.URL + "/v1")
+
+	// Rounding 0.123456789 BTC to 8 places would pay out 0.12345679, more than asked for
+	payoutReq := &cryptomus.PayoutRequest{
+		Amount: cryptomus.MustAmount("0.123456789"), Currency: cryptomus.CurrencyBTC, Network: cryptomus.NetworkBitcoin,
+		OrderID: "o1", Address: "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", IsSubtract: true,
+	}
+	_, err := client.CreatePayout(payoutReq)
+	var validationErrs cryptomus.ValidationErrors
+	require.ErrorAs(t, err, &validationErrs)
+	require.Equal(t, []string{"The amount of BTC supports at most 8 decimal places."}, validationErrs.Field("amount"))
+
+	invoice := validInvoice("o2")
+	invoice.Amount = cryptomus.MustAmount("10.001")
+	invoice.Currency = cryptomus.CurrencyUSD
+	_, err = client.CreateInvoice(invoice)
+	require.ErrorAs(t, err, &validationErrs)
+	require.Empty(t, sent)
+
+	_, err = client.CreateInvoice(nil)
+	require.EqualError(t, err, "invoice request cannot be nil")
+	_, err = client.CreateRecurrence(nil)
+	require.EqualError(t, err, "recurrence request cannot be nil")
+}
+
+func TestNumericResponseFields(t *testing.T) {
+	var services []*cryptomus.PaymentService
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"currency":"USDT","commision":{"feeAmount":"0.3","percent":1.50}},
+		{"currency":"BTC","commision":{"feeAmount":"0","percent":"0.1"}}
+	]`), &services))
+	require.Equal(t, json.Number("1.50"), services[0].Commision.Percent)
+	require.Equal(t, json.Number("0.1"), services[1].Commision.Percent)
+
+	paginate := &cryptomus.PayoutHistoryPaginate{}
+	require.NoError(t, json.Unmarshal([]byte(`{"count":40000,"perPage":15}`), paginate))
+	require.Equal(t, 40000, paginate.Count)
+
+	// Numeric identifiers are reported as they were sent
+	client := cryptomus.New(nil, "merchant", testPaymentKey, testPayoutKey)
+	client.SetBaseURL("http://127.0.0.1:1/v1")
+	_, err := client.Call(context.Background(), http.MethodPost, "/payment/info",
+		map[string]any{"order_id": uint64(12345678901234567890)}, cryptomus.AuthPayment)
+	require.ErrorContains(t, err, "[order_id=12345678901234567890]")
+}
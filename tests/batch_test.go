@@ -0,0 +1,166 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/backtrac3r/go-cryptomus"
+	"github.com/backtrac3r/go-cryptomus/cryptomustest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunBatchParallelism(t *testing.T) {
+	var running, peak atomic.Int32
+	started := make(chan struct{}, 4)
+	release := make(chan struct{})
+	var releaseOnce sync.Once
+
+	results, err := cryptomus.RunBatch(context.Background(), 10, &cryptomus.BatchOptions{Parallelism: 4},
+		func(ctx context.Context, i int) (int, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+			}
+
+			// The first items wait until all the workers are busy
+			select {
+			case started <- struct{}{}:
+				if len(started) == cap(started) {
+					releaseOnce.Do(func() { close(release) })
+				}
+				<-release
+			default:
+			}
+			if i%3 == 0 {
+				return 0, errors.New("failed")
+			}
+			return i * i, nil
+		})
+
+	var batchErr *cryptomus.BatchError
+	require.ErrorAs(t, err, &batchErr)
+	require.Equal(t, []int{0, 3, 6, 9}, batchErr.Failed())
+	require.Equal(t, []int{1, 2, 4, 5, 7, 8}, batchErr.Succeeded)
+	require.Equal(t, []int{0, 1, 4, 0, 16, 25, 0, 49, 64, 0}, results)
+	require.Equal(t, int32(4), peak.Load())
+
+	// Sequential by default
+	var order []int
+	_, err = cryptomus.RunBatch(context.Background(), 3, nil, func(ctx context.Context, i int) (struct{}, error) {
+		order = append(order, i)
+		return struct{}{}, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 1, 2}, order)
+}
+
+func TestRunBatchItemTimeout(t *testing.T) {
+	_, err := cryptomus.RunBatch(context.Background(), 3, &cryptomus.BatchOptions{ItemTimeout: 10 * time.Millisecond},
+		func(ctx context.Context, i int) (int, error) {
+			if i == 1 {
+				<-ctx.Done()
+				return 0, ctx.Err()
+			}
+			return i, ctx.Err()
+		})
+
+	var batchErr *cryptomus.BatchError
+	require.ErrorAs(t, err, &batchErr)
+	require.Equal(t, []int{1}, batchErr.Failed())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	_, err = cryptomus.RunBatch(ctx, 3, nil, func(ctx context.Context, i int) (int, error) {
+		calls++
+		cancel()
+		return i, nil
+	})
+	require.ErrorAs(t, err, &batchErr)
+	require.Equal(t, []int{1, 2}, batchErr.Failed())
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 1, calls)
+}
+
+func TestRunBatchRateLimit(t *testing.T) {
+	clock := cryptomustest.NewClock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	var mu sync.Mutex
+	var calls []int
+	rateLimited := &cryptomus.RateLimitError{APIError: &cryptomus.APIError{StatusCode: http.StatusTooManyRequests}, RetryAfter: 2 * time.Second}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := cryptomus.RunBatch(context.Background(), 3, &cryptomus.BatchOptions{RateLimitRetries: 1, Clock: clock},
+			func(ctx context.Context, i int) (int, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				calls = append(calls, i)
+				if i == 1 {
+					return 0, rateLimited
+				}
+				return i, nil
+			})
+		done <- err
+	}()
+
+	// The rate-limited item pauses the batch before it is retried, and again before the next item
+	clock.BlockUntil(1)
+	mu.Lock()
+	require.Equal(t, []int{0, 1}, calls)
+	mu.Unlock()
+	clock.Advance(2 * time.Second)
+	clock.BlockUntil(1)
+	mu.Lock()
+	require.Equal(t, []int{0, 1, 1}, calls)
+	mu.Unlock()
+	clock.Advance(2 * time.Second)
+
+	err := <-done
+	var batchErr *cryptomus.BatchError
+	require.ErrorAs(t, err, &batchErr)
+	require.Equal(t, []int{1}, batchErr.Failed())
+	require.ErrorIs(t, err, cryptomus.ErrRateLimited)
+	require.Equal(t, []int{0, 1, 1, 2}, calls)
+}
+
+func TestBatchOptions(t *testing.T) {
+	var running, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		var req struct {
+			OrderID string `json:"order_id"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		_, _ = w.Write([]byte(`{"state":0,"result":{"order_id":"` + req.OrderID + `"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey,
+		cryptomus.WithBatchOptions(&cryptomus.BatchOptions{Parallelism: 3}))
+	client.SetBaseURL(server.URL + "/v1")
+
+	reqs := make([]*cryptomus.PayoutRequest, 9)
+	for i := range reqs {
+		reqs[i] = validPayout("order-" + string(rune('a'+i)))
+	}
+	payouts, err := client.CreatePayouts(reqs)
+	require.NoError(t, err)
+	for i, payout := range payouts {
+		require.Equal(t, reqs[i].OrderID, payout.OrderID)
+	}
+	require.LessOrEqual(t, peak.Load(), int32(3))
+	require.Greater(t, peak.Load(), int32(1))
+}
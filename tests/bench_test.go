@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/backtrac3r/go-cryptomus"
+)
+
+// roundTripFunc adapts a function to an http.RoundTripper, so benchmarks measure the client without the network.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// newBenchClient returns a client whose requests are drained and answered with the body, without the network.
+func newBenchClient(body string) *cryptomus.Cryptomus {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Body != nil {
+			_, _ = io.Copy(io.Discard, req.Body)
+			req.Body.Close()
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+			Request:    req,
+		}, nil
+	})
+
+	return cryptomus.New(&http.Client{Transport: transport}, "merchant", testPaymentKey, testPayoutKey)
+}
+
+func benchInvoice() *cryptomus.InvoiceRequest {
+	invoice := validInvoice("order-1714556400")
+	invoice.InvoiceRequestOptions = &cryptomus.InvoiceRequestOptions{
+		Network:     "tron",
+		UrlCallback: "https://example.com/cryptomus/callback",
+		UrlReturn:   "https://example.com/shop/cart",
+		UrlSuccess:  "https://example.com/shop/thanks",
+	}
+	invoice.AdditionalData = cryptomus.AdditionalData(`{"customer":"Jöhn Doe <john@example.com>","items":[1,2,3]}`)
+
+	return invoice
+}
+
+func BenchmarkMarshalCanonical(b *testing.B) {
+	invoice := benchInvoice()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := cryptomus.MarshalCanonical(invoice); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCreateInvoice(b *testing.B) {
+	client := newBenchClient(`{"state":0,"result":{"uuid":"8b03432e-385b-4670-8d06-064591096795","order_id":"order-1714556400","amount":"10","currency":"USDT","status":"check"}}`)
+	invoice := benchInvoice()
+	b.ReportAllocs()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := client.CreateInvoice(invoice); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkSign(b *testing.B) {
+	body, err := cryptomus.MarshalCanonical(benchInvoice())
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := cryptomus.Sign(testPaymentKey, body); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
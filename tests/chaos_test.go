@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/backtrac3r/go-cryptomus"
+	"github.com/backtrac3r/go-cryptomus/cryptomuschaos"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChaosTransport(t *testing.T) {
+	sent := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent++
+		_, _ = w.Write([]byte(`{"state":0,"result":{"uuid":"8b03432e-385b-4670-8d06-064591096795","order_id":"p1","status":"process"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	newClient := func(schedule cryptomuschaos.Schedule, opts ...cryptomus.Option) (*cryptomus.Cryptomus, *cryptomuschaos.Transport) {
+		sent = 0
+		chaos := cryptomuschaos.New(schedule)
+		chaos.Transport = server.Client().Transport
+		client := cryptomus.New(chaos.Client(), "merchant", testPaymentKey, testPayoutKey, opts...)
+		client.SetBaseURL(server.URL + "/v1")
+		return client, chaos
+	}
+
+	// Rate-limited requests are retried by the policy
+	client, chaos := newClient(cryptomuschaos.Sequence(cryptomuschaos.FaultRateLimit, cryptomuschaos.FaultRateLimit),
+		cryptomus.WithRetry(cryptomus.RetryPolicy{MaxRetries: 2, DefaultWait: time.Millisecond}))
+	_, err := client.CreatePayout(validPayout("p1"))
+	require.NoError(t, err)
+	require.Equal(t, 3, chaos.Requests())
+	require.Equal(t, 1, sent)
+	require.Len(t, chaos.Injections(), 2)
+	require.Equal(t, cryptomuschaos.FaultRateLimit, chaos.Injections()[0].Fault)
+
+	// and surface once the policy gives up
+	client, _ = newClient(cryptomuschaos.Sequence(cryptomuschaos.FaultRateLimit, cryptomuschaos.FaultRateLimit),
+		cryptomus.WithRetry(cryptomus.RetryPolicy{MaxRetries: 1, DefaultWait: time.Millisecond}))
+	_, err = client.CreatePayout(validPayout("p1"))
+	var rateErr *cryptomus.RateLimitError
+	require.ErrorAs(t, err, &rateErr)
+	require.Zero(t, sent)
+
+	// Timeouts and 5xx responses never reach the API
+	client, _ = newClient(cryptomuschaos.Sequence(cryptomuschaos.FaultTimeout))
+	_, err = client.CreatePayout(validPayout("p1"))
+	require.ErrorIs(t, err, cryptomuschaos.ErrTimeout)
+	require.True(t, cryptomus.IsRetryable(err))
+	var netErr interface{ Timeout() bool }
+	require.True(t, errors.As(err, &netErr) && netErr.Timeout())
+	require.Zero(t, sent)
+
+	client, chaos = newClient(cryptomuschaos.Sequence(cryptomuschaos.FaultServerError))
+	chaos.StatusCode = http.StatusBadGateway
+	_, err = client.CreatePayout(validPayout("p1"))
+	var apiErr *cryptomus.APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+	require.Zero(t, sent)
+
+	// Broken responses come back from requests the API processed
+	client, _ = newClient(cryptomuschaos.Sequence(cryptomuschaos.FaultTruncatedBody))
+	_, err = client.CreatePayout(validPayout("p1"))
+	var transportErr *cryptomus.TransportError
+	require.ErrorAs(t, err, &transportErr)
+	require.Equal(t, 1, sent)
+
+	client, _ = newClient(cryptomuschaos.Sequence(cryptomuschaos.FaultNone, cryptomuschaos.FaultMalformedJSON))
+	_, err = client.CreatePayout(validPayout("p1"))
+	require.NoError(t, err)
+	_, err = client.CreatePayout(validPayout("p1"))
+	var decodeErr *cryptomus.DecodeError
+	require.ErrorAs(t, err, &decodeErr)
+	require.Equal(t, cryptomuschaos.MalformedBody, string(decodeErr.Body))
+	require.False(t, cryptomus.IsRetryable(err))
+	require.Equal(t, 2, sent)
+}
+
+func TestChaosSchedules(t *testing.T) {
+	faults := func(schedule cryptomuschaos.Schedule) []cryptomuschaos.Fault {
+		var faults []cryptomuschaos.Fault
+		for i := 0; i < 6; i++ {
+			faults = append(faults, schedule.Fault(nil, i))
+		}
+		return faults
+	}
+
+	none, timeout := cryptomuschaos.FaultNone, cryptomuschaos.FaultTimeout
+	require.Equal(t, []cryptomuschaos.Fault{none, none, timeout, none, none, timeout}, faults(cryptomuschaos.Every(3, timeout)))
+	require.Equal(t, []cryptomuschaos.Fault{timeout, none, none, none, none, none}, faults(cryptomuschaos.Sequence(timeout)))
+
+	// Random schedules are reproducible from their seed
+	random := faults(cryptomuschaos.Random(0.5, timeout, 42))
+	require.Equal(t, random, faults(cryptomuschaos.Random(0.5, timeout, 42)))
+	require.Equal(t, []cryptomuschaos.Fault{none, none, none, none, none, none}, faults(cryptomuschaos.Random(0, timeout, 42)))
+	require.Equal(t, "malformed JSON", cryptomuschaos.FaultMalformedJSON.String())
+}
@@ -0,0 +1,245 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/backtrac3r/go-cryptomus"
+	"github.com/backtrac3r/go-cryptomus/cryptomustest"
+
+	"github.com/stretchr/testify/require"
+)
+
+var clockStart = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func TestClockRetry(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) == 1 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"state":0,"result":{"uuid":"8b03432e-385b-4670-8d06-064591096795","status":"process"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	clock := cryptomustest.NewClock(clockStart)
+	client := cryptomustest.NewClient(server.Client(), cryptomus.WithClock(clock),
+		cryptomus.WithRetry(cryptomus.RetryPolicy{MaxRetries: 1, MaxWait: 2 * time.Hour}))
+	client.SetBaseURL(server.URL + "/v1")
+
+	// The retry waits an hour on the clock, not in real time
+	done := make(chan error)
+	go func() {
+		_, err := client.CreatePayout(validPayout("p1"))
+		done <- err
+	}()
+	clock.BlockUntil(1)
+	require.EqualValues(t, 1, atomic.LoadInt32(&hits))
+	clock.Advance(time.Hour)
+	require.NoError(t, <-done)
+	require.EqualValues(t, 2, atomic.LoadInt32(&hits))
+	require.Zero(t, clock.Waiters())
+
+	// Failed calls are timed with the clock as well
+	atomic.StoreInt32(&hits, 0)
+	took := time.Minute
+	client = cryptomustest.NewClient(server.Client(), cryptomus.WithClock(clock),
+		cryptomus.WithOnError(func(_ context.Context, _ error, call cryptomus.CallInfo) { took = call.Duration }))
+	client.SetBaseURL(server.URL + "/v1")
+	_, err := client.CreatePayout(validPayout("p1"))
+	require.Error(t, err)
+	require.Zero(t, took)
+}
+
+func TestClockRateCache(t *testing.T) {
+	var hits int32
+	provider := cryptomus.RateProviderFunc(func(ctx context.Context, currency string, opts *cryptomus.ExchangeRateOptions) ([]cryptomus.ExchangeRate, error) {
+		atomic.AddInt32(&hits, 1)
+		return []cryptomus.ExchangeRate{{From: currency, To: "USD", Course: cryptomus.MustAmount("1")}}, nil
+	})
+
+	clock := cryptomustest.NewClock(clockStart)
+	cache := cryptomus.NewExchangeRateCache(provider, &cryptomus.ExchangeRateCacheOptions{TTL: time.Minute, Clock: clock})
+	defer cache.Close()
+
+	_, err := cache.ListExchangeRates(context.Background(), "USDT", nil)
+	require.NoError(t, err)
+	clock.Advance(59 * time.Second)
+	_, err = cache.ListExchangeRates(context.Background(), "USDT", nil)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, atomic.LoadInt32(&hits))
+
+	clock.Advance(time.Second)
+	_, err = cache.ListExchangeRates(context.Background(), "USDT", nil)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, atomic.LoadInt32(&hits))
+}
+
+func TestRateCacheClosed(t *testing.T) {
+	var hits int32
+	provider := cryptomus.RateProviderFunc(func(ctx context.Context, currency string, opts *cryptomus.ExchangeRateOptions) ([]cryptomus.ExchangeRate, error) {
+		atomic.AddInt32(&hits, 1)
+		return []cryptomus.ExchangeRate{{From: currency, To: "USD", Course: cryptomus.MustAmount("1")}}, nil
+	})
+
+	clock := cryptomustest.NewClock(clockStart)
+	cache := cryptomus.NewExchangeRateCache(provider, &cryptomus.ExchangeRateCacheOptions{TTL: time.Minute, StaleTTL: time.Hour, Clock: clock})
+	_, err := cache.ListExchangeRates(context.Background(), "USDT", nil)
+	require.NoError(t, err)
+
+	// Lookups racing with Close serve the stale rates, but refresh them only before it
+	clock.Advance(2 * time.Minute)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rates, err := cache.ListExchangeRates(context.Background(), "USDT", nil)
+			require.NoError(t, err)
+			require.Len(t, rates, 1)
+		}()
+	}
+	cache.Close()
+	wg.Wait()
+	hitsAfterClose := atomic.LoadInt32(&hits)
+	require.LessOrEqual(t, hitsAfterClose, int32(2))
+
+	rates, err := cache.ListExchangeRates(context.Background(), "USDT", nil)
+	require.NoError(t, err)
+	require.True(t, rates[0].CacheHit)
+	require.Equal(t, hitsAfterClose, atomic.LoadInt32(&hits))
+}
+
+func TestClockRateAge(t *testing.T) {
+	clock := cryptomustest.NewClock(clockStart)
+	fetchedAt := clock.Now()
+	primary := cryptomus.RateProviderFunc(func(ctx context.Context, currency string, opts *cryptomus.ExchangeRateOptions) ([]cryptomus.ExchangeRate, error) {
+		return []cryptomus.ExchangeRate{{From: currency, To: "USD", Course: cryptomus.MustAmount("1"), FetchedAt: fetchedAt}}, nil
+	})
+	fallback := cryptomus.RateProviderFunc(func(ctx context.Context, currency string, opts *cryptomus.ExchangeRateOptions) ([]cryptomus.ExchangeRate, error) {
+		return []cryptomus.ExchangeRate{{From: currency, To: "USD", Course: cryptomus.MustAmount("2"), FetchedAt: clock.Now()}}, nil
+	})
+
+	chained := cryptomus.NewChainedRateProvider(primary, fallback)
+	chained.MaxAge, chained.Clock = time.Minute, clock
+
+	// Rates are aged on the clock they were fetched with, whatever the wall time
+	rates, err := chained.ListExchangeRates(context.Background(), "USDT", nil)
+	require.NoError(t, err)
+	require.Equal(t, "1", rates[0].Course.String())
+	require.Zero(t, rates[0].AgeAt(clock.Now()))
+
+	clock.Advance(2 * time.Minute)
+	rates, err = chained.ListExchangeRates(context.Background(), "USDT", nil)
+	require.NoError(t, err)
+	require.Equal(t, "2", rates[0].Course.String())
+}
+
+func TestClockRateWatcher(t *testing.T) {
+	var polls int32
+	provider := cryptomus.RateProviderFunc(func(ctx context.Context, currency string, opts *cryptomus.ExchangeRateOptions) ([]cryptomus.ExchangeRate, error) {
+		course := "100"
+		if atomic.AddInt32(&polls, 1) > 1 {
+			course = "110"
+		}
+		return []cryptomus.ExchangeRate{{From: currency, To: "USD", Course: cryptomus.MustAmount(course)}}, nil
+	})
+
+	clock := cryptomustest.NewClock(clockStart)
+	changes := make(chan cryptomus.RateChange, 1)
+	watcher, err := cryptomus.NewRateWatcher(provider, cryptomus.RateWatcherOptions{
+		Pairs:     []cryptomus.RatePair{{From: "BTC", To: "USD"}},
+		Interval:  time.Hour,
+		Threshold: 0.05,
+		OnChange:  func(change cryptomus.RateChange) { changes <- change },
+		Clock:     clock,
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = watcher.Run(ctx) }()
+
+	// The next poll happens as soon as the clock is advanced by the interval
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+	change := <-changes
+	require.Equal(t, "0.1", change.Change.String())
+	require.Equal(t, clockStart.Add(time.Hour), change.At)
+}
+
+func TestRateWatcherConcurrentRuns(t *testing.T) {
+	var polls int32
+	var course atomic.Value
+	course.Store("100")
+	provider := cryptomus.RateProviderFunc(func(ctx context.Context, currency string, opts *cryptomus.ExchangeRateOptions) ([]cryptomus.ExchangeRate, error) {
+		atomic.AddInt32(&polls, 1)
+		return []cryptomus.ExchangeRate{{From: currency, To: "USD", Course: cryptomus.MustAmount(course.Load().(string))}}, nil
+	})
+
+	clock := cryptomustest.NewClock(clockStart)
+	changes := make(chan cryptomus.RateChange, 2)
+	watcher, err := cryptomus.NewRateWatcher(provider, cryptomus.RateWatcherOptions{
+		Pairs:     []cryptomus.RatePair{{From: "BTC", To: "USD"}},
+		Interval:  time.Hour,
+		Threshold: 0.05,
+		OnChange:  func(change cryptomus.RateChange) { changes <- change },
+		Clock:     clock,
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = watcher.Run(ctx)
+		}()
+	}
+
+	// Both runs share the baseline, so the movement is reported once
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&polls) >= 2 }, time.Second, time.Millisecond)
+	course.Store("110")
+	clock.BlockUntil(2)
+	clock.Advance(time.Hour)
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&polls) >= 4 }, time.Second, time.Millisecond)
+	cancel()
+	wg.Wait()
+
+	require.Len(t, changes, 1)
+	require.Equal(t, "0.1", (<-changes).Change.String())
+}
+
+func TestFakeClock(t *testing.T) {
+	clock := cryptomustest.NewClock(clockStart)
+
+	timer := clock.NewTimer(time.Minute)
+	ticker := clock.NewTicker(time.Minute)
+	require.Equal(t, 2, clock.Waiters())
+
+	clock.Advance(30 * time.Second)
+	require.Empty(t, timer.C())
+
+	// Due timers fire once, tickers drop the ticks that are missed
+	clock.Advance(3 * time.Minute)
+	require.Equal(t, clockStart.Add(210*time.Second), <-timer.C())
+	require.Equal(t, clockStart.Add(210*time.Second), <-ticker.C())
+	require.Empty(t, ticker.C())
+	require.False(t, timer.Stop())
+	require.Equal(t, 1, clock.Waiters())
+
+	clock.Advance(30 * time.Second)
+	require.Len(t, ticker.C(), 1)
+	ticker.Stop()
+	require.Zero(t, clock.Waiters())
+
+	require.Len(t, clock.NewTimer(0).C(), 1)
+}
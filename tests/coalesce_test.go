@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/backtrac3r/go-cryptomus"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newBlockingServer returns a client of a server holding every request until release is closed,
+// and a channel receiving the path of every request as it arrives.
+func newBlockingServer(t *testing.T, release chan struct{}, body string) (*cryptomus.Cryptomus, chan string) {
+	arrived := make(chan string, 100)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrived <- r.URL.Path
+		<-release
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	client := cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey)
+	client.SetBaseURL(server.URL + "/v1")
+
+	return client, arrived
+}
+
+func TestCoalesceExchangeRates(t *testing.T) {
+	release := make(chan struct{})
+	client, arrived := newBlockingServer(t, release, `{"state":0,"result":[{"from":"USDT","to":"USD","course":"1"},{"from":"USDT","to":"EUR","course":"0.9"}]}`)
+
+	const callers = 10
+	var wg sync.WaitGroup
+	results := make([][]cryptomus.ExchangeRate, callers)
+	errs := make([]error, callers)
+	call := func(i int, ctx context.Context) {
+		defer wg.Done()
+		var opts *cryptomus.ExchangeRateOptions
+		if i%2 == 1 {
+			opts = &cryptomus.ExchangeRateOptions{To: []string{"EUR"}}
+		}
+		results[i], errs[i] = client.ListExchangeRates(ctx, "USDT", opts)
+	}
+
+	// The first call is in flight when the others are made
+	wg.Add(1)
+	go call(0, context.Background())
+	<-arrived
+
+	// A caller giving up doesn't fail the shared request
+	canceled, cancel := context.WithCancel(context.Background())
+	wg.Add(callers - 1)
+	for i := 1; i < callers; i++ {
+		ctx := context.Background()
+		if i == callers-1 {
+			ctx = canceled
+		}
+		go call(i, ctx)
+	}
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	require.Empty(t, arrived, "identical concurrent calls must share one request")
+	require.ErrorIs(t, errs[callers-1], context.Canceled)
+	for i := 0; i < callers-1; i++ {
+		require.NoError(t, errs[i])
+		if i%2 == 1 {
+			require.Len(t, results[i], 1)
+		} else {
+			require.Len(t, results[i], 2)
+		}
+	}
+
+	// Every caller gets its own copy
+	results[0][0].To = "changed"
+	require.Equal(t, "USD", results[2][0].To)
+
+	// Calls made after the shared request completed send their own
+	_, err := client.ListExchangeRates(context.Background(), "USDT", nil)
+	require.NoError(t, err)
+	require.Len(t, arrived, 1)
+}
+
+func TestCoalesceInfo(t *testing.T) {
+	release := make(chan struct{})
+	client, arrived := newBlockingServer(t, release, `{"state":0,"result":{"uuid":"8b03432e-385b-4670-8d06-064591096795","order_id":"o1","status":"paid","additional_data":"{\"a\":1}"}}`)
+
+	var wg sync.WaitGroup
+	payments := make(chan *cryptomus.Payment, 4)
+	var failures int32
+	lookup := func(req *cryptomus.PaymentInfoRequest) {
+		defer wg.Done()
+		payment, err := client.GetPaymentInfo(req)
+		if err != nil {
+			atomic.AddInt32(&failures, 1)
+		}
+		payments <- payment
+	}
+
+	wg.Add(1)
+	go lookup(&cryptomus.PaymentInfoRequest{OrderID: "o1"})
+	<-arrived
+
+	// The same lookup joins the request in flight, another one doesn't
+	wg.Add(3)
+	go lookup(&cryptomus.PaymentInfoRequest{OrderID: "o1"})
+	go lookup(&cryptomus.PaymentInfoRequest{OrderID: "o1"})
+	go lookup(&cryptomus.PaymentInfoRequest{OrderID: "o2"})
+	<-arrived
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(payments)
+
+	require.Zero(t, atomic.LoadInt32(&failures))
+	require.Empty(t, arrived)
+	var first *cryptomus.Payment
+	for payment := range payments {
+		if first == nil {
+			first = payment
+			continue
+		}
+		require.NotSame(t, first, payment)
+		require.Equal(t, first.OrderID, payment.OrderID)
+	}
+
+	payout, err := cryptomus.New(nil, "", "", "").GetPayoutInfo(&cryptomus.PayoutInfoRequest{OrderID: "p1"})
+	require.ErrorIs(t, err, cryptomus.ErrMissingCredential)
+	require.Nil(t, payout)
+}
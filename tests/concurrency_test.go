@@ -0,0 +1,171 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/backtrac3r/go-cryptomus"
+	"github.com/backtrac3r/go-cryptomus/cryptomustest"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestConcurrentClient shares one client across goroutines while its base URL and credentials change,
+// and is meant to be run with the race detector. Every request must be signed consistently with
+// the merchant it is sent for, and reach one of the configured servers.
+func TestConcurrentClient(t *testing.T) {
+	keys := map[string]string{"merchant-a": "payment-key-a", "merchant-b": "payment-key-b"}
+	var requests, inconsistent int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Path != "/v1/exchange-rate/USDT/list" {
+			if _, err := verifyMerchantRequest(r, keys[r.Header.Get("merchant")]); err != nil {
+				atomic.AddInt32(&inconsistent, 1)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/exchange-rate/USDT/list":
+			_, _ = w.Write([]byte(`{"state":0,"result":[{"from":"USDT","to":"USD","course":"1"}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"state":0,"result":{"uuid":"8b03432e-385b-4670-8d06-064591096795","order_id":"o1","amount":"10","currency":"USDT","status":"check"}}`))
+		}
+	})
+	serverA := httptest.NewServer(handler)
+	t.Cleanup(serverA.Close)
+	serverB := httptest.NewServer(handler)
+	t.Cleanup(serverB.Close)
+
+	client := cryptomus.New(serverA.Client(), "merchant-a", keys["merchant-a"], "payout-key", cryptomus.WithRetry(cryptomus.RetryPolicy{MaxRetries: 1}))
+	client.SetBaseURL(serverA.URL + "/v1")
+
+	const workers, calls = 16, 20
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*calls)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < calls; i++ {
+				var err error
+				switch i % 4 {
+				case 0:
+					_, err = client.CreateInvoice(validInvoice(fmt.Sprintf("o-%d-%d", w, i)))
+				case 1:
+					_, err = client.GetPaymentInfo(&cryptomus.PaymentInfoRequest{OrderID: "o1"})
+				case 2:
+					_, err = client.ListExchangeRates(context.Background(), "USDT", nil)
+				case 3:
+					_, err = client.VerifyWebhook(cryptomustest.SignWebhook(t, keys["merchant-a"], `{"type":"payment","status":"paid"}`))
+					if err != nil {
+						// The payment key may have been replaced by the other goroutine meanwhile
+						_, err = client.VerifyWebhook(cryptomustest.SignWebhook(t, keys["merchant-b"], `{"type":"payment","status":"paid"}`))
+					}
+				}
+				if err != nil {
+					errs <- err
+				}
+			}
+		}(w)
+	}
+
+	// Rotate the base URL and the credentials while the calls are in flight
+	for i := 0; i < 50; i++ {
+		if i%2 == 0 {
+			client.SetBaseURL(serverB.URL + "/v1")
+			client.SetCredentials("merchant-b", keys["merchant-b"], "payout-key")
+		} else {
+			client.SetBaseURL(serverA.URL + "/v1")
+			client.SetCredentials("merchant-a", keys["merchant-a"], "payout-key")
+		}
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	// Identical concurrent reads may share a request
+	require.NotZero(t, atomic.LoadInt32(&requests))
+	require.LessOrEqual(t, atomic.LoadInt32(&requests), int32(workers*calls*3/4))
+	require.Zero(t, atomic.LoadInt32(&inconsistent))
+}
+
+func TestSetCredentials(t *testing.T) {
+	var merchant, verifyErr atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		merchant.Store(r.Header.Get("merchant"))
+		_, err := verifyMerchantRequest(r, "rotated-payout-key")
+		verifyErr.Store(fmt.Sprint(err))
+		_, _ = w.Write([]byte(`{"state":0,"result":{"uuid":"8b03432e-385b-4670-8d06-064591096795","status":"process"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey)
+	client.SetBaseURL(server.URL + "/v1")
+	client.SetCredentials("rotated", "", "rotated-payout-key")
+
+	_, err := client.CreatePayout(validPayout("p1"))
+	require.NoError(t, err)
+	require.Equal(t, "rotated", merchant.Load())
+	require.Equal(t, "<nil>", verifyErr.Load())
+
+	// The keys that are not given are cleared
+	_, err = client.CreateInvoice(validInvoice("o1"))
+	require.ErrorIs(t, err, cryptomus.ErrMissingCredential)
+
+	signature, err := client.SignWithPayoutKey([]byte("{}"))
+	require.NoError(t, err)
+	require.Equal(t, cryptomustest.Sign(t, "rotated-payout-key", []byte("{}")), signature)
+}
+
+func TestSetBaseURLAfterRequests(t *testing.T) {
+	// The URLs of the endpoints are memoized: changing the base URL must not reuse the previous ones
+	newServer := func(paths *[]string) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*paths = append(*paths, r.URL.RequestURI())
+			if strings.HasSuffix(r.URL.Path, "/list") {
+				_, _ = w.Write([]byte(`{"state":0,"result":{"items":[],"paginate":{}}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"state":0,"result":[]}`))
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+	var first, second []string
+	firstServer, secondServer := newServer(&first), newServer(&second)
+
+	client := cryptomus.New(firstServer.Client(), "merchant", testPaymentKey, testPayoutKey)
+	client.SetBaseURL(firstServer.URL + "/v1")
+	for i := 0; i < 2; i++ {
+		_, err := client.GetPaymentServicesList()
+		require.NoError(t, err)
+	}
+
+	client.SetBaseURL(secondServer.URL + "/api/v1/")
+	_, err := client.GetPaymentServicesList()
+	require.NoError(t, err)
+	_, err = client.ListAllPayments(context.Background(), nil, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"/v1/payment/services", "/v1/payment/services"}, first)
+	require.Equal(t, []string{"/api/v1/payment/services", "/api/v1/payment/list"}, second)
+}
+
+// verifyMerchantRequest checks that the body of the request is signed with the API key in its sign header.
+func verifyMerchantRequest(r *http.Request, apiKey string) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return body, cryptomus.VerifyDetached(apiKey, body, r.Header.Get("sign"))
+}
@@ -0,0 +1,181 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/backtrac3r/go-cryptomus"
+
+	"github.com/stretchr/testify/require"
+)
+
+// contractUUID is the uuid the contract requests refer to.
+const contractUUID = "8b03432e-385b-4670-8d06-064591096795"
+
+// TestContracts decodes the documented response of every wrapped endpoint, kept in testdata/contract,
+// with strict decoding, so a field of the API that the SDK drops fails the test. When the API docs change,
+// update the samples to match them and the structs until the test passes again.
+func TestContracts(t *testing.T) {
+	refundAddress := "TDD97yguPESTpcrJMqU6h2ozZbibv4Vaqm"
+	testWebhook := &cryptomus.TestWebhookRequest{
+		UrlCallback: "https://example.com/cryptomus/callback",
+		Currency:    "USDT",
+		Network:     "tron",
+		OrderID:     "order-1",
+		Status:      "paid",
+	}
+
+	contracts := []struct {
+		sample string
+		path   string
+		call   func(client *cryptomus.Cryptomus) (interface{}, error)
+		check  func(t *testing.T, result interface{})
+	}{
+		{"payment_create", "/v1/payment", func(c *cryptomus.Cryptomus) (interface{}, error) {
+			return c.CreateInvoice(validInvoice("order-1"))
+		}, func(t *testing.T, result interface{}) {
+			payment := result.(*cryptomus.Payment)
+			require.Equal(t, cryptomus.PaymentStatusCheck, payment.PaymentStatus)
+			require.Equal(t, int64(1714560000), payment.ExpiredAt.Unix())
+		}},
+		{"payment_qr", "/v1/payment/qr", func(c *cryptomus.Cryptomus) (interface{}, error) {
+			return c.GeneratePaymentQRCode(contractUUID)
+		}, nil},
+		{"payment_info", "/v1/payment/info", func(c *cryptomus.Cryptomus) (interface{}, error) {
+			return c.GetPaymentInfo(&cryptomus.PaymentInfoRequest{PaymentUUID: contractUUID})
+		}, func(t *testing.T, result interface{}) {
+			payment := result.(*cryptomus.Payment)
+			require.Equal(t, "14.7", payment.MerchantAmount.String())
+			require.EqualValues(t, 5, payment.DiscountPercent)
+			data := struct{ Customer int }{}
+			require.NoError(t, payment.AdditionalData.Decode(&data))
+			require.Equal(t, 42, data.Customer)
+		}},
+		{"payment_list", "/v1/payment/list", func(c *cryptomus.Cryptomus) (interface{}, error) {
+			return c.GetPaymentHistory(time.Time{}, time.Time{})
+		}, func(t *testing.T, result interface{}) {
+			history := result.(*cryptomus.PaymentHistoryResponse)
+			require.Len(t, history.Payments, 1)
+			require.Equal(t, 15, history.Paginate.PerPage)
+		}},
+		{"payment_services", "/v1/payment/services", func(c *cryptomus.Cryptomus) (interface{}, error) {
+			return c.GetPaymentServicesList()
+		}, func(t *testing.T, result interface{}) {
+			services := result.([]*cryptomus.PaymentService)
+			require.Len(t, services, 2)
+			require.True(t, services[0].IsAvailable)
+			require.Equal(t, "2.00", services[0].Commision.Percent.String())
+		}},
+		{"payment_refund", "/v1/payment/refund", func(c *cryptomus.Cryptomus) (interface{}, error) {
+			return c.Refund(&cryptomus.RefundRequest{Address: refundAddress, PaymentUUID: contractUUID})
+		}, nil},
+		{"payment_resend", "/v1/payment/resend", func(c *cryptomus.Cryptomus) (interface{}, error) {
+			return c.ResendWebhook(&cryptomus.ResendWebhookRequest{PaymentUUID: contractUUID})
+		}, nil},
+		{"wallet_create", "/v1/wallet", func(c *cryptomus.Cryptomus) (interface{}, error) {
+			return c.CreateStaticWallet(validStaticWallet("wallet-1"))
+		}, nil},
+		{"wallet_qr", "/v1/wallet/qr", func(c *cryptomus.Cryptomus) (interface{}, error) {
+			return c.GenerateStaticWalletQRCode(contractUUID)
+		}, nil},
+		{"wallet_block_address", "/v1/wallet/block-address", func(c *cryptomus.Cryptomus) (interface{}, error) {
+			return c.BlockAddress(&cryptomus.BlockAddressRequest{OrderID: "wallet-1"})
+		}, func(t *testing.T, result interface{}) {
+			require.Equal(t, cryptomus.WalletStatusBlocked, result.(*cryptomus.BlockAddressResponse).Status)
+		}},
+		{"wallet_blocked_address_refund", "/v1/wallet/blocked-address-refund", func(c *cryptomus.Cryptomus) (interface{}, error) {
+			return c.BlockedAddressRefund(&cryptomus.BlockedAddressRefundRequest{OrderID: "wallet-1", Address: refundAddress})
+		}, func(t *testing.T, result interface{}) {
+			refund := result.(*cryptomus.BlockedAddressRefundResponse)
+			require.Equal(t, "0.4", refund.Commision.String())
+			require.Equal(t, "20", refund.Amount.String())
+		}},
+		{"payout_create", "/v1/payout", func(c *cryptomus.Cryptomus) (interface{}, error) {
+			return c.CreatePayout(validPayout("payout-1"))
+		}, func(t *testing.T, result interface{}) {
+			require.Equal(t, "129", result.(*cryptomus.Payout).Balance.String())
+		}},
+		{"payout_info", "/v1/payout/info", func(c *cryptomus.Cryptomus) (interface{}, error) {
+			return c.GetPayoutInfo(&cryptomus.PayoutInfoRequest{OrderID: "payout-1"})
+		}, nil},
+		{"payout_list", "/v1/payout/list", func(c *cryptomus.Cryptomus) (interface{}, error) {
+			return c.GetPayoutHistory(time.Time{}, time.Time{})
+		}, func(t *testing.T, result interface{}) {
+			history := result.(*cryptomus.PayoutHistoryResponse)
+			require.Len(t, history.Payouts, 1)
+			require.Equal(t, cryptomus.UUID("c26b80a8-9549-4b70-9f2c-3b8a9d6e1b5f"), history.MerchantUUID)
+			require.Equal(t, 15, history.Paginate.PerPage)
+		}},
+		{"payout_services", "/v1/payout/services", func(c *cryptomus.Cryptomus) (interface{}, error) {
+			return c.GetPayoutServicesList()
+		}, nil},
+		{"recurrence_create", "/v1/recurrence/create", func(c *cryptomus.Cryptomus) (interface{}, error) {
+			return c.CreateRecurrence(&cryptomus.RecurrenceRequest{
+				Amount: cryptomus.MustAmount("5"), Currency: "USD", Name: "Recurring payment", Period: "monthly", OrderID: "sub-1",
+			})
+		}, func(t *testing.T, result interface{}) {
+			recurrence := result.(*cryptomus.Recurrence)
+			require.Equal(t, 7, recurrence.DiscountDays)
+			require.NotNil(t, recurrence.EndOfDiscount)
+		}},
+		{"recurrence_info", "/v1/recurrence/info", func(c *cryptomus.Cryptomus) (interface{}, error) {
+			return c.GetRecurrenceInfo(&cryptomus.RecurrenceInfoRequest{OrderID: "sub-1"})
+		}, nil},
+		{"recurrence_list", "/v1/recurrence/list", func(c *cryptomus.Cryptomus) (interface{}, error) {
+			return c.ListRecurrences(nil)
+		}, func(t *testing.T, result interface{}) {
+			require.Len(t, result.(*cryptomus.RecurrenceListResponse).Items, 1)
+		}},
+		{"recurrence_cancel", "/v1/recurrence/cancel", func(c *cryptomus.Cryptomus) (interface{}, error) {
+			return c.CancelRecurrence(&cryptomus.RecurrenceCancelRequest{OrderID: "sub-1"})
+		}, nil},
+		{"exchange_rate_list", "/v1/exchange-rate/BTC/list", func(c *cryptomus.Cryptomus) (interface{}, error) {
+			return c.ListExchangeRates(context.Background(), "BTC", nil)
+		}, func(t *testing.T, result interface{}) {
+			require.Equal(t, "62890.12", result.([]cryptomus.ExchangeRate)[0].CourseString())
+		}},
+		{"test_webhook_payment", "/v1/test-webhook/payment", func(c *cryptomus.Cryptomus) (interface{}, error) {
+			return c.TestPaymentWebhook(testWebhook)
+		}, nil},
+		{"test_webhook_payout", "/v1/test-webhook/payout", func(c *cryptomus.Cryptomus) (interface{}, error) {
+			return c.TestPayoutWebhook(testWebhook)
+		}, nil},
+		{"test_webhook_wallet", "/v1/test-webhook/wallet", func(c *cryptomus.Cryptomus) (interface{}, error) {
+			return c.TestWalletWebhook(testWebhook)
+		}, nil},
+	}
+
+	samples, err := filepath.Glob(filepath.Join("testdata", "contract", "*.json"))
+	require.NoError(t, err)
+	require.Len(t, samples, len(contracts), "every sample must have a contract")
+
+	for _, contract := range contracts {
+		contract := contract
+		t.Run(contract.sample, func(t *testing.T) {
+			sample, err := os.ReadFile(filepath.Join("testdata", "contract", contract.sample+".json"))
+			require.NoError(t, err)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, contract.path, r.URL.Path)
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write(sample)
+			}))
+			t.Cleanup(server.Close)
+
+			client := cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey, cryptomus.WithStrictDecoding())
+			client.SetBaseURL(server.URL + "/v1")
+
+			result, err := contract.call(client)
+			require.NoError(t, err)
+			require.NotEmpty(t, result)
+			if contract.check != nil {
+				contract.check(t, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/backtrac3r/go-cryptomus"
+	"github.com/backtrac3r/go-cryptomus/cryptomustest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestSigner(t *testing.T) {
+	client := cryptomustest.NewClient(nil)
+
+	// Webhooks signed by the helpers pass the production verification
+	payload := `{"type":"payment","uuid":"8b03432e-385b-4670-8d06-064591096795","order_id":"o1","amount":"10","status":"paid","url":"https://example.com/a/b"}`
+	signed := cryptomustest.SignWebhook(t, cryptomustest.PaymentKey, payload)
+	require.NoError(t, cryptomus.Verify(cryptomustest.PaymentKey, signed))
+	require.NoError(t, client.VerifyRaw(signed))
+	require.Error(t, cryptomus.Verify(cryptomustest.PayoutKey, signed))
+
+	// and are identical to the ones of the webhook simulator
+	webhook := cryptomus.NewWebhookSimulator(client).Payment("o1", cryptomus.MustAmount("10"), "USDT", cryptomus.PaymentStatusPaid)
+	simulated, err := cryptomus.NewWebhookSimulator(client).Sign(webhook)
+	require.NoError(t, err)
+	require.Equal(t, string(simulated), string(cryptomustest.SignWebhook(t, cryptomustest.PaymentKey, webhook)))
+
+	handler := cryptomus.NewDispatcher(client)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, cryptomustest.WebhookRequest(t, cryptomustest.PaymentKey, payload))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// A mock of the API validates the requests of the client
+	var verifyErr error
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, verifyErr = cryptomustest.VerifyRequest(r, cryptomustest.PayoutKey)
+		_, _ = w.Write([]byte(`{"state":0,"result":{"uuid":"8b03432e-385b-4670-8d06-064591096795","status":"process"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client = cryptomustest.NewClient(server.Client())
+	client.SetBaseURL(server.URL + "/v1")
+	_, err = client.CreatePayout(validPayout("p1"))
+	require.NoError(t, err)
+	require.NoError(t, verifyErr)
+
+	_, err = client.CreateInvoice(validInvoice("o1"))
+	require.NoError(t, err)
+	require.ErrorIs(t, verifyErr, cryptomus.ErrInvalidSignature)
+
+	signature, err := client.SignWithPaymentKey([]byte("{}"))
+	require.NoError(t, err)
+	require.Equal(t, signature, cryptomustest.Sign(t, cryptomustest.PaymentKey, []byte("{}")))
+}
+
+func TestWebhookFixtures(t *testing.T) {
+	client := cryptomustest.NewClient(nil)
+	fixtures := cryptomustest.Webhooks(t)
+	require.Len(t, fixtures, 14+14+6+4)
+
+	for _, fixture := range fixtures {
+		fixture := fixture
+		t.Run(fixture.Name, func(t *testing.T) {
+			typ, err := client.VerifyWebhook(fixture.Body)
+			require.NoError(t, err)
+			require.Equal(t, fixture.Type, typ)
+
+			event, err := cryptomus.ParseWebhook(fixture.Body, cryptomus.WithParseMode(cryptomus.ParseStrict))
+			require.NoError(t, err)
+			require.Equal(t, fixture.Status, event.Status())
+			require.NotEmpty(t, event.UUID())
+
+			var handled string
+			dispatcher := cryptomus.NewDispatcher(client)
+			dispatcher.On(fixture.Type, fixture.Status, func(_ context.Context, event *cryptomus.WebhookEvent) error {
+				handled = event.OrderID()
+				return nil
+			})
+			rec := httptest.NewRecorder()
+			dispatcher.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(fixture.Body)))
+			require.Equal(t, http.StatusOK, rec.Code)
+			require.NotEmpty(t, handled)
+		})
+	}
+
+	payout := cryptomustest.Webhook(t, "payout/fail")
+	require.Equal(t, cryptomustest.PayoutKey, payout.Key())
+	require.True(t, payout.Payload.(*cryptomus.PayoutWebhook).Status.IsFinal())
+	require.NotEmpty(t, payout.Payload.(*cryptomus.PayoutWebhook).Sign)
+}
@@ -0,0 +1,444 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/backtrac3r/go-cryptomus"
+	"github.com/backtrac3r/go-cryptomus/cryptomustest"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testPaymentKey = "test-payment-key"
+	testPayoutKey  = "test-payout-key"
+	testUUID       = "8b03432e-385b-4670-8d06-064591096795"
+)
+
+// signPayload signs the JSON payload with the key the same way Cryptomus does, appending the sign field.
+func signPayload(t *testing.T, key, payload string) []byte {
+	return cryptomustest.SignWebhook(t, key, payload)
+}
+
+func newTestClient() *cryptomus.Cryptomus {
+	return cryptomus.New(nil, "merchant", testPaymentKey, testPayoutKey)
+}
+
+func TestDispatcherRoutes(t *testing.T) {
+	dispatcher := cryptomus.NewDispatcher(newTestClient())
+
+	var paid, payoutAny, unknown int
+	dispatcher.OnPaymentPaid(func(ctx context.Context, webhook *cryptomus.PaymentWebhook) error {
+		paid++
+		return nil
+	})
+	dispatcher.OnPayout("", func(ctx context.Context, webhook *cryptomus.PayoutWebhook) error {
+		payoutAny++
+		return nil
+	})
+	dispatcher.OnUnknown(func(ctx context.Context, event *cryptomus.WebhookEvent) error {
+		unknown++
+		return nil
+	})
+
+	ctx := context.Background()
+	require.NoError(t, dispatcher.Dispatch(ctx, signPayload(t, testPaymentKey, paymentWebhookPayload)))
+	require.NoError(t, dispatcher.Dispatch(ctx, signPayload(t, testPayoutKey, payoutWebhookPayload)))
+	require.NoError(t, dispatcher.Dispatch(ctx, signPayload(t, testPaymentKey, walletWebhookPayload)))
+	require.Equal(t, 1, paid)
+	require.Equal(t, 1, payoutAny)
+	require.Equal(t, 1, unknown)
+
+	// A payout signed with the payment key must be rejected.
+	require.Error(t, dispatcher.Dispatch(ctx, signPayload(t, testPaymentKey, payoutWebhookPayload)))
+	require.Equal(t, 1, payoutAny)
+}
+
+func TestDispatcherServeHTTP(t *testing.T) {
+	dispatcher := cryptomus.NewDispatcher(newTestClient())
+	dispatcher.OnPayment("", func(ctx context.Context, webhook *cryptomus.PaymentWebhook) error {
+		return errors.New("database is down")
+	})
+
+	post := func(body []byte) int {
+		rec := httptest.NewRecorder()
+		dispatcher.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/callback", bytes.NewReader(body)))
+		return rec.Code
+	}
+
+	require.Equal(t, http.StatusInternalServerError, post(signPayload(t, testPaymentKey, paymentWebhookPayload)))
+	require.Equal(t, http.StatusBadRequest, post([]byte(paymentWebhookPayload)))
+	require.Equal(t, http.StatusBadRequest, post([]byte(`not json`)))
+}
+
+func TestWebhookStream(t *testing.T) {
+	stream := cryptomus.NewWebhookStream(newTestClient(), cryptomus.WithStreamBuffer(1), cryptomus.WithStreamRejectWhenFull())
+
+	ctx := context.Background()
+	require.NoError(t, stream.Dispatch(ctx, signPayload(t, testPaymentKey, paymentWebhookPayload)))
+	require.ErrorIs(t, stream.Dispatch(ctx, signPayload(t, testPayoutKey, payoutWebhookPayload)), cryptomus.ErrWebhookStreamFull)
+
+	event := <-stream.Events()
+	require.Equal(t, cryptomus.WebhookTypePayment, event.Type)
+
+	stream.Close()
+	_, ok := <-stream.Events()
+	require.False(t, ok)
+	require.ErrorIs(t, stream.Dispatch(ctx, signPayload(t, testPaymentKey, paymentWebhookPayload)), cryptomus.ErrWebhookStreamClosed)
+}
+
+func TestDispatcherAsync(t *testing.T) {
+	errs := make(chan error, 1)
+	dispatcher := cryptomus.NewDispatcher(newTestClient(),
+		cryptomus.WithAsync(2, 8),
+		cryptomus.WithAsyncErrorHandler(func(event *cryptomus.WebhookEvent, err error) {
+			errs <- err
+		}),
+	)
+
+	var handled int32
+	dispatcher.OnPayout("", func(ctx context.Context, webhook *cryptomus.PayoutWebhook) error {
+		atomic.AddInt32(&handled, 1)
+		return nil
+	})
+	dispatcher.OnPayment("", func(ctx context.Context, webhook *cryptomus.PaymentWebhook) error {
+		panic("boom")
+	})
+
+	ctx := context.Background()
+	require.NoError(t, dispatcher.Dispatch(ctx, signPayload(t, testPaymentKey, paymentWebhookPayload)))
+	require.NoError(t, dispatcher.Dispatch(ctx, signPayload(t, testPayoutKey, payoutWebhookPayload)))
+	require.NoError(t, dispatcher.Shutdown(ctx))
+
+	require.EqualValues(t, 1, atomic.LoadInt32(&handled))
+	require.ErrorContains(t, <-errs, "panicked: boom")
+	require.ErrorIs(t, dispatcher.Dispatch(ctx, signPayload(t, testPayoutKey, payoutWebhookPayload)), cryptomus.ErrDispatcherClosed)
+}
+
+func TestIPAllowlist(t *testing.T) {
+	allowlist, err := cryptomus.NewIPAllowlist(&cryptomus.IPAllowlistOptions{TrustedProxies: []string{"10.0.0.0/8"}})
+	require.NoError(t, err)
+
+	handler := allowlist.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	request := func(remoteAddr, forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodPost, "/callback", nil)
+		req.RemoteAddr = remoteAddr
+		if forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	require.Equal(t, http.StatusOK, request("91.227.144.54:40000", ""))
+	require.Equal(t, http.StatusForbidden, request("203.0.113.7:40000", ""))
+	require.Equal(t, http.StatusOK, request("10.1.2.3:40000", "91.227.144.54, 10.0.0.5"))
+	require.Equal(t, http.StatusForbidden, request("10.1.2.3:40000", "91.227.144.54, 203.0.113.7"))
+	require.Equal(t, http.StatusForbidden, request("203.0.113.7:40000", "91.227.144.54"))
+
+	// A trusted proxy sending only X-Real-IP
+	realIP := func(remoteAddr, ip string) int {
+		req := httptest.NewRequest(http.MethodPost, "/callback", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Real-IP", ip)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	require.Equal(t, http.StatusOK, realIP("10.1.2.3:40000", "91.227.144.54"))
+	require.Equal(t, http.StatusForbidden, realIP("10.1.2.3:40000", "203.0.113.7"))
+
+	_, err = cryptomus.NewIPAllowlist(&cryptomus.IPAllowlistOptions{Allowed: []string{"not-an-ip"}})
+	require.Error(t, err)
+}
+
+func TestDispatcherDedup(t *testing.T) {
+	dispatcher := cryptomus.NewDispatcher(newTestClient(), cryptomus.WithDedup(cryptomus.NewMemoryDedupStore(0)))
+
+	var calls int
+	fail := true
+	dispatcher.OnPayment("", func(ctx context.Context, webhook *cryptomus.PaymentWebhook) error {
+		calls++
+		if fail {
+			return errors.New("temporary failure")
+		}
+		return nil
+	})
+
+	ctx := context.Background()
+	payload := signPayload(t, testPaymentKey, paymentWebhookPayload)
+
+	require.Error(t, dispatcher.Dispatch(ctx, payload))
+	fail = false
+	require.NoError(t, dispatcher.Dispatch(ctx, payload))
+	require.NoError(t, dispatcher.Dispatch(ctx, payload))
+	require.Equal(t, 2, calls)
+}
+
+func TestMemoryDedupStoreEviction(t *testing.T) {
+	store := cryptomus.NewMemoryDedupStore(2)
+	ctx := context.Background()
+
+	for _, key := range []string{"a", "b", "c"} {
+		added, err := store.Add(ctx, key)
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+
+	added, err := store.Add(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, added, "oldest key should have been evicted")
+
+	added, err = store.Add(ctx, "c")
+	require.NoError(t, err)
+	require.False(t, added)
+}
+
+func TestWebhookSimulator(t *testing.T) {
+	client := newTestClient()
+	dispatcher := cryptomus.NewDispatcher(client)
+
+	received := make(chan string, 3)
+	dispatcher.OnUnknown(func(ctx context.Context, event *cryptomus.WebhookEvent) error {
+		received <- string(event.Type) + ":" + event.OrderID()
+		return nil
+	})
+
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	simulator := cryptomus.NewWebhookSimulator(client)
+	ctx := context.Background()
+	require.NoError(t, simulator.Send(ctx, server.URL, simulator.Payment("order-1", cryptomus.MustAmount("10.00"), "USDT", "paid")))
+	require.NoError(t, simulator.Send(ctx, server.URL, simulator.Payout("payout-1", cryptomus.MustAmount("5.00"), "USDT", "paid")))
+	require.NoError(t, simulator.Send(ctx, server.URL, simulator.Wallet("user-1", cryptomus.MustAmount("7.00"), "USDT", "paid")))
+
+	require.Equal(t, "payment:order-1", <-received)
+	require.Equal(t, "payout:payout-1", <-received)
+	require.Equal(t, "wallet:user-1", <-received)
+
+	signed, err := simulator.Sign(simulator.Payment("order-2", cryptomus.MustAmount("1"), "TRX", "paid"))
+	require.NoError(t, err)
+	require.NoError(t, client.VerifyRaw(signed))
+}
+
+func TestWrapIdempotent(t *testing.T) {
+	store := cryptomus.NewMemoryDedupStore(0)
+
+	var calls int32
+	fail := true
+	handler := cryptomus.WrapIdempotent(func(ctx context.Context, event *cryptomus.WebhookEvent) error {
+		atomic.AddInt32(&calls, 1)
+		if fail {
+			return errors.New("temporary failure")
+		}
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	}, store)
+
+	event, err := cryptomus.ParseWebhook([]byte(paymentWebhookPayload))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.Error(t, handler(ctx, &event))
+	seen, err := store.Contains(ctx, cryptomus.DedupKey(&event))
+	require.NoError(t, err)
+	require.False(t, seen, "failed webhook must not be committed")
+
+	fail = false
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, handler(ctx, &event))
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestVerifyWebhookSelectsKey(t *testing.T) {
+	client := newTestClient()
+
+	typ, err := client.VerifyWebhook(signPayload(t, testPayoutKey, payoutWebhookPayload))
+	require.NoError(t, err)
+	require.Equal(t, cryptomus.WebhookTypePayout, typ)
+
+	_, err = client.VerifyWebhook(signPayload(t, testPaymentKey, payoutWebhookPayload))
+	require.Error(t, err)
+
+	typ, err = client.VerifyWebhook(signPayload(t, testPaymentKey, walletWebhookPayload))
+	require.NoError(t, err)
+	require.Equal(t, cryptomus.WebhookTypeWallet, typ)
+
+	webhook, err := client.ParseWebhook(signPayload(t, testPaymentKey, walletWebhookPayload), true)
+	require.NoError(t, err)
+	require.Equal(t, "wallet", webhook.Type)
+}
+
+// recordingMetrics counts the emitted metrics by name.
+type recordingMetrics struct {
+	mu        sync.Mutex
+	counters  map[string]int
+	durations map[string]int
+}
+
+func (m *recordingMetrics) IncCounter(name string, tags map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters[name]++
+}
+
+func (m *recordingMetrics) ObserveDuration(name string, d time.Duration, tags map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.durations[name]++
+}
+
+func TestDispatcherMetrics(t *testing.T) {
+	metrics := &recordingMetrics{counters: map[string]int{}, durations: map[string]int{}}
+	client := cryptomus.New(nil, "merchant", testPaymentKey, testPayoutKey, cryptomus.WithMetrics(metrics))
+	dispatcher := cryptomus.NewDispatcher(client)
+	dispatcher.OnPaymentFail(func(ctx context.Context, webhook *cryptomus.PaymentWebhook) error {
+		return errors.New("boom")
+	})
+
+	ctx := context.Background()
+	require.NoError(t, dispatcher.Dispatch(ctx, signPayload(t, testPaymentKey, paymentWebhookPayload)))
+	require.Error(t, dispatcher.Dispatch(ctx, signPayload(t, testPayoutKey, paymentWebhookPayload)))
+	require.Error(t, dispatcher.Dispatch(ctx, []byte(`not json`)))
+
+	failed := bytes.Replace([]byte(paymentWebhookPayload), []byte(`"status":"paid"`), []byte(`"status":"fail"`), 1)
+	require.Error(t, dispatcher.Dispatch(ctx, signPayload(t, testPaymentKey, string(failed))))
+
+	require.Equal(t, 3, metrics.counters[cryptomus.MetricWebhooksReceived])
+	require.Equal(t, 1, metrics.counters[cryptomus.MetricWebhooksInvalid])
+	require.Equal(t, 1, metrics.counters[cryptomus.MetricWebhookVerifyFailures])
+	require.Equal(t, 1, metrics.counters[cryptomus.MetricWebhookHandlerErrors])
+	require.Equal(t, 2, metrics.durations[cryptomus.MetricWebhookProcessDuration])
+}
+
+func TestDispatcherOrderedProcessing(t *testing.T) {
+	dispatcher := cryptomus.NewDispatcher(newTestClient(), cryptomus.WithOrderedProcessing())
+
+	var running, maxRunning int32
+	dispatcher.OnPayment("", func(ctx context.Context, webhook *cryptomus.PaymentWebhook) error {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			current := atomic.LoadInt32(&maxRunning)
+			if n <= current || atomic.CompareAndSwapInt32(&maxRunning, current, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	})
+
+	payload := func(orderID, status string) []byte {
+		p := bytes.Replace([]byte(paymentWebhookPayload), []byte(`"status":"paid"`), []byte(`"status":"`+status+`"`), 1)
+		p = bytes.Replace(p, []byte(`"order_id":"97a75bf8eda5cca41ba9d2e104840fcd"`), []byte(`"order_id":"`+orderID+`"`), 1)
+		return signPayload(t, testPaymentKey, string(p))
+	}
+
+	dispatch := func(payloads ...[]byte) {
+		var wg sync.WaitGroup
+		for _, p := range payloads {
+			wg.Add(1)
+			go func(p []byte) {
+				defer wg.Done()
+				require.NoError(t, dispatcher.Dispatch(context.Background(), p))
+			}(p)
+		}
+		wg.Wait()
+	}
+
+	dispatch(payload("order-1", "process"), payload("order-1", "paid"), payload("order-1", "paid_over"))
+	require.Equal(t, int32(1), atomic.LoadInt32(&maxRunning))
+
+	dispatch(payload("order-1", "paid"), payload("order-2", "paid"))
+	require.Equal(t, int32(2), atomic.LoadInt32(&maxRunning))
+}
+
+func TestDispatcherOrderedAsync(t *testing.T) {
+	const orders, events = 5, 20
+	dispatcher := cryptomus.NewDispatcher(newTestClient(), cryptomus.WithAsync(8, orders*events*8), cryptomus.WithOrderedProcessing())
+
+	var mu sync.Mutex
+	sequences := map[string][]string{}
+	dispatcher.OnPayment("", func(ctx context.Context, webhook *cryptomus.PaymentWebhook) error {
+		// Uneven processing times, to shuffle the events if they weren't ordered
+		time.Sleep(time.Duration(len(webhook.TxId)%3) * time.Millisecond)
+		mu.Lock()
+		sequences[webhook.OrderID] = append(sequences[webhook.OrderID], webhook.TxId)
+		mu.Unlock()
+		return nil
+	})
+
+	want := map[string][]string{}
+	for i := 0; i < events; i++ {
+		for o := 0; o < orders; o++ {
+			orderID, txID := fmt.Sprintf("order-%d", o), strings.Repeat("x", i)+fmt.Sprint(i)
+			p := bytes.Replace([]byte(paymentWebhookPayload), []byte(`"order_id":"97a75bf8eda5cca41ba9d2e104840fcd"`), []byte(`"order_id":"`+orderID+`"`), 1)
+			p = bytes.Replace(p, []byte(`"txid":"6f0d9c8374db57cac0d806251473de754f361c83a03cd805f74aa9da3193486b"`), []byte(`"txid":"`+txID+`"`), 1)
+			require.NoError(t, dispatcher.Dispatch(context.Background(), signPayload(t, testPaymentKey, string(p))))
+			want[orderID] = append(want[orderID], txID)
+		}
+	}
+	require.NoError(t, dispatcher.Shutdown(context.Background()))
+
+	// The events of each order are processed in the order they were received
+	require.Equal(t, want, sequences)
+}
+
+func TestListenAndServeWebhooks(t *testing.T) {
+	client := newTestClient()
+	dispatcher := cryptomus.NewDispatcher(client, cryptomus.WithAsync(1, 4))
+
+	var handled int32
+	dispatcher.OnPaymentPaid(func(ctx context.Context, webhook *cryptomus.PaymentWebhook) error {
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&handled, 1)
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	listening := make(chan net.Addr, 1)
+	served := make(chan error, 1)
+	go func() {
+		served <- cryptomus.ListenAndServeWebhooks(ctx, "127.0.0.1:0", dispatcher, &cryptomus.WebhookServerOptions{
+			Path:     "/cryptomus",
+			OnListen: func(addr net.Addr) { listening <- addr },
+		})
+	}()
+	base := "http://" + (<-listening).String()
+
+	res, err := http.Get(base + "/healthz")
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	simulator := cryptomus.NewWebhookSimulator(client)
+	require.NoError(t, simulator.Send(ctx, base+"/cryptomus", simulator.Payment("order-1", cryptomus.MustAmount("1"), "USDT", "paid")))
+
+	cancel()
+	require.NoError(t, <-served)
+	require.Equal(t, int32(1), atomic.LoadInt32(&handled), "queued event must be drained on shutdown")
+}
@@ -0,0 +1,435 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/backtrac3r/go-cryptomus"
+	"github.com/backtrac3r/go-cryptomus/cryptomustest"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newErrorServer returns a client whose requests are answered with the status and body.
+func newErrorServer(t *testing.T, status int, body string) *cryptomus.Cryptomus {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	client := cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey)
+	client.SetBaseURL(server.URL + "/v1")
+
+	return client
+}
+
+func TestAPIErrorValidation(t *testing.T) {
+	client := newErrorServer(t, http.StatusUnprocessableEntity,
+		`{"state":1,"errors":{"amount":["The amount field is required."],"currency":["The currency field is required."]}}`)
+
+	_, err := client.CreateRecurrence(&cryptomus.RecurrenceRequest{
+		Amount: cryptomus.MustAmount("10"), Currency: "USDT", Name: "Plan", Period: "monthly",
+	})
+	var apiErr *cryptomus.APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, 1, apiErr.State)
+	require.Equal(t, http.StatusUnprocessableEntity, apiErr.StatusCode)
+	require.Equal(t, http.MethodPost, apiErr.Method)
+	require.Equal(t, "/v1/recurrence/create", apiErr.Endpoint)
+	require.Equal(t, []string{"The amount field is required."}, apiErr.Errors["amount"])
+	require.Contains(t, string(apiErr.Body), "The currency field is required.")
+	require.Equal(t, "cryptomus: POST /v1/recurrence/create: state 1, HTTP 422: validation errors: "+
+		"amount: The amount field is required.; currency: The currency field is required.", err.Error())
+}
+
+func TestAPIErrorMessage(t *testing.T) {
+	client := newErrorServer(t, http.StatusOK, `{"state":1,"message":"Payment not found"}`)
+
+	_, err := client.GetPaymentInfo(&cryptomus.PaymentInfoRequest{PaymentUUID: testUUID})
+	var apiErr *cryptomus.APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, "Payment not found", apiErr.Message)
+	require.Equal(t, testUUID, apiErr.UUID)
+	require.False(t, cryptomus.IsRetryable(err))
+	require.Equal(t, http.StatusOK, apiErr.StatusCode)
+	require.Equal(t, "cryptomus: POST /v1/payment/info [uuid=8b03432e-385b-4670-8d06-064591096795]: state 1, HTTP 200: Payment not found", err.Error())
+}
+
+func TestErrorRequestContext(t *testing.T) {
+	client := cryptomus.New(nil, "merchant", testPaymentKey, "")
+	client.SetBaseURL("http://127.0.0.1:0/v1")
+
+	_, err := client.GetPaymentInfo(&cryptomus.PaymentInfoRequest{OrderID: "order-1"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "cryptomus: POST /v1/payment/info [order_id=order-1]: HTTP request failed")
+	var transportErr *cryptomus.TransportError
+	require.ErrorAs(t, err, &transportErr)
+	require.True(t, cryptomus.IsRetryable(err))
+
+	_, err = client.GetPayoutInfo(&cryptomus.PayoutInfoRequest{PayoutUUID: testUUID})
+	require.ErrorIs(t, err, cryptomus.ErrMissingCredential)
+	require.Equal(t, "cryptomus: POST /v1/payout/info [uuid=8b03432e-385b-4670-8d06-064591096795]: payout API key is not configured", err.Error())
+
+	client = newErrorServer(t, http.StatusOK, `{"state":0,"result":[]}`)
+	_, err = client.GetPaymentInfo(&cryptomus.PaymentInfoRequest{PaymentUUID: testUUID})
+	require.ErrorContains(t, err, "cryptomus: POST /v1/payment/info [uuid=8b03432e-385b-4670-8d06-064591096795]: failed to decode response")
+	var decodeErr *cryptomus.DecodeError
+	require.ErrorAs(t, err, &decodeErr)
+	require.Equal(t, `{"state":0,"result":[]}`, string(decodeErr.Body))
+	require.False(t, cryptomus.IsRetryable(err))
+}
+
+func TestAPIErrorNonJSON(t *testing.T) {
+	client := newErrorServer(t, http.StatusBadGateway, `<html>Bad Gateway</html>`)
+
+	_, err := client.GetPayoutServicesList()
+	var apiErr *cryptomus.APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+	require.Equal(t, "<html>Bad Gateway</html>", string(apiErr.Body))
+}
+
+func TestErrorBodyOutlivesRequest(t *testing.T) {
+	// Responses are read into pooled buffers: the body of an error must not change with later requests
+	client := newErrorServer(t, http.StatusBadGateway, `<html>Bad Gateway</html>`)
+	_, err := client.GetPayoutServicesList()
+	var apiErr *cryptomus.APIError
+	require.ErrorAs(t, err, &apiErr)
+
+	other := newErrorServer(t, http.StatusBadGateway, `<html>Service Unavailable, retry later</html>`)
+	for i := 0; i < 10; i++ {
+		_, _ = other.GetPayoutServicesList()
+	}
+	require.Equal(t, "<html>Bad Gateway</html>", string(apiErr.Body))
+}
+
+func TestAPIErrorSentinels(t *testing.T) {
+	for _, tc := range []struct {
+		status   int
+		body     string
+		sentinel error
+	}{
+		{http.StatusOK, `{"state":1,"message":"Payment not found"}`, cryptomus.ErrNotFound},
+		{http.StatusNotFound, `{"state":1}`, cryptomus.ErrNotFound},
+		{http.StatusUnauthorized, `{"state":1,"message":"Unauthorized"}`, cryptomus.ErrUnauthorized},
+		{http.StatusUnauthorized, `{"state":1,"message":"Invalid Sign"}`, cryptomus.ErrInvalidSignature},
+		{http.StatusOK, `{"state":1,"message":"Insufficient funds on the balance"}`, cryptomus.ErrInsufficientFunds},
+		{http.StatusTooManyRequests, `{"message":"Too Many Attempts."}`, cryptomus.ErrRateLimited},
+	} {
+		client := newErrorServer(t, tc.status, tc.body)
+
+		_, err := client.GetPaymentInfo(&cryptomus.PaymentInfoRequest{PaymentUUID: testUUID})
+		require.ErrorIs(t, err, tc.sentinel, tc.body)
+	}
+
+	client := newErrorServer(t, http.StatusOK, `{"state":1,"message":"Payment not found"}`)
+	_, err := client.GetPaymentInfo(&cryptomus.PaymentInfoRequest{PaymentUUID: testUUID})
+	require.NotErrorIs(t, err, cryptomus.ErrUnauthorized)
+}
+
+func TestAPIErrorCode(t *testing.T) {
+	for _, tc := range []struct {
+		body string
+		code cryptomus.ErrorCode
+	}{
+		{`{"state":1,"message":"Payment not found"}`, cryptomus.CodePaymentNotFound},
+		{`{"state":1,"message":"Wallet not found"}`, cryptomus.CodeWalletNotFound},
+		{`{"state":1,"errors":{"amount":["Minimum amount 0.5 USDT"]}}`, cryptomus.CodeMinAmountNotReached},
+		{`{"state":1,"errors":{"order_id":["The order id has already been taken."]}}`, cryptomus.CodeDuplicateOrderID},
+		{`{"state":1,"errors":{"url_callback":["The url callback format is invalid."]}}`, cryptomus.CodeValidation},
+		{`{"state":1,"message":"Something new"}`, cryptomus.CodeUnknown},
+	} {
+		client := newErrorServer(t, http.StatusOK, tc.body)
+
+		_, err := client.GetPaymentInfo(&cryptomus.PaymentInfoRequest{PaymentUUID: testUUID})
+		var apiErr *cryptomus.APIError
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, tc.code, apiErr.Code(), tc.body)
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	client := newErrorServer(t, http.StatusUnprocessableEntity,
+		`{"state":1,"errors":{"network":"The network field is required.","amount":"Minimum amount 1"}}`)
+
+	_, err := client.CreatePayout(validPayout("o1"))
+	var validation cryptomus.ValidationErrors
+	require.ErrorAs(t, err, &validation)
+	require.Equal(t, []string{"amount", "network"}, validation.Fields())
+	require.True(t, validation.Has("network"))
+	require.False(t, validation.Has("currency"))
+	require.Equal(t, []string{"Minimum amount 1"}, validation.Field("amount"))
+	require.Equal(t, "validation errors: amount: Minimum amount 1; network: The network field is required.", validation.Error())
+}
+
+func TestRateLimitError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"message":"Too Many Attempts."}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey,
+		cryptomus.WithRetry(cryptomus.RetryPolicy{MaxRetries: 3, MaxWait: time.Second}))
+	client.SetBaseURL(server.URL + "/v1")
+
+	_, err := client.GetPaymentInfo(&cryptomus.PaymentInfoRequest{PaymentUUID: testUUID})
+	var rateErr *cryptomus.RateLimitError
+	require.ErrorAs(t, err, &rateErr)
+	require.Equal(t, 30*time.Second, rateErr.RetryAfter)
+	require.ErrorIs(t, err, cryptomus.ErrRateLimited)
+	var apiErr *cryptomus.APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+	require.Contains(t, err.Error(), "retry after 30s")
+	require.True(t, cryptomus.IsRetryable(err))
+}
+
+func TestRateLimitReset(t *testing.T) {
+	var calls int
+	reset := "30"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("X-RateLimit-Reset", reset)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	t.Cleanup(server.Close)
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	client := cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey,
+		cryptomus.WithClock(cryptomustest.NewClock(now)), cryptomus.WithRetry(cryptomus.RetryPolicy{MaxRetries: 3}))
+	client.SetBaseURL(server.URL + "/v1")
+
+	// A Unix timestamp is the time of the reset, and waits longer than the default limit aren't honored
+	reset = strconv.FormatInt(now.Add(90*time.Second).Unix(), 10)
+	_, err := client.GetPaymentInfo(&cryptomus.PaymentInfoRequest{PaymentUUID: testUUID})
+	var rateErr *cryptomus.RateLimitError
+	require.ErrorAs(t, err, &rateErr)
+	require.Equal(t, 90*time.Second, rateErr.RetryAfter)
+	require.Equal(t, 1, calls)
+
+	reset = "120"
+	_, err = client.GetPaymentInfo(&cryptomus.PaymentInfoRequest{PaymentUUID: testUUID})
+	require.ErrorAs(t, err, &rateErr)
+	require.Equal(t, 2*time.Minute, rateErr.RetryAfter)
+	require.Equal(t, 2, calls)
+}
+
+func TestRetryRateLimited(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		require.JSONEq(t, `{"uuid":"8b03432e-385b-4670-8d06-064591096795"}`, string(body))
+		if calls < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"state":0,"result":{"uuid":"8b03432e-385b-4670-8d06-064591096795"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey,
+		cryptomus.WithRetry(cryptomus.RetryPolicy{MaxRetries: 2, DefaultWait: time.Millisecond}))
+	client.SetBaseURL(server.URL + "/v1")
+
+	payment, err := client.GetPaymentInfo(&cryptomus.PaymentInfoRequest{PaymentUUID: testUUID})
+	require.NoError(t, err)
+	require.Equal(t, cryptomus.UUID(testUUID), payment.UUID)
+	require.Equal(t, 3, calls)
+}
+
+func TestAPIErrorMessageEveryEndpoint(t *testing.T) {
+	client := newErrorServer(t, http.StatusOK, `{"state":1,"message":"Merchant is blocked"}`)
+
+	for name, call := range map[string]func() error{
+		"CreateInvoice":   func() error { _, err := client.CreateInvoice(validInvoice("o1")); return err },
+		"PaymentQRCode":   func() error { _, err := client.GeneratePaymentQRCode(testUUID); return err },
+		"PaymentHistory":  func() error { _, err := client.GetPaymentHistory(time.Time{}, time.Time{}); return err },
+		"PaymentServices": func() error { _, err := client.GetPaymentServicesList(); return err },
+		"CreatePayout":    func() error { _, err := client.CreatePayout(validPayout("o1")); return err },
+		"PayoutHistory":   func() error { _, err := client.GetPayoutHistory(time.Time{}, time.Time{}); return err },
+		"StaticWallet": func() error {
+			_, err := client.CreateStaticWallet(validStaticWallet("w1"))
+			return err
+		},
+		"Refund": func() error {
+			_, err := client.Refund(&cryptomus.RefundRequest{Address: "T1", PaymentUUID: testUUID})
+			return err
+		},
+		"RecurrenceInfo": func() error {
+			_, err := client.GetRecurrenceInfo(&cryptomus.RecurrenceInfoRequest{UUID: testUUID})
+			return err
+		},
+		"ListRecurrences": func() error { _, err := client.ListRecurrences(nil); return err },
+		"ExchangeRates": func() error {
+			_, err := client.ListExchangeRates(context.Background(), "USDT", nil)
+			return err
+		},
+		"ResendWebhook": func() error {
+			_, err := client.ResendWebhook(&cryptomus.ResendWebhookRequest{PaymentUUID: testUUID})
+			return err
+		},
+	} {
+		var apiErr *cryptomus.APIError
+		require.ErrorAs(t, call(), &apiErr, name)
+		require.Equal(t, "Merchant is blocked", apiErr.Message, name)
+	}
+
+	client = newErrorServer(t, http.StatusForbidden, `{"error":"Access denied"}`)
+	_, err := client.GetPaymentServicesList()
+	var apiErr *cryptomus.APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, "Access denied", apiErr.Message)
+}
+
+func TestOnError(t *testing.T) {
+	var calls []cryptomus.CallInfo
+	var errs []error
+	hook := cryptomus.WithOnError(func(ctx context.Context, err error, call cryptomus.CallInfo) {
+		require.NotNil(t, ctx)
+		calls = append(calls, call)
+		errs = append(errs, err)
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/payment/info" {
+			_, _ = w.Write([]byte(`{"state":1,"message":"Payment not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"state":0,"result":[]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey, hook)
+	client.SetBaseURL(server.URL + "/v1")
+
+	_, err := client.GetPaymentServicesList()
+	require.NoError(t, err)
+	require.Empty(t, calls)
+
+	_, err = client.GetPaymentInfo(&cryptomus.PaymentInfoRequest{OrderID: "order-1"})
+	require.Error(t, err)
+	require.Len(t, calls, 1)
+	require.Equal(t, err, errs[0])
+	require.ErrorIs(t, errs[0], cryptomus.ErrNotFound)
+	require.Equal(t, http.MethodPost, calls[0].Method)
+	require.Equal(t, "/v1/payment/info", calls[0].Endpoint)
+	require.Equal(t, "order-1", calls[0].OrderID)
+
+	client = cryptomus.New(nil, "merchant", testPaymentKey, testPayoutKey, hook)
+	client.SetBaseURL("http://127.0.0.1:0/v1")
+	_, err = client.GetPayoutInfo(&cryptomus.PayoutInfoRequest{PayoutUUID: testUUID})
+	require.Len(t, calls, 2)
+	require.Equal(t, err, errs[1])
+	var transportErr *cryptomus.TransportError
+	require.ErrorAs(t, errs[1], &transportErr)
+	require.Equal(t, testUUID, calls[1].UUID)
+}
+
+func TestErrorJSON(t *testing.T) {
+	client := newErrorServer(t, http.StatusUnprocessableEntity,
+		`{"state":1,"errors":{"amount":["Minimum amount 0.5 USDT"]}}`)
+
+	_, err := client.CreateInvoice(validInvoice("order-1"))
+	var apiErr *cryptomus.APIError
+	require.ErrorAs(t, err, &apiErr)
+
+	data, err := json.Marshal(apiErr)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"error": "cryptomus: POST /v1/payment [order_id=order-1]: state 1, HTTP 422: validation errors: amount: Minimum amount 0.5 USDT",
+		"code": "min_amount_not_reached",
+		"state": 1,
+		"status_code": 422,
+		"errors": {"amount": ["Minimum amount 0.5 USDT"]},
+		"method": "POST",
+		"endpoint": "/v1/payment",
+		"order_id": "order-1"
+	}`, string(data))
+
+	data, err = json.Marshal(&cryptomus.RateLimitError{APIError: &cryptomus.APIError{
+		StatusCode: 429, Method: "POST", Endpoint: "/v1/payment", Message: "Too Many Attempts.",
+	}, RetryAfter: 1500 * time.Millisecond})
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"error": "cryptomus: POST /v1/payment: state 0, HTTP 429: Too Many Attempts.: retry after 1.5s",
+		"code": "rate_limited",
+		"state": 0,
+		"status_code": 429,
+		"message": "Too Many Attempts.",
+		"method": "POST",
+		"endpoint": "/v1/payment",
+		"retry_after_seconds": 1.5
+	}`, string(data))
+
+	data, err = json.Marshal(cryptomus.ValidationErrors(nil))
+	require.NoError(t, err)
+	require.Equal(t, `{}`, string(data))
+}
+
+func TestBatchError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			OrderID string `json:"order_id"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if strings.HasPrefix(req.OrderID, "bad") {
+			_, _ = w.Write([]byte(`{"state":1,"message":"Insufficient funds on the balance"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"state":0,"result":{"order_id":"` + req.OrderID + `"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey)
+	client.SetBaseURL(server.URL + "/v1")
+
+	payouts, err := client.CreatePayouts([]*cryptomus.PayoutRequest{
+		validPayout("o1"), validPayout("bad-1"), validPayout("o2"), validPayout("bad-2"),
+	})
+	var batchErr *cryptomus.BatchError
+	require.ErrorAs(t, err, &batchErr)
+	require.Equal(t, 4, batchErr.Total)
+	require.Equal(t, []int{0, 2}, batchErr.Succeeded)
+	require.Equal(t, []int{1, 3}, batchErr.Failed())
+	require.ErrorIs(t, err, cryptomus.ErrInsufficientFunds)
+	require.Len(t, payouts, 4)
+	require.Equal(t, "o2", payouts[2].OrderID)
+	require.Nil(t, payouts[1])
+	require.Contains(t, err.Error(), "2 of 4 batch items failed: item 1: ")
+
+	wallets, err := client.CreateStaticWallets([]*cryptomus.StaticWalletRequest{validStaticWallet("w1")})
+	require.NoError(t, err)
+	require.Equal(t, "w1", wallets[0].OrderID)
+}
+
+func TestStrictDecoding(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"state":0,"result":{"uuid":"8b03432e-385b-4670-8d06-064591096795","brand_new_field":true}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey)
+	client.SetBaseURL(server.URL + "/v1")
+	payment, err := client.GetPaymentInfo(&cryptomus.PaymentInfoRequest{PaymentUUID: testUUID})
+	require.NoError(t, err)
+	require.Equal(t, cryptomus.UUID(testUUID), payment.UUID)
+
+	client = cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey, cryptomus.WithStrictDecoding())
+	client.SetBaseURL(server.URL + "/v1")
+	_, err = client.GetPaymentInfo(&cryptomus.PaymentInfoRequest{PaymentUUID: testUUID})
+	var decodeErr *cryptomus.DecodeError
+	require.ErrorAs(t, err, &decodeErr)
+	require.ErrorContains(t, err, "brand_new_field")
+}
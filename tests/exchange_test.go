@@ -0,0 +1,270 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/backtrac3r/go-cryptomus"
+	"github.com/backtrac3r/go-cryptomus/cryptomustest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newExchangeRateServer(t *testing.T, hits *int32) *cryptomus.Cryptomus {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(hits, 1)
+		require.Equal(t, "/v1/exchange-rate/USDT/list", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"state":0,"result":[` +
+			`{"from":"USDT","to":"USD","course":"1.00010000"},` +
+			`{"from":"USDT","to":"EUR","course":"0.92140000"},` +
+			`{"from":"USDT","to":"BTC","course":"0.00001600"}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := cryptomus.New(server.Client(), "", "", "")
+	client.SetBaseURL(server.URL + "/v1")
+
+	return client
+}
+
+func TestListExchangeRatesFilter(t *testing.T) {
+	var hits int32
+	client := newExchangeRateServer(t, &hits)
+
+	rates, err := client.ListExchangeRates(context.Background(), "USDT", &cryptomus.ExchangeRateOptions{To: []string{"eur", "BTC"}})
+	require.NoError(t, err)
+	require.Len(t, rates, 2)
+	require.Equal(t, "EUR", rates[0].To)
+	require.Equal(t, "0.9214", rates[0].CourseString())
+	require.Equal(t, "BTC", rates[1].To)
+}
+
+func TestExchangeRateCache(t *testing.T) {
+	var hits int32
+	client := newExchangeRateServer(t, &hits)
+
+	cache := cryptomus.NewExchangeRateCache(client, &cryptomus.ExchangeRateCacheOptions{TTL: time.Hour})
+	defer cache.Close()
+
+	for i := 0; i < 3; i++ {
+		rates, err := cache.ListExchangeRates(context.Background(), "usdt", nil)
+		require.NoError(t, err)
+		require.Len(t, rates, 3)
+		require.Equal(t, i > 0, rates[0].CacheHit)
+		require.False(t, rates[0].FetchedAt.IsZero())
+	}
+	require.EqualValues(t, 1, atomic.LoadInt32(&hits))
+
+	cache.Invalidate("USDT")
+	_, err := cache.ListExchangeRates(context.Background(), "USDT", nil)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, atomic.LoadInt32(&hits))
+}
+
+func TestConvert(t *testing.T) {
+	var hits int32
+	client := newExchangeRateServer(t, &hits)
+
+	conversion, err := client.Convert(context.Background(), "12.5", "usdt", "eur")
+	require.NoError(t, err)
+	require.Equal(t, "11.5175", conversion.Amount.String())
+	require.Equal(t, "0.9214", conversion.Course.String())
+	require.Equal(t, "EUR", conversion.Rate.To)
+}
+
+func TestConvertCrossRate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/exchange-rate/LTC/list":
+			_, _ = w.Write([]byte(`{"state":0,"result":[{"from":"LTC","to":"USDT","course":"80"}]}`))
+		case "/v1/exchange-rate/USDT/list":
+			_, _ = w.Write([]byte(`{"state":0,"result":[{"from":"USDT","to":"EUR","course":"0.9"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"state":1,"message":"Not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := cryptomus.New(server.Client(), "", "", "")
+	client.SetBaseURL(server.URL + "/v1")
+
+	conversion, err := client.Convert(context.Background(), "2", "LTC", "EUR")
+	require.NoError(t, err)
+	require.True(t, conversion.Derived)
+	require.Equal(t, "USDT", conversion.Via)
+	require.Equal(t, "72", conversion.Course.String())
+	require.Equal(t, "144", conversion.Amount.String())
+}
+
+func TestConvertCrossRateThroughUSD(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/exchange-rate/LTC/list":
+			_, _ = w.Write([]byte(`{"state":0,"result":[{"from":"LTC","to":"USD","course":"80"}]}`))
+		case "/v1/exchange-rate/XMR/list":
+			_, _ = w.Write([]byte(`{"state":0,"result":[{"from":"XMR","to":"USD","course":"160"}]}`))
+		case "/v1/exchange-rate/USDT/list":
+			_, _ = w.Write([]byte(`{"state":0,"result":[{"from":"USDT","to":"EUR","course":"0.9"}]}`))
+		default:
+			// Listings of fiat currencies are rejected
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"state":1,"message":"Currency not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := cryptomus.New(server.Client(), "", "", "")
+	client.SetBaseURL(server.URL + "/v1")
+
+	// Only the USD route prices the pair, its USD leg is the inverse of the XMR listing
+	conversion, err := client.Convert(context.Background(), "3", "LTC", "XMR")
+	require.NoError(t, err)
+	require.True(t, conversion.Derived)
+	require.Equal(t, "USD", conversion.Via)
+	require.Equal(t, "0.5", conversion.Course.String())
+	require.Equal(t, "1.5", conversion.Amount.String())
+
+	_, err = client.Convert(context.Background(), "3", "LTC", "EUR")
+	require.ErrorContains(t, err, "no exchange rate found for LTC/EUR")
+	require.ErrorIs(t, err, cryptomus.ErrNotFound)
+}
+
+func TestListExchangeRatesEmpty(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"state":0,"result":[]}`))
+	}))
+	defer server.Close()
+
+	client := cryptomus.New(server.Client(), "", "", "")
+	client.SetBaseURL(server.URL + "/v1")
+
+	rates, err := client.ListExchangeRates(context.Background(), "XMR", nil)
+	require.NoError(t, err)
+	require.NotNil(t, rates)
+	require.Empty(t, rates)
+}
+
+func TestGroupExchangeRates(t *testing.T) {
+	rates := []cryptomus.ExchangeRate{
+		{From: "USDT", To: "USD", Course: cryptomus.MustAmount("1")},
+		{From: "USDT", To: "BTC", Course: cryptomus.MustAmount("0.000016")},
+		{From: "USDT", To: "eur", Course: cryptomus.MustAmount("0.92")},
+	}
+
+	fiat, crypto := cryptomus.GroupExchangeRates(rates)
+	require.Len(t, fiat, 2)
+	require.Len(t, crypto, 1)
+	require.Equal(t, "BTC", crypto[0].To)
+
+	require.Len(t, cryptomus.FilterExchangeRates(rates, "EUR", "btc"), 2)
+	require.Empty(t, cryptomus.FilterExchangeRates(rates))
+}
+
+func TestChainedRateProvider(t *testing.T) {
+	failing := cryptomus.RateProviderFunc(func(ctx context.Context, currency string, opts *cryptomus.ExchangeRateOptions) ([]cryptomus.ExchangeRate, error) {
+		return nil, errors.New("unavailable")
+	})
+	fallback := cryptomus.RateProviderFunc(func(ctx context.Context, currency string, opts *cryptomus.ExchangeRateOptions) ([]cryptomus.ExchangeRate, error) {
+		return []cryptomus.ExchangeRate{{From: currency, To: "EUR", Course: cryptomus.MustAmount("0.5")}}, nil
+	})
+
+	provider := cryptomus.NewChainedRateProvider(failing, fallback)
+	conversion, err := cryptomus.Convert(context.Background(), provider, "10", "USDT", "EUR")
+	require.NoError(t, err)
+	require.Equal(t, "5", conversion.Amount.String())
+
+	_, err = cryptomus.NewChainedRateProvider(failing).ListExchangeRates(context.Background(), "USDT", nil)
+	require.ErrorContains(t, err, "unavailable")
+}
+
+func TestRecordingRateProvider(t *testing.T) {
+	var hits int32
+	client := newExchangeRateServer(t, &hits)
+
+	provider := cryptomus.NewRecordingRateProvider(client, cryptomus.NewMemoryRateSnapshotStore())
+	before := time.Now()
+
+	_, err := provider.ListExchangeRates(context.Background(), "USDT", &cryptomus.ExchangeRateOptions{To: []string{"USD"}})
+	require.NoError(t, err)
+
+	rate, fetchedAt, err := provider.RateAt(context.Background(), "USDT", "EUR", time.Now())
+	require.NoError(t, err)
+	require.Equal(t, "0.9214", rate.CourseString())
+	require.False(t, fetchedAt.Before(before))
+
+	_, _, err = provider.RateAt(context.Background(), "USDT", "EUR", before.Add(-time.Hour))
+	require.ErrorIs(t, err, cryptomus.ErrRateSnapshotNotFound)
+
+	// Modifying the rates returned leaves the recorded ones as they were
+	rates, err := provider.ListExchangeRates(context.Background(), "USDT", nil)
+	require.NoError(t, err)
+	for i := range rates {
+		rates[i].Course = cryptomus.MustAmount("1000")
+	}
+	rate, _, err = provider.RateAt(context.Background(), "USDT", "EUR", time.Now())
+	require.NoError(t, err)
+	require.Equal(t, "0.9214", rate.CourseString())
+}
+
+// countingSnapshotStore counts the saved snapshots, failing to save them while failing is set.
+type countingSnapshotStore struct {
+	*cryptomus.MemoryRateSnapshotStore
+	saves   int
+	failing bool
+}
+
+func (s *countingSnapshotStore) SaveRateSnapshot(ctx context.Context, snapshot *cryptomus.RateSnapshot) error {
+	if s.failing {
+		return errors.New("database is down")
+	}
+	s.saves++
+	return s.MemoryRateSnapshotStore.SaveRateSnapshot(ctx, snapshot)
+}
+
+func TestRecordingRateProviderCache(t *testing.T) {
+	clock := cryptomustest.NewClock(clockStart)
+	source := cryptomus.RateProviderFunc(func(ctx context.Context, currency string, opts *cryptomus.ExchangeRateOptions) ([]cryptomus.ExchangeRate, error) {
+		return []cryptomus.ExchangeRate{{From: currency, To: "EUR", Course: cryptomus.MustAmount("0.92"), FetchedAt: clock.Now()}}, nil
+	})
+	cache := cryptomus.NewExchangeRateCache(source, &cryptomus.ExchangeRateCacheOptions{TTL: time.Minute, Clock: clock})
+	defer cache.Close()
+
+	store := &countingSnapshotStore{MemoryRateSnapshotStore: cryptomus.NewMemoryRateSnapshotStore(), failing: true}
+	var failures int
+	provider := cryptomus.NewRecordingRateProvider(cache, store)
+	provider.OnError = func(error) { failures++ }
+
+	list := func() {
+		t.Helper()
+		_, err := provider.ListExchangeRates(context.Background(), "USDT", nil)
+		require.NoError(t, err)
+	}
+
+	// A snapshot that failed to be saved is saved on the next lookup, even from the cache
+	list()
+	require.Equal(t, 1, failures)
+	store.failing = false
+	list()
+	require.Equal(t, 1, store.saves)
+
+	// Cached rates are recorded once, until they are fetched again
+	list()
+	list()
+	require.Equal(t, 1, store.saves)
+
+	clock.Advance(2 * time.Minute)
+	list()
+	list()
+	require.Equal(t, 2, store.saves)
+	require.Equal(t, 1, failures)
+}
@@ -0,0 +1,112 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/backtrac3r/go-cryptomus"
+	"github.com/backtrac3r/go-cryptomus/cryptomusfake"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeInvoiceFlow(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	fake := cryptomusfake.New()
+	fake.Now = func() time.Time { return now }
+	var api cryptomus.PaymentsAPI = fake
+
+	payment, err := api.CreateInvoice(validInvoice("o1"))
+	require.NoError(t, err)
+	require.Equal(t, cryptomus.PaymentStatusCheck, payment.PaymentStatus)
+	require.True(t, payment.UUID.IsValid())
+
+	again, err := api.CreateInvoice(validInvoice("o1"))
+	require.NoError(t, err)
+	require.Equal(t, payment.UUID, again.UUID)
+
+	require.NoError(t, fake.MarkPaid("o1"))
+	info, err := api.GetPaymentInfo(&cryptomus.PaymentInfoRequest{PaymentUUID: payment.UUID})
+	require.NoError(t, err)
+	require.Equal(t, cryptomus.PaymentStatusPaid, info.PaymentStatus)
+	require.True(t, info.IsFinal)
+	require.Error(t, fake.MarkPaid("o1"))
+
+	refunded, err := api.Refund(&cryptomus.RefundRequest{Address: "addr", OrderID: "o1"})
+	require.NoError(t, err)
+	require.True(t, refunded)
+	info, err = api.GetPaymentInfo(&cryptomus.PaymentInfoRequest{OrderID: "o1"})
+	require.NoError(t, err)
+	require.Equal(t, cryptomus.PaymentStatusRefundProcess, info.PaymentStatus)
+
+	// Unpaid invoices expire
+	_, err = api.CreateInvoice(validInvoice("o2"))
+	require.NoError(t, err)
+	now = now.Add(2 * cryptomusfake.DefaultInvoiceLifetime)
+	info, err = api.GetPaymentInfo(&cryptomus.PaymentInfoRequest{OrderID: "o2"})
+	require.NoError(t, err)
+	require.Equal(t, cryptomus.PaymentStatusCancel, info.PaymentStatus)
+
+	_, err = api.GetPaymentInfo(&cryptomus.PaymentInfoRequest{OrderID: "missing"})
+	require.ErrorIs(t, err, cryptomus.ErrNotFound)
+
+	payments, err := api.ListAllPayments(context.Background(), nil, nil)
+	require.NoError(t, err)
+	require.Len(t, payments, 2)
+	require.Equal(t, "o2", payments[0].OrderID)
+
+	wallet, err := api.CreateStaticWallet(validStaticWallet("w1"))
+	require.NoError(t, err)
+	deposit, err := fake.Deposit("w1", cryptomus.MustAmount("15"))
+	require.NoError(t, err)
+	require.Equal(t, wallet.Address, deposit.Address)
+	_, err = api.BlockAddress(&cryptomus.BlockAddressRequest{OrderID: "w1"})
+	require.NoError(t, err)
+	_, err = fake.Deposit("w1", cryptomus.MustAmount("1"))
+	require.Error(t, err)
+	refund, err := api.BlockedAddressRefund(&cryptomus.BlockedAddressRefundRequest{OrderID: "w1", Address: "addr"})
+	require.NoError(t, err)
+	require.Equal(t, "15", refund.Amount.String())
+	require.True(t, refund.Commision.IsZero())
+}
+
+func TestFakePayoutsAndRecurrences(t *testing.T) {
+	fake := cryptomusfake.New()
+	var api cryptomus.CryptomusAPI = fake
+
+	fake.SetBalance("USDT", cryptomus.MustAmount("15"))
+	payout, err := api.CreatePayout(validPayout("p1"))
+	require.NoError(t, err)
+	require.Equal(t, cryptomus.PayoutStatusProcess, payout.Status)
+	_, err = api.CreatePayout(validPayout("p2"))
+	require.ErrorIs(t, err, cryptomus.ErrInsufficientFunds)
+
+	require.NoError(t, fake.MarkPayoutFailed("p1"))
+	balance, _ := fake.Balance("USDT")
+	require.Equal(t, "15", balance.String())
+	payout, err = api.GetPayoutInfo(&cryptomus.PayoutInfoRequest{OrderID: "p1"})
+	require.NoError(t, err)
+	require.True(t, payout.IsFinal)
+
+	recurrence, err := api.CreateRecurrence(&cryptomus.RecurrenceRequest{
+		Amount: cryptomus.MustAmount("10"), Currency: "USDT", Name: "Plan", Period: "monthly", OrderID: "r1",
+	})
+	require.NoError(t, err)
+	require.Equal(t, cryptomus.RecurrenceStatusWaitAccept, recurrence.Status)
+	require.NoError(t, fake.AcceptRecurrence("r1"))
+	active, err := api.ListAllRecurrences(context.Background(), &cryptomus.RecurrenceListOptions{Status: cryptomus.RecurrenceStatusActive}, nil)
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+
+	newAmount := cryptomus.MustAmount("20")
+	change, err := api.ChangeRecurrencePlan(&cryptomus.RecurrencePlanChangeRequest{OrderID: "r1", Amount: &newAmount})
+	require.NoError(t, err)
+	require.Equal(t, cryptomus.RecurrenceStatusCancelByMerchant, change.Old.Status)
+	require.Equal(t, "20", change.New.Amount.String())
+
+	fake.SetExchangeRate("BTC", "USDT", cryptomus.MustAmount("60000"))
+	conversion, err := api.Convert(context.Background(), "0.5", "BTC", "USDT")
+	require.NoError(t, err)
+	require.Equal(t, "30000", conversion.Amount.String())
+}
@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/backtrac3r/go-cryptomus"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Environment variables configuring the live tests, which run against the real API and are skipped when
+// the credentials are not set:
+//
+//	CRYPTOMUS_MERCHANT=... CRYPTOMUS_PAYMENT_KEY=... CRYPTOMUS_PAYOUT_KEY=... go test ./tests -run Live
+//
+// Destructive tests, which move funds or change the state of the account, also need CRYPTOMUS_DESTRUCTIVE=1.
+const (
+	merchantEnv    = "CRYPTOMUS_MERCHANT"
+	paymentKeyEnv  = "CRYPTOMUS_PAYMENT_KEY"
+	payoutKeyEnv   = "CRYPTOMUS_PAYOUT_KEY"
+	destructiveEnv = "CRYPTOMUS_DESTRUCTIVE"
+)
+
+// liveCredentials returns the credentials of the environment, skipping the test if they are not set.
+func liveCredentials(t *testing.T) (merchant, paymentKey, payoutKey string) {
+	merchant, paymentKey, payoutKey = os.Getenv(merchantEnv), os.Getenv(paymentKeyEnv), os.Getenv(payoutKeyEnv)
+	if merchant == "" || paymentKey == "" || payoutKey == "" {
+		t.Skipf("live test: set %s, %s and %s to run it", merchantEnv, paymentKeyEnv, payoutKeyEnv)
+	}
+
+	return merchant, paymentKey, payoutKey
+}
+
+// liveClient returns a client of the real API, skipping the test if the credentials are not set.
+func liveClient(t *testing.T) *cryptomus.Cryptomus {
+	merchant, paymentKey, payoutKey := liveCredentials(t)
+
+	return cryptomus.New(nil, merchant, paymentKey, payoutKey)
+}
+
+// destructive skips the test unless destructive tests are enabled.
+func destructive(t *testing.T) {
+	if enabled, _ := strconv.ParseBool(os.Getenv(destructiveEnv)); !enabled {
+		t.Skipf("destructive live test: set %s=1 to run it", destructiveEnv)
+	}
+}
+
+// liveOrderID returns an order_id unique to the run, so live tests don't collide with previous runs.
+func liveOrderID(t *testing.T) string {
+	return "sdk-" + t.Name() + "-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+}
+
+func TestLiveServices(t *testing.T) {
+	client := liveClient(t)
+
+	payments, err := client.GetPaymentServicesList()
+	require.NoError(t, err)
+	require.NotEmpty(t, payments)
+
+	payouts, err := client.GetPayoutServicesList()
+	require.NoError(t, err)
+	require.NotEmpty(t, payouts)
+}
+
+func TestLiveExchangeRates(t *testing.T) {
+	client := liveClient(t)
+
+	rates, err := client.ListExchangeRates(context.Background(), "BTC", &cryptomus.ExchangeRateOptions{To: []string{"USDT"}})
+	require.NoError(t, err)
+	require.Len(t, rates, 1)
+	require.True(t, rates[0].Course.IsPositive())
+}
+
+func TestLiveHistory(t *testing.T) {
+	client := liveClient(t)
+	opts := &cryptomus.HistoryOptions{DateFrom: time.Now().AddDate(0, 0, -7), PerPage: 10}
+
+	_, err := client.ListAllPayments(context.Background(), opts, &cryptomus.ListAllOptions{MaxPages: 2})
+	if err != nil {
+		require.ErrorIs(t, err, cryptomus.ErrListTruncated)
+	}
+	_, err = client.ListAllPayouts(context.Background(), opts, &cryptomus.ListAllOptions{MaxPages: 2})
+	if err != nil {
+		require.ErrorIs(t, err, cryptomus.ErrListTruncated)
+	}
+}
+
+func TestLiveInvoice(t *testing.T) {
+	client := liveClient(t)
+
+	invoice, err := client.CreateInvoice(validInvoice(liveOrderID(t)))
+	require.NoError(t, err)
+	require.True(t, invoice.UUID.IsValid())
+
+	payment, err := client.GetPaymentInfo(&cryptomus.PaymentInfoRequest{PaymentUUID: invoice.UUID})
+	require.NoError(t, err)
+	require.Equal(t, invoice.OrderID, payment.OrderID)
+
+	qrCode, err := client.GeneratePaymentQRCode(invoice.UUID)
+	require.NoError(t, err)
+	require.NotEmpty(t, qrCode)
+}
+
+func TestLiveStaticWallet(t *testing.T) {
+	destructive(t)
+	client := liveClient(t)
+
+	// Static wallets cannot be deleted, only blocked
+	wallet, err := client.CreateStaticWallet(validStaticWallet(liveOrderID(t)))
+	require.NoError(t, err)
+	require.NotEmpty(t, wallet.Address)
+
+	blocked, err := client.BlockAddress(&cryptomus.BlockAddressRequest{WalletUUID: wallet.UUID})
+	require.NoError(t, err)
+	require.Equal(t, cryptomus.WalletStatusBlocked, blocked.Status)
+}
+
+func TestLivePayout(t *testing.T) {
+	destructive(t)
+	client := liveClient(t)
+
+	// Pays out the minimum amount to the address of the environment, as it moves real funds
+	address := os.Getenv("CRYPTOMUS_PAYOUT_ADDRESS")
+	if address == "" {
+		t.Skip("destructive live test: set CRYPTOMUS_PAYOUT_ADDRESS to run it")
+	}
+	services, err := client.GetPayoutServicesList()
+	require.NoError(t, err)
+
+	req := validPayout(liveOrderID(t))
+	req.Address = address
+	for _, service := range services {
+		if service.Currency == string(req.Currency) && service.Network == string(req.Network) && service.Limit != nil {
+			req.Amount = service.Limit.MinAmount
+		}
+	}
+
+	payout, err := client.CreatePayout(req)
+	require.NoError(t, err)
+	require.True(t, payout.UUID.IsValid())
+}
@@ -1,21 +1,29 @@
 package tests
 
 import (
-	"net/http"
-	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/backtrac3r/go-cryptomus"
+	"github.com/backtrac3r/go-cryptomus/cryptomusvcr"
+
+	"github.com/stretchr/testify/require"
 )
 
-var TestCryptomus *cryptomus.Cryptomus
+// recordedClient returns a client replaying the fixture of the test from testdata/vcr. With CRYPTOMUS_RECORD=1,
+// the fixture is recorded from the live API instead, with the credentials of the environment, see liveCredentials.
+// Fixtures with a comment are synthetic: written by hand after the documented responses, they are to be
+// recorded for real.
+func recordedClient(t *testing.T) *cryptomus.Cryptomus {
+	merchant, paymentKey, payoutKey := "merchant", testPaymentKey, testPayoutKey
+	mode := cryptomusvcr.ModeFromEnv()
+	if mode == cryptomusvcr.ModeRecord {
+		merchant, paymentKey, payoutKey = liveCredentials(t)
+	}
 
-func TestMain(m *testing.M) {
-	httpClient := http.Client{}
-	merchant := "replace with your merchant id"
-	paymentAPIKey := "replace with your payment API key"
-	payoutAPIKey := "replace with your payout API key"
-	TestCryptomus = cryptomus.New(&httpClient, merchant, paymentAPIKey, payoutAPIKey)
+	rec, err := cryptomusvcr.New(filepath.Join("testdata", "vcr", t.Name()+".json"), mode)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, rec.Stop()) })
 
-	os.Exit(m.Run())
+	return cryptomus.New(rec.Client(), merchant, paymentKey, payoutKey)
 }
@@ -0,0 +1,50 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/backtrac3r/go-cryptomus"
+	"github.com/backtrac3r/go-cryptomus/cryptomusmock"
+
+	"github.com/stretchr/testify/require"
+)
+
+// checkout stands for application code depending on the interfaces rather than on *cryptomus.Cryptomus.
+func checkout(api cryptomus.PaymentsAPI, orderID string) (string, error) {
+	payment, err := api.CreateInvoice(validInvoice(orderID))
+	if err != nil {
+		return "", err
+	}
+
+	return payment.Url, nil
+}
+
+func TestMocks(t *testing.T) {
+	api := &cryptomusmock.PaymentsAPIMock{
+		CreateInvoiceFunc: func(invoiceReq *cryptomus.InvoiceRequest) (*cryptomus.Payment, error) {
+			return &cryptomus.Payment{OrderID: invoiceReq.OrderID, Url: "https://pay.cryptomus.com/pay/" + invoiceReq.OrderID}, nil
+		},
+	}
+
+	url, err := checkout(api, "o1")
+	require.NoError(t, err)
+	require.Equal(t, "https://pay.cryptomus.com/pay/o1", url)
+	require.Len(t, api.CreateInvoiceCalls(), 1)
+	require.Equal(t, "o1", api.CreateInvoiceCalls()[0].InvoiceReq.OrderID)
+
+	all := &cryptomusmock.CryptomusAPIMock{
+		PayoutHistoryFunc: func(ctx context.Context, opts *cryptomus.HistoryOptions) *cryptomus.Iterator[*cryptomus.Payout] {
+			return cryptomus.NewSliceIterator(ctx, []*cryptomus.Payout{{OrderID: "p1"}, {OrderID: "p2"}})
+		},
+	}
+	it := all.PayoutHistory(context.Background(), nil)
+	var orderIDs []string
+	for it.Next() {
+		orderIDs = append(orderIDs, it.Item().OrderID)
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []string{"p1", "p2"}, orderIDs)
+
+	require.Panics(t, func() { _, _ = all.CreatePayout(validPayout("p3")) })
+}
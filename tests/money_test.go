@@ -0,0 +1,132 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/backtrac3r/go-cryptomus"
+	"github.com/shopspring/decimal"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoney(t *testing.T) {
+	price := cryptomus.NewMoney(cryptomus.MustAmount("100"), "usdt")
+	require.Equal(t, "100 USDT", price.String())
+
+	fee := price.Mul(decimal.RequireFromString("0.02"))
+	require.Equal(t, "2 USDT", fee.String())
+
+	net, err := price.Sub(fee)
+	require.NoError(t, err)
+	require.True(t, net.Equal(cryptomus.NewMoney(cryptomus.MustAmount("98"), "USDT")))
+
+	total, err := net.Add(fee)
+	require.NoError(t, err)
+	require.True(t, total.Equal(price))
+
+	_, err = price.Add(cryptomus.NewMoney(cryptomus.MustAmount("1"), "BTC"))
+	require.ErrorIs(t, err, cryptomus.ErrCurrencyMismatch)
+
+	eur, err := price.Convert(cryptomus.ExchangeRate{From: "USDT", To: "EUR", Course: cryptomus.MustAmount("0.92")})
+	require.NoError(t, err)
+	require.Equal(t, "92 EUR", eur.String())
+	_, err = eur.Convert(cryptomus.ExchangeRate{From: "USDT", To: "BTC", Course: cryptomus.MustAmount("0.00001")})
+	require.ErrorIs(t, err, cryptomus.ErrCurrencyMismatch)
+}
+
+func TestMoneySplit(t *testing.T) {
+	parts, err := cryptomus.NewMoney(cryptomus.MustAmount("10"), "USDT").Split(3, 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"3.34 USDT", "3.33 USDT", "3.33 USDT"},
+		[]string{parts[0].String(), parts[1].String(), parts[2].String()})
+
+	parts, err = cryptomus.NewMoney(cryptomus.MustAmount("-0.05"), "USDT").Split(2, 2)
+	require.NoError(t, err)
+	require.Equal(t, "-0.03", parts[0].Amount.String())
+	require.Equal(t, "-0.02", parts[1].Amount.String())
+
+	_, err = cryptomus.NewMoney(cryptomus.MustAmount("1"), "USDT").Split(0, 2)
+	require.Error(t, err)
+}
+
+func TestCurrencyCode(t *testing.T) {
+	require.True(t, cryptomus.CurrencyUSD.IsFiat())
+	require.False(t, cryptomus.CurrencyUSD.IsCrypto())
+	require.True(t, cryptomus.CurrencyCode("usdt").IsCrypto())
+	require.True(t, cryptomus.CurrencyCode("eur").IsKnown())
+	require.False(t, cryptomus.CurrencyCode("NEWCOIN").IsKnown())
+	require.True(t, cryptomus.CurrencyCode("NEWCOIN").IsValid())
+	require.False(t, cryptomus.CurrencyCode("US D").IsValid())
+
+	client := cryptomus.New(nil, "merchant", testPaymentKey, testPayoutKey)
+	_, err := client.CreateStaticWallet(&cryptomus.StaticWalletRequest{Currency: cryptomus.CurrencyEUR, Network: "tron"})
+	var validation cryptomus.ValidationErrors
+	require.ErrorAs(t, err, &validation)
+	require.True(t, validation.Has("currency"))
+
+	_, err = client.CreateInvoice(&cryptomus.InvoiceRequest{Amount: cryptomus.MustAmount("1"), Currency: "U$D"})
+	require.ErrorAs(t, err, &validation)
+}
+
+func TestNetwork(t *testing.T) {
+	require.True(t, cryptomus.CurrencyUSDT.SupportsNetwork(cryptomus.NetworkTron))
+	require.True(t, cryptomus.CurrencyUSDT.SupportsNetwork("TRON"))
+	require.False(t, cryptomus.CurrencyBTC.SupportsNetwork(cryptomus.NetworkTron))
+	require.True(t, cryptomus.CurrencyCode("NEWCOIN").SupportsNetwork(cryptomus.NetworkTron))
+	require.True(t, cryptomus.CurrencyBTC.SupportsNetwork("newchain"))
+	require.Equal(t, []cryptomus.Network{cryptomus.NetworkTON}, cryptomus.CurrencyNOT.Networks())
+
+	client := cryptomus.New(nil, "merchant", testPaymentKey, testPayoutKey)
+	_, err := client.CreatePayout(&cryptomus.PayoutRequest{
+		Amount: cryptomus.MustAmount("1"), Currency: cryptomus.CurrencyBTC, Network: cryptomus.NetworkTron,
+	})
+	var validation cryptomus.ValidationErrors
+	require.ErrorAs(t, err, &validation)
+	require.True(t, validation.Has("network"))
+
+	_, err = client.CreateStaticWallet(&cryptomus.StaticWalletRequest{Currency: cryptomus.CurrencyTRX, Network: cryptomus.NetworkBSC})
+	require.ErrorAs(t, err, &validation)
+}
+
+func TestStatuses(t *testing.T) {
+	require.True(t, cryptomus.PaymentStatusPaidOver.IsFinal())
+	require.True(t, cryptomus.PaymentStatusPaidOver.IsSuccessful())
+	require.False(t, cryptomus.PaymentStatusConfirmCheck.IsFinal())
+	require.Equal(t, cryptomus.RefundStatusPaid, cryptomus.PaymentStatusRefundPaid.RefundStatus())
+	require.Equal(t, cryptomus.RefundStatus(""), cryptomus.PaymentStatusPaid.RefundStatus())
+	require.False(t, cryptomus.PayoutStatusCheck.IsFinal())
+	require.False(t, cryptomus.PayoutStatusFail.IsSuccessful())
+	require.True(t, cryptomus.WalletStatusBlocked.IsFinal())
+	require.False(t, cryptomus.TransferStatusProcess.IsFinal())
+	require.True(t, cryptomus.TransferStatusCancel.IsFinal())
+	require.False(t, cryptomus.TransferStatusCancel.IsSuccessful())
+	require.True(t, cryptomus.TransferStatusPaid.IsSuccessful())
+	require.True(t, cryptomus.RecurrenceStatusCancelByUser.IsFinal())
+	require.True(t, cryptomus.RecurrenceStatusActive.IsSuccessful())
+}
+
+func TestUnknownEnumValues(t *testing.T) {
+	payment := &cryptomus.Payment{}
+	require.NoError(t, json.Unmarshal([]byte(`{"uuid":"p1","payment_status":"awaiting_kyc"}`), payment))
+	require.Equal(t, cryptomus.PaymentStatus("awaiting_kyc"), payment.PaymentStatus)
+	require.False(t, payment.PaymentStatus.IsKnown())
+	require.False(t, payment.PaymentStatus.IsFinal())
+	require.False(t, payment.PaymentStatus.IsSuccessful())
+	require.True(t, cryptomus.PaymentStatusPaid.IsKnown())
+
+	data, err := json.Marshal(payment)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"payment_status":"awaiting_kyc"`)
+
+	require.False(t, cryptomus.PayoutStatus("queued").IsKnown())
+	require.False(t, cryptomus.PayoutStatus("queued").IsFinal())
+	require.False(t, cryptomus.RefundStatus("partial").IsFinal())
+	require.True(t, cryptomus.RefundStatusFail.IsKnown())
+	require.False(t, cryptomus.WalletStatus("frozen").IsKnown())
+	require.False(t, cryptomus.TransferStatus("queued").IsKnown())
+	require.False(t, cryptomus.TransferStatus("queued").IsFinal())
+	require.False(t, cryptomus.RecurrenceStatus("paused").IsKnown())
+	require.True(t, cryptomus.WebhookTypeRecurrence.IsKnown())
+	require.False(t, cryptomus.WebhookType("transfer").IsKnown())
+}
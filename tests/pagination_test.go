@@ -0,0 +1,395 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/backtrac3r/go-cryptomus"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newHistoryServer serves pages of per items out of total from the history endpoints, the cursor of
+// a page being the index of its first item. It records the cursors and bodies of the requests.
+func newHistoryServer(t *testing.T, total, per int) (*cryptomus.Cryptomus, *[]string) {
+	var cursors []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cursor := r.URL.Query().Get("cursor")
+		cursors = append(cursors, cursor)
+		start, _ := strconv.Atoi(cursor)
+
+		items := []map[string]string{}
+		for i := start; i < start+per && i < total; i++ {
+			items = append(items, map[string]string{"uuid": fmt.Sprintf("item-%d", i)})
+		}
+		paginate := map[string]interface{}{"count": len(items), "perPage": per, "hasPages": total > per}
+		if start+per < total {
+			paginate["nextCursor"] = strconv.Itoa(start + per)
+		}
+		if start > 0 {
+			paginate["previousCursor"] = strconv.Itoa(start - per)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"state": 0, "result": map[string]interface{}{"items": items, "paginate": paginate}})
+	}))
+	t.Cleanup(server.Close)
+
+	client := cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey)
+	client.SetBaseURL(server.URL + "/v1")
+
+	return client, &cursors
+}
+
+func TestPaymentHistoryIterator(t *testing.T) {
+	client, cursors := newHistoryServer(t, 7, 3)
+
+	it := client.PaymentHistory(context.Background(), nil)
+	var uuids []cryptomus.UUID
+	for it.Next() {
+		uuids = append(uuids, it.Item().UUID)
+	}
+	require.NoError(t, it.Err())
+	require.Len(t, uuids, 7)
+	require.Equal(t, cryptomus.UUID("item-6"), uuids[6])
+	require.Equal(t, []string{"", "3", "6"}, *cursors)
+	require.False(t, it.Next())
+
+	pages := client.PayoutHistory(context.Background(), nil)
+	var sizes []int
+	for pages.NextPage() {
+		sizes = append(sizes, len(pages.Page().Items))
+	}
+	require.NoError(t, pages.Err())
+	require.Equal(t, []int{3, 3, 1}, sizes)
+}
+
+func TestIteratorStopsOnError(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls > 1 {
+			_, _ = w.Write([]byte(`{"state":1,"message":"Insufficient funds on the balance"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"state":0,"result":{"items":[{"uuid":"r1"}],"paginate":{"nextCursor":"next"}}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey)
+	client.SetBaseURL(server.URL + "/v1")
+
+	it := client.Recurrences(context.Background(), nil)
+	require.True(t, it.Next())
+	require.Equal(t, cryptomus.UUID("r1"), it.Item().UUID)
+	require.False(t, it.Next())
+	require.ErrorIs(t, it.Err(), cryptomus.ErrInsufficientFunds)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	it = client.Recurrences(ctx, nil)
+	require.False(t, it.Next())
+	require.ErrorIs(t, it.Err(), context.Canceled)
+}
+
+func TestListAll(t *testing.T) {
+	client, _ := newHistoryServer(t, 7, 3)
+
+	payments, err := client.ListAllPayments(context.Background(), nil, nil)
+	require.NoError(t, err)
+	require.Len(t, payments, 7)
+
+	payouts, err := client.ListAllPayouts(context.Background(), nil, &cryptomus.ListAllOptions{MaxItems: 7})
+	require.NoError(t, err)
+	require.Len(t, payouts, 7)
+
+	payouts, err = client.ListAllPayouts(context.Background(), nil, &cryptomus.ListAllOptions{MaxItems: 5})
+	require.ErrorIs(t, err, cryptomus.ErrListTruncated)
+	require.EqualError(t, err, "listing truncated: more than 5 items")
+	require.Len(t, payouts, 5)
+
+	payments, err = client.ListAllPayments(context.Background(), nil, &cryptomus.ListAllOptions{MaxPages: 2})
+	require.ErrorIs(t, err, cryptomus.ErrListTruncated)
+	require.Len(t, payments, 6)
+
+	payments, err = client.ListAllPayments(context.Background(), nil, &cryptomus.ListAllOptions{MaxPages: 3})
+	require.NoError(t, err)
+	require.Len(t, payments, 7)
+}
+
+func TestHistoryPerPage(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		_, _ = w.Write([]byte(`{"state":0,"result":{"items":[],"paginate":{}}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey)
+	client.SetBaseURL(server.URL + "/v1")
+
+	opts := &cryptomus.HistoryOptions{DateFrom: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), PerPage: 100}
+	_, err := client.ListAllPayouts(context.Background(), opts, nil)
+	require.NoError(t, err)
+	_, err = client.ListAllPayments(context.Background(), &cryptomus.HistoryOptions{}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{`{"date_from":"2024-05-01 00:00:00","per_page":100}`, `{}`}, bodies)
+}
+
+func TestDateWindows(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC)
+	windows := cryptomus.SplitDateRange(from, to, 24*time.Hour)
+	require.Equal(t, []cryptomus.DateWindow{
+		{From: from, To: from.Add(24*time.Hour - time.Second)},
+		{From: from.Add(24 * time.Hour), To: from.Add(48*time.Hour - time.Second)},
+		{From: from.Add(48 * time.Hour), To: to},
+	}, windows)
+	require.Len(t, cryptomus.SplitDateRange(time.Time{}, to, time.Hour), 1)
+
+	var bodies []string
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		calls++
+		// The second window has two pages, the others one
+		if calls == 2 {
+			_, _ = w.Write([]byte(`{"state":0,"result":{"items":[{"uuid":"p2"}],"paginate":{"nextCursor":"next"}}}`))
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"state":0,"result":{"items":[{"uuid":"p%d"}],"paginate":{}}}`, calls)
+	}))
+	t.Cleanup(server.Close)
+
+	client := cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey)
+	client.SetBaseURL(server.URL + "/v1")
+
+	payments, err := client.ListAllPayments(context.Background(), &cryptomus.HistoryOptions{DateFrom: from, DateTo: to, Window: 24 * time.Hour}, nil)
+	require.NoError(t, err)
+	require.Len(t, payments, 4)
+	require.Equal(t, cryptomus.UUID("p4"), payments[3].UUID)
+	require.Equal(t, []string{
+		`{"date_from":"2024-01-01 00:00:00","date_to":"2024-01-01 23:59:59"}`,
+		`{"date_from":"2024-01-02 00:00:00","date_to":"2024-01-02 23:59:59"}`,
+		`{"date_from":"2024-01-02 00:00:00","date_to":"2024-01-02 23:59:59"}`,
+		`{"date_from":"2024-01-03 00:00:00","date_to":"2024-01-03 12:00:00"}`,
+	}, bodies)
+}
+
+func TestIteratorResume(t *testing.T) {
+	client, cursors := newHistoryServer(t, 7, 3)
+	store := cryptomus.NewMemoryResumeStore()
+	ctx := context.Background()
+
+	// The first run crashes after processing the first page
+	it := client.PaymentHistory(ctx, nil)
+	require.NoError(t, it.ResumeFrom(ctx, store, "export"))
+	require.True(t, it.NextPage())
+	require.NoError(t, it.Checkpoint(ctx, store, "export"))
+
+	token, err := store.LoadResumeToken(ctx, "export")
+	require.NoError(t, err)
+	body, err := json.Marshal(token)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"window":{"from":"0001-01-01T00:00:00Z","to":"0001-01-01T00:00:00Z"},"cursor":"3"}`, string(body))
+
+	*cursors = nil
+	it = client.PaymentHistory(ctx, nil)
+	require.NoError(t, it.ResumeFrom(ctx, store, "export"))
+	var uuids []cryptomus.UUID
+	for it.NextPage() {
+		for _, payment := range it.Page().Items {
+			uuids = append(uuids, payment.UUID)
+		}
+		require.NoError(t, it.Checkpoint(ctx, store, "export"))
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []string{"3", "6"}, *cursors)
+	require.Len(t, uuids, 4)
+	require.Equal(t, cryptomus.UUID("item-3"), uuids[0])
+
+	_, err = store.LoadResumeToken(ctx, "export")
+	require.ErrorIs(t, err, cryptomus.ErrResumeTokenNotFound)
+
+	windowed := client.PaymentHistory(ctx, &cryptomus.HistoryOptions{
+		DateFrom: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		DateTo:   time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+		Window:   24 * time.Hour,
+	})
+	err = windowed.Resume(&cryptomus.ResumeToken{Window: cryptomus.DateWindow{From: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}})
+	require.Error(t, err)
+	require.NoError(t, windowed.Resume(&cryptomus.ResumeToken{Window: cryptomus.DateWindow{From: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)}}))
+	require.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), windowed.ResumeToken().Window.From)
+}
+
+func TestIteratorPrefetch(t *testing.T) {
+	requested := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cursor := r.URL.Query().Get("cursor")
+		requested <- cursor
+		next := map[string]string{"": "a", "a": "b"}[cursor]
+		_, _ = fmt.Fprintf(w, `{"state":0,"result":{"items":[{"uuid":"p-%s"}],"paginate":{"nextCursor":%q}}}`, cursor, next)
+	}))
+	t.Cleanup(server.Close)
+
+	client := cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey)
+	client.SetBaseURL(server.URL + "/v1")
+
+	it := client.PaymentHistory(context.Background(), nil).Prefetch()
+	require.True(t, it.NextPage())
+	require.Equal(t, "", <-requested)
+	// The next page is requested before the caller asks for it
+	select {
+	case cursor := <-requested:
+		require.Equal(t, "a", cursor)
+	case <-time.After(time.Second):
+		t.Fatal("next page not prefetched")
+	}
+
+	var uuids []cryptomus.UUID
+	for it.Next() {
+		uuids = append(uuids, it.Item().UUID)
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []cryptomus.UUID{"p-", "p-a", "p-b"}, uuids)
+	require.Equal(t, "b", <-requested)
+	require.Empty(t, requested)
+}
+
+func TestIteratorReverse(t *testing.T) {
+	client, cursors := newHistoryServer(t, 7, 3)
+
+	it := client.PaymentHistory(context.Background(), nil).Reverse()
+	require.NoError(t, it.Resume(&cryptomus.ResumeToken{Cursor: "6"}))
+	var uuids []cryptomus.UUID
+	for it.Next() {
+		uuids = append(uuids, it.Item().UUID)
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []string{"6", "3", "0"}, *cursors)
+	require.Len(t, uuids, 7)
+	require.Equal(t, cryptomus.UUID("item-6"), uuids[0])
+	require.Equal(t, cryptomus.UUID("item-0"), uuids[6])
+
+	forward := client.PaymentHistory(context.Background(), nil)
+	require.Error(t, forward.Resume(&cryptomus.ResumeToken{Cursor: "3", Reverse: true}))
+
+	windowed := client.PaymentHistory(context.Background(), &cryptomus.HistoryOptions{
+		DateFrom: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		DateTo:   time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+		Window:   24 * time.Hour,
+	}).Reverse()
+	token := windowed.ResumeToken()
+	require.True(t, token.Reverse)
+	require.Equal(t, time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), token.Window.From)
+}
+
+func TestIteratorStream(t *testing.T) {
+	client, cursors := newHistoryServer(t, 7, 3)
+
+	it := client.PaymentHistory(context.Background(), nil).Stream()
+	var uuids []cryptomus.UUID
+	for it.Next() {
+		uuids = append(uuids, it.Item().UUID)
+	}
+	require.NoError(t, it.Err())
+	require.Len(t, uuids, 7)
+	require.Equal(t, cryptomus.UUID("item-6"), uuids[6])
+	require.Equal(t, []string{"", "3", "6"}, *cursors)
+	require.Equal(t, "6", it.Page().Cursor)
+	require.Equal(t, "3", it.Page().PreviousCursor)
+	require.Nil(t, it.ResumeToken())
+
+	// The items are yielded before the rest of the page is sent
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"state":0,"result":{"merchant_uuid":"c26b80a8-9549-4b70-9f2c-3b8a9d6e1b5f","items":[{"uuid":"p1"},`))
+		w.(http.Flusher).Flush()
+		<-release
+		_, _ = w.Write([]byte(`{"uuid":"p2"}],"paginate":{"count":2,"hasPages":false,"perPage":15}}}`))
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() {
+		select {
+		case <-release:
+		default:
+			close(release)
+		}
+	})
+	client = cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey, cryptomus.WithStrictDecoding())
+	client.SetBaseURL(server.URL + "/v1")
+
+	payouts := client.PayoutHistory(context.Background(), nil).Stream()
+	require.True(t, payouts.Next())
+	require.Equal(t, cryptomus.UUID("p1"), payouts.Item().UUID)
+	close(release)
+	require.True(t, payouts.Next())
+	require.Equal(t, cryptomus.UUID("p2"), payouts.Item().UUID)
+	require.False(t, payouts.Next())
+	require.NoError(t, payouts.Err())
+}
+
+func TestIteratorStreamErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		body  string
+		check func(t *testing.T, err error)
+	}{
+		{"api error", `{"state":1,"message":"Insufficient funds on the balance"}`, func(t *testing.T, err error) {
+			require.ErrorIs(t, err, cryptomus.ErrInsufficientFunds)
+			var apiErr *cryptomus.APIError
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, `{"state":1,"message":"Insufficient funds on the balance"}`, string(apiErr.Body))
+		}},
+		{"malformed item", `{"state":0,"result":{"items":[{"uuid":"p1"},{"uuid":1}]}}`, func(t *testing.T, err error) {
+			var decodeErr *cryptomus.DecodeError
+			require.ErrorAs(t, err, &decodeErr)
+			require.Contains(t, string(decodeErr.Body), `{"uuid":1}`)
+		}},
+		{"truncated", `{"state":0,"result":{"items":[{"uuid":"p1"},{"uu`, func(t *testing.T, err error) {
+			var decodeErr *cryptomus.DecodeError
+			require.ErrorAs(t, err, &decodeErr)
+			require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newErrorServer(t, http.StatusOK, tc.body)
+
+			it := client.PaymentHistory(context.Background(), nil).Stream()
+			for it.Next() {
+				require.Equal(t, cryptomus.UUID("p1"), it.Item().UUID)
+			}
+			require.Error(t, it.Err())
+			tc.check(t, it.Err())
+		})
+	}
+
+	// The pagination may precede the items, and an empty result has no items
+	for body, want := range map[string]int{
+		`{"state":0,"result":{"paginate":{"count":1},"items":[{"uuid":"p1"}]}}`: 1,
+		`{"state":0,"result":{"paginate":{"count":0}}}`:                         0,
+		`{"state":0,"result":null}`:                                             0,
+	} {
+		it := newErrorServer(t, http.StatusOK, body).PaymentHistory(context.Background(), nil).Stream()
+		var count int
+		for it.Next() {
+			count++
+		}
+		require.NoError(t, it.Err(), body)
+		require.Equal(t, want, count, body)
+	}
+
+	client := newErrorServer(t, http.StatusOK, `{"state":0,"result":{"items":[{"uuid":"p1"},{"uuid":"p2"}]}}`)
+	it := client.PaymentHistory(context.Background(), nil).Stream()
+	require.True(t, it.Next())
+	require.NoError(t, it.Close())
+	require.False(t, it.Next())
+	require.NoError(t, it.Err())
+}
@@ -9,9 +9,9 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func createTestInvoice(t *testing.T) *cryptomus.Payment {
+func createTestInvoice(t *testing.T, client *cryptomus.Cryptomus) *cryptomus.Payment {
 	invoiceReq := &cryptomus.InvoiceRequest{
-		Amount:   "10",
+		Amount:   cryptomus.MustAmount("10"),
 		Currency: "USD",
 		OrderID:  "xxy",
 		InvoiceRequestOptions: &cryptomus.InvoiceRequestOptions{
@@ -19,7 +19,7 @@ func createTestInvoice(t *testing.T) *cryptomus.Payment {
 			UrlCallback: "https://example.com/cryptomus/callback",
 		},
 	}
-	invoice, err := TestCryptomus.CreateInvoice(invoiceReq)
+	invoice, err := client.CreateInvoice(invoiceReq)
 	require.NoError(t, err)
 	require.NotEmpty(t, invoice)
 
@@ -27,25 +27,28 @@ func createTestInvoice(t *testing.T) *cryptomus.Payment {
 }
 
 func TestCreateInvoice(t *testing.T) {
-	createTestInvoice(t)
+	createTestInvoice(t, recordedClient(t))
 }
 
 func TestGenerateInvoiceQRCode(t *testing.T) {
-	invoice := createTestInvoice(t)
-	qrCode, err := TestCryptomus.GeneratePaymentQRCode(invoice.UUID)
+	client := recordedClient(t)
+	invoice := createTestInvoice(t, client)
+	qrCode, err := client.GeneratePaymentQRCode(invoice.UUID)
 	require.NoError(t, err)
 	require.NotEmpty(t, qrCode)
 }
 
 func TestGetPaymentInfo(t *testing.T) {
-	invoice := createTestInvoice(t)
-	payment, err := TestCryptomus.GetPaymentInfo(&cryptomus.PaymentInfoRequest{PaymentUUID: invoice.UUID})
+	client := recordedClient(t)
+	invoice := createTestInvoice(t, client)
+	payment, err := client.GetPaymentInfo(&cryptomus.PaymentInfoRequest{PaymentUUID: invoice.UUID})
 	require.NoError(t, err)
 	require.NotEmpty(t, payment)
 }
 
 func TestGeyPaymentHistory(t *testing.T) {
-	payments, err := TestCryptomus.GetPaymentHistory(time.Now(), time.Now())
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	payments, err := recordedClient(t).GetPaymentHistory(day, day.Add(24*time.Hour-time.Second))
 	require.NoError(t, err)
 	require.NotEmpty(t, payments)
 }
@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/backtrac3r/go-cryptomus"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newSigningServer returns a client whose requests are checked to be signed with the expected key.
+func newSigningServer(t *testing.T, expectKey func(client *cryptomus.Cryptomus, path string, body []byte) (string, error)) *cryptomus.Cryptomus {
+	var client *cryptomus.Cryptomus
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		want, err := expectKey(client, r.URL.Path, body)
+		require.NoError(t, err)
+		require.Equal(t, want, r.Header.Get("sign"), r.URL.Path)
+		require.Equal(t, "merchant", r.Header.Get("merchant"))
+
+		_, _ = w.Write([]byte(`{"state":0,"result":{"uuid":"8b03432e-385b-4670-8d06-064591096795","status":"paid"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client = cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey)
+	client.SetBaseURL(server.URL + "/v1")
+
+	return client
+}
+
+func TestPayoutSignedWithPayoutKey(t *testing.T) {
+	client := newSigningServer(t, func(client *cryptomus.Cryptomus, path string, body []byte) (string, error) {
+		return client.SignWithPayoutKey(body)
+	})
+
+	payout, err := client.GetPayoutInfo(&cryptomus.PayoutInfoRequest{PayoutUUID: testUUID})
+	require.NoError(t, err)
+	require.Equal(t, cryptomus.UUID(testUUID), payout.UUID)
+}
+
+func TestCall(t *testing.T) {
+	client := newSigningServer(t, func(client *cryptomus.Cryptomus, path string, body []byte) (string, error) {
+		if path == "/v1/payout/transfer/to-personal" {
+			return client.SignWithPayoutKey(body)
+		}
+		return client.SignWithPaymentKey(body)
+	})
+
+	ctx := context.Background()
+	res, err := client.Call(ctx, http.MethodPost, "/payout/transfer/to-personal", map[string]string{"amount": "1"}, cryptomus.AuthPayout)
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	res, err = client.Call(ctx, http.MethodPost, "/payment/info", map[string]string{"uuid": testUUID}, cryptomus.AuthPayment)
+	require.NoError(t, err)
+	res.Body.Close()
+}
+
+func TestEmptyBodySigning(t *testing.T) {
+	for _, tc := range []struct {
+		mode cryptomus.EmptyBody
+		body string
+	}{
+		{cryptomus.EmptyBodyNone, ""},
+		{cryptomus.EmptyBodyObject, "{}"},
+		{cryptomus.EmptyBodyArray, "[]"},
+	} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			require.Equal(t, tc.body, string(body))
+
+			sign, err := cryptomus.Sign(testPaymentKey, []byte(tc.body))
+			require.NoError(t, err)
+			require.Equal(t, sign, r.Header.Get("sign"))
+		}))
+
+		client := cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey, cryptomus.WithEmptyBody(tc.mode))
+		client.SetBaseURL(server.URL + "/v1")
+
+		res, err := client.Call(context.Background(), http.MethodPost, "/balance", nil, cryptomus.AuthPayment)
+		require.NoError(t, err)
+		res.Body.Close()
+		server.Close()
+	}
+}
+
+func TestSignedQueryRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "currency=USDT&network=tron&page=2", r.URL.RawQuery)
+		require.Empty(t, r.Header.Get("Content-Type"))
+
+		sign, err := cryptomus.Sign(testPayoutKey, []byte(r.URL.RawQuery))
+		require.NoError(t, err)
+		require.Equal(t, sign, r.Header.Get("sign"))
+	}))
+	defer server.Close()
+
+	client := cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey)
+	client.SetBaseURL(server.URL + "/v1")
+
+	payload := struct {
+		Network  string  `json:"network"`
+		Currency string  `json:"currency"`
+		Page     int     `json:"page"`
+		Cursor   *string `json:"cursor"`
+	}{Network: "tron", Currency: "USDT", Page: 2}
+
+	res, err := client.Call(context.Background(), http.MethodGet, "/payout/services", payload, cryptomus.AuthPayout)
+	require.NoError(t, err)
+	res.Body.Close()
+}
+
+func TestSignInBody(t *testing.T) {
+	var received []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		received, err = io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, cryptomus.Verify(testPaymentKey, received))
+
+		fields := map[string]string{}
+		require.NoError(t, json.Unmarshal(received, &fields))
+		require.Equal(t, fields["sign"], r.Header.Get("sign"))
+	}))
+	defer server.Close()
+
+	client := cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey, cryptomus.WithSignInBody())
+	client.SetBaseURL(server.URL + "/v1")
+
+	res, err := client.Call(context.Background(), http.MethodPost, "/payment",
+		map[string]string{"amount": "1", "url": "https://example.com"}, cryptomus.AuthPayment)
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Contains(t, string(received), `,"sign":"`)
+
+	res, err = client.Call(context.Background(), http.MethodPost, "/payment", map[string]string{}, cryptomus.AuthPayment)
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Regexp(t, `^\{"sign":"[0-9a-f]{32}"\}$`, string(received))
+}
+
+func TestCredentialValidation(t *testing.T) {
+	_, err := cryptomus.NewWithOptions(nil, "", testPaymentKey, testPayoutKey)
+	require.ErrorIs(t, err, cryptomus.ErrMissingCredential)
+	_, err = cryptomus.NewWithOptions(nil, "merchant", "", "")
+	require.ErrorIs(t, err, cryptomus.ErrMissingCredential)
+
+	client, err := cryptomus.NewWithOptions(nil, "merchant", testPaymentKey, "")
+	require.NoError(t, err)
+	client.SetBaseURL("http://127.0.0.1:0/v1")
+
+	_, err = client.GetPayoutInfo(&cryptomus.PayoutInfoRequest{PayoutUUID: testUUID})
+	var credErr *cryptomus.CredentialError
+	require.ErrorAs(t, err, &credErr)
+	require.Equal(t, "payout API key", credErr.Credential)
+}
+
+func TestNilRequests(t *testing.T) {
+	client := cryptomus.New(nil, "merchant", testPaymentKey, testPayoutKey)
+	client.SetBaseURL("http://127.0.0.1:0/v1")
+
+	_, err := client.CreatePayout(nil)
+	require.EqualError(t, err, "payout request cannot be nil")
+	_, err = client.CreateStaticWallet(nil)
+	require.EqualError(t, err, "static wallet request cannot be nil")
+
+	payouts, err := client.CreatePayouts([]*cryptomus.PayoutRequest{nil})
+	require.ErrorContains(t, err, "item 0: payout request cannot be nil")
+	require.Equal(t, []*cryptomus.Payout{nil}, payouts)
+}
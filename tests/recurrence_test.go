@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/backtrac3r/go-cryptomus"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeRecurrencePlan(t *testing.T) {
+	var paths []string
+	var created map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/v1/recurrence/info":
+			_, _ = w.Write([]byte(`{"state":0,"result":{"uuid":"` + testUUID + `","name":"Pro","order_id":"sub-1",` +
+				`"amount":"10","currency":"USD","to_currency":"USDT","period":"monthly","status":"active",` +
+				`"url_callback":"https://example.com/callback","discount_days":7,"discount_amount":"2.5"}}`))
+		case "/v1/recurrence/cancel":
+			_, _ = w.Write([]byte(`{"state":0,"result":{"uuid":"` + testUUID + `","status":"cancel_by_merchant"}}`))
+		case "/v1/recurrence/create":
+			require.NoError(t, json.Unmarshal(body, &created))
+			_, _ = w.Write([]byte(`{"state":0,"result":{"uuid":"a3b3c3d3-385b-4670-8d06-064591096795","status":"wait_accept"}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey)
+	client.SetBaseURL(server.URL + "/v1")
+
+	amount := cryptomus.MustAmount("20")
+	change, err := client.ChangeRecurrencePlan(&cryptomus.RecurrencePlanChangeRequest{OrderID: "sub-1", Amount: &amount})
+	require.NoError(t, err)
+	require.Equal(t, cryptomus.RecurrenceStatusCancelByMerchant, change.Old.Status)
+	require.Equal(t, cryptomus.RecurrenceStatusWaitAccept, change.New.Status)
+	require.Equal(t, []string{"/v1/recurrence/info", "/v1/recurrence/cancel", "/v1/recurrence/create"}, paths)
+
+	// The discount and the target currency are carried over
+	require.Equal(t, "20", created["amount"])
+	require.Equal(t, "monthly", created["period"])
+	require.Equal(t, "USDT", created["to_currency"])
+	require.Equal(t, float64(7), created["discount_days"])
+	require.Equal(t, "2.5", created["discount_amount"])
+	require.Equal(t, "sub-1", created["order_id"])
+
+	// An invalid replacement is rejected before the current plan is canceled
+	for _, changeReq := range []*cryptomus.RecurrencePlanChangeRequest{
+		{OrderID: "sub-1", Period: "daily"},
+		{OrderID: "sub-1", Amount: cryptomus.Ptr(cryptomus.MustAmount("20.001"))},
+	} {
+		paths = nil
+		_, err = client.ChangeRecurrencePlan(changeReq)
+		var validationErrs cryptomus.ValidationErrors
+		require.ErrorAs(t, err, &validationErrs)
+		require.ErrorContains(t, err, "invalid replacement recurrence")
+		require.Equal(t, []string{"/v1/recurrence/info"}, paths)
+	}
+}
+
+func TestListRecurrencesFilters(t *testing.T) {
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		payload = nil
+		require.NoError(t, json.Unmarshal(body, &payload))
+		_, _ = w.Write([]byte(`{"state":0,"result":{"items":[` +
+			`{"uuid":"` + testUUID + `","order_id":"sub-1","currency":"USD","status":"active"},` +
+			`{"uuid":"` + testUUID + `","order_id":"sub-2","currency":"EUR","status":"active"},` +
+			`{"uuid":"` + testUUID + `","order_id":"sub-3","currency":"usd","status":"cancel_by_user"}],` +
+			`"paginate":{"count":3,"hasPages":true,"nextCursor":"next","perPage":3}}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey)
+	client.SetBaseURL(server.URL + "/v1")
+
+	orderIDs := func(list *cryptomus.RecurrenceListResponse) []string {
+		var ids []string
+		for _, item := range list.Items {
+			ids = append(ids, item.OrderID)
+		}
+		return ids
+	}
+
+	list, err := client.ListRecurrences(nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"sub-1", "sub-2", "sub-3"}, orderIDs(list))
+	require.Empty(t, payload)
+
+	// The status and currency are filtered client-side, the cursor and page size are sent
+	list, err = client.ListRecurrences(&cryptomus.RecurrenceListOptions{Cursor: "abc", PerPage: 3, Currency: "USD"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"sub-1", "sub-3"}, orderIDs(list))
+	require.Equal(t, map[string]interface{}{"cursor": "abc", "per_page": float64(3)}, payload)
+	require.Equal(t, "next", list.Paginate.NextCursor)
+
+	list, err = client.ListRecurrences(&cryptomus.RecurrenceListOptions{Status: cryptomus.RecurrenceStatusActive, Currency: "usd"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"sub-1"}, orderIDs(list))
+
+	// A page left empty by the filters still points to the next one
+	list, err = client.ListRecurrences(&cryptomus.RecurrenceListOptions{Status: cryptomus.RecurrenceStatusCancelByMerchant})
+	require.NoError(t, err)
+	require.Empty(t, list.Items)
+	require.Equal(t, "next", list.Paginate.NextCursor)
+}
@@ -0,0 +1,68 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/backtrac3r/go-cryptomus"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSandbox(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		require.Equal(t, "1", r.Header.Get(cryptomus.SandboxHeader))
+		_, _ = w.Write([]byte(`{"state":0,"result":{"uuid":"8b03432e-385b-4670-8d06-064591096795","status":"process"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey,
+		cryptomus.WithSandbox(&cryptomus.SandboxOptions{BaseURL: server.URL + "/v1", MaxAmounts: map[cryptomus.CurrencyCode]cryptomus.Amount{
+			cryptomus.CurrencyUSDT: cryptomus.MustAmount("20"),
+			"btc":                  cryptomus.MustAmount("0.001"),
+		}}))
+	require.True(t, client.IsSandbox())
+
+	_, err := client.CreatePayout(validPayout("p1"))
+	require.NoError(t, err)
+
+	payout := validPayout("p2")
+	payout.Amount = cryptomus.MustAmount("25")
+	_, err = client.CreatePayout(payout)
+	require.ErrorIs(t, err, cryptomus.ErrSandboxLimit)
+	require.EqualError(t, err, "cryptomus: POST /v1/payout [order_id=p2]: refused by sandbox: payout of 25 USDT is above the limit of 20")
+
+	// The limits are per currency, and currencies without one are refused
+	payout = validPayout("p3")
+	payout.Amount, payout.Currency, payout.Network = cryptomus.MustAmount("9.99"), "BTC", cryptomus.NetworkBitcoin
+	_, err = client.CreatePayout(payout)
+	require.ErrorIs(t, err, cryptomus.ErrSandboxLimit)
+	require.ErrorContains(t, err, "payout of 9.99 BTC is above the limit of 0.001")
+	payout.Amount, payout.Currency, payout.Network = cryptomus.MustAmount("1"), "ETH", cryptomus.NetworkEthereum
+	_, err = client.CreatePayout(payout)
+	require.ErrorIs(t, err, cryptomus.ErrSandboxLimit)
+	require.ErrorContains(t, err, `payouts in "ETH" have no limit`)
+
+	// Requests sent with Call are guarded too
+	_, err = client.Call(context.Background(), http.MethodPost, "payout/", map[string]string{"amount": "100", "currency": "USDT"}, cryptomus.AuthPayout)
+	require.ErrorIs(t, err, cryptomus.ErrSandboxLimit)
+
+	_, err = client.Refund(&cryptomus.RefundRequest{Address: "addr", PaymentUUID: testUUID})
+	require.ErrorIs(t, err, cryptomus.ErrSandboxLimit)
+	require.Equal(t, 1, requests)
+
+	t.Setenv(cryptomus.SandboxBaseURLEnv, server.URL+"/v1")
+	client = cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey, cryptomus.WithSandbox(nil))
+	_, err = client.CreatePayout(validPayout("p4"))
+	require.NoError(t, err)
+	require.Equal(t, 2, requests)
+	payout = validPayout("p5")
+	payout.Amount, payout.Currency, payout.Network = cryptomus.MustAmount("9.99"), "BTC", cryptomus.NetworkBitcoin
+	_, err = client.CreatePayout(payout)
+	require.ErrorIs(t, err, cryptomus.ErrSandboxLimit)
+	require.False(t, cryptomus.New(nil, "merchant", testPaymentKey, testPayoutKey).IsSandbox())
+}
@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/backtrac3r/go-cryptomus"
+	"github.com/backtrac3r/go-cryptomus/cryptomustest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServicesCache(t *testing.T) {
+	var paymentCalls, payoutCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/payment/services":
+			paymentCalls.Add(1)
+		case "/v1/payout/services":
+			payoutCalls.Add(1)
+		}
+		_, _ = w.Write([]byte(`{"state":0,"result":[{"network":"tron","currency":"USDT","isAvailable":true,` +
+			`"limit":{"minAmount":"1.00000000","maxAmount":"10000.00000000"},"commision":{"feeAmount":"0.00","percent":"1.00"}}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey)
+	client.SetBaseURL(server.URL + "/v1")
+	clock := cryptomustest.NewClock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	cache := cryptomus.NewServicesCache(client, &cryptomus.ServicesCacheOptions{TTL: time.Minute, Clock: clock})
+
+	services, err := cache.GetPaymentServicesList()
+	require.NoError(t, err)
+	require.Len(t, services, 1)
+	require.Equal(t, "1", services[0].Limit.MinAmount.String())
+
+	// The lists returned are copies
+	services[0].IsAvailable = false
+	services[0].Limit.MinAmount = cryptomus.MustAmount("5")
+
+	clock.Advance(59 * time.Second)
+	services, err = cache.GetPaymentServicesList()
+	require.NoError(t, err)
+	require.True(t, services[0].IsAvailable)
+	require.Equal(t, "1", services[0].Limit.MinAmount.String())
+	require.Equal(t, int32(1), paymentCalls.Load())
+
+	// The payout list is cached separately
+	_, err = cache.GetPayoutServicesList()
+	require.NoError(t, err)
+	_, err = cache.GetPayoutServicesList()
+	require.NoError(t, err)
+	require.Equal(t, int32(1), payoutCalls.Load())
+
+	clock.Advance(time.Second)
+	_, err = cache.GetPaymentServicesList()
+	require.NoError(t, err)
+	require.Equal(t, int32(2), paymentCalls.Load())
+
+	cache.Invalidate()
+	_, err = cache.GetPaymentServicesList()
+	require.NoError(t, err)
+	_, err = cache.GetPayoutServicesList()
+	require.NoError(t, err)
+	require.Equal(t, int32(3), paymentCalls.Load())
+	require.Equal(t, int32(2), payoutCalls.Load())
+}
+
+func TestServicesCacheConcurrentMisses(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		_, _ = w.Write([]byte(`{"state":0,"result":[{"network":"tron","currency":"USDT","isAvailable":true}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey)
+	client.SetBaseURL(server.URL + "/v1")
+	cache := cryptomus.NewServicesCache(client, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			services, err := cache.GetPayoutServicesList()
+			require.NoError(t, err)
+			require.Len(t, services, 1)
+		}()
+	}
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	require.Equal(t, int32(1), calls.Load())
+
+	// Failures are not cached
+	server.Close()
+	cache.Invalidate()
+	_, err := cache.GetPayoutServicesList()
+	require.Error(t, err)
+}
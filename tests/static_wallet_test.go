@@ -18,7 +18,7 @@ func TestCreateStaticWallet(t *testing.T) {
 		},
 	}
 
-	staticWallet, err := TestCryptomus.CreateStaticWallet(staticWalletReq)
+	staticWallet, err := recordedClient(t).CreateStaticWallet(staticWalletReq)
 	require.NoError(t, err)
 	require.NotEmpty(t, staticWallet)
 }
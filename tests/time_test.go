@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/backtrac3r/go-cryptomus"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCryptomusTimeFields(t *testing.T) {
+	payment := &cryptomus.Payment{}
+	err := json.Unmarshal([]byte(`{
+		"expired_at": 1679567380,
+		"created_at": "2023-03-23 09:43:00+03:00",
+		"updated_at": ""
+	}`), payment)
+	require.NoError(t, err)
+	require.Equal(t, int64(1679567380), payment.ExpiredAt.Unix())
+	require.Equal(t, time.Date(2023, 3, 23, 6, 43, 0, 0, time.UTC), payment.CreatedAt.UTC())
+	require.True(t, payment.UpdatedAt.IsZero())
+
+	data, err := json.Marshal(payment.CreatedAt)
+	require.NoError(t, err)
+	require.Equal(t, `"2023-03-23 09:43:00+03:00"`, string(data))
+	data, err = json.Marshal(payment.UpdatedAt)
+	require.NoError(t, err)
+	require.Equal(t, `null`, string(data))
+
+	recurrence := &cryptomus.Recurrence{}
+	require.NoError(t, json.Unmarshal([]byte(`{"last_pay_off":"2024-01-02 10:00:00+03:00","end_of_discount":null}`), recurrence))
+	require.Equal(t, 2024, recurrence.LastPayOff.Year())
+	require.True(t, recurrence.EndOfDiscount == nil || recurrence.EndOfDiscount.IsZero())
+}
+
+func TestHistoryDates(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		_, _ = w.Write([]byte(`{"state":0,"result":{"items":[]}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey)
+	client.SetBaseURL(server.URL + "/v1")
+
+	_, err := client.GetPaymentHistory(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), time.Time{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"date_from":"2024-05-01 00:00:00"}`, body)
+}
+
+func TestInvoiceLifetime(t *testing.T) {
+	req := &cryptomus.InvoiceRequest{
+		Amount:   cryptomus.MustAmount("10"),
+		Currency: cryptomus.CurrencyUSDT,
+		OrderID:  "o1",
+		InvoiceRequestOptions: &cryptomus.InvoiceRequestOptions{
+			UrlReturn: "https://example.com/return?a=1&b=2",
+			Lifetime:  30 * time.Minute,
+		},
+	}
+
+	data, err := cryptomus.MarshalCanonical(req)
+	require.NoError(t, err)
+	require.Equal(t, `{"amount":"10","currency":"USDT","order_id":"o1","url_return":"https:\/\/example.com\/return?a=1&b=2","lifetime":1800}`, string(data))
+
+	decoded := &cryptomus.InvoiceRequest{}
+	require.NoError(t, json.Unmarshal(data, decoded))
+	require.Equal(t, 30*time.Minute, decoded.Lifetime)
+	require.Equal(t, "https://example.com/return?a=1&b=2", decoded.UrlReturn)
+
+	data, err = json.Marshal(&cryptomus.InvoiceRequest{OrderID: "o1"})
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "lifetime")
+}
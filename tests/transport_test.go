@@ -0,0 +1,51 @@
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/backtrac3r/go-cryptomus"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransportDefaults(t *testing.T) {
+	transport := cryptomus.NewTransport(nil)
+	require.Equal(t, cryptomus.DefaultMaxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+	require.Equal(t, cryptomus.DefaultMaxIdleConns, transport.MaxIdleConns)
+	require.Equal(t, cryptomus.DefaultResponseHeaderTimeout, transport.ResponseHeaderTimeout)
+	require.Equal(t, uint16(tls.VersionTLS12), transport.TLSClientConfig.MinVersion)
+	require.NotNil(t, transport.Proxy)
+
+	client := cryptomus.NewHTTPClient(&cryptomus.TransportOptions{MaxIdleConnsPerHost: 64, MaxConnsPerHost: 128})
+	require.Equal(t, cryptomus.DefaultRequestTimeout, client.Timeout)
+	transport = client.Transport.(*http.Transport)
+	require.Equal(t, 64, transport.MaxIdleConnsPerHost)
+	require.Equal(t, 128, transport.MaxConnsPerHost)
+
+	require.Zero(t, cryptomus.NewHTTPClient(&cryptomus.TransportOptions{RequestTimeout: -1}).Timeout)
+}
+
+func TestTransportOptions(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		_, _ = w.Write([]byte(`{"state":0,"result":{"uuid":"8b03432e-385b-4670-8d06-064591096795","status":"process"}}`))
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	// The client built for a nil *http.Client applies the options
+	client := cryptomus.New(nil, "merchant", testPaymentKey, testPayoutKey,
+		cryptomus.WithTransportOptions(&cryptomus.TransportOptions{ResponseHeaderTimeout: 20 * time.Millisecond}))
+	client.SetBaseURL(server.URL + "/v1")
+
+	_, err := client.CreatePayout(validPayout("p1"))
+	var transportErr *cryptomus.TransportError
+	require.ErrorAs(t, err, &transportErr)
+	require.Contains(t, err.Error(), "timeout awaiting response headers")
+	require.True(t, cryptomus.IsRetryable(err))
+}
@@ -0,0 +1,122 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/backtrac3r/go-cryptomus"
+	"github.com/stretchr/testify/require"
+)
+
+func validInvoice(orderID string) *cryptomus.InvoiceRequest {
+	return &cryptomus.InvoiceRequest{Amount: cryptomus.MustAmount("10"), Currency: cryptomus.CurrencyUSDT, OrderID: orderID}
+}
+
+func validPayout(orderID string) *cryptomus.PayoutRequest {
+	return &cryptomus.PayoutRequest{
+		Amount:   cryptomus.MustAmount("10"),
+		Currency: cryptomus.CurrencyUSDT,
+		OrderID:  orderID,
+		Address:  "TDD97yguPESTpcrJMqU6h2ozZbibv4Vaqm",
+		Network:  cryptomus.NetworkTron,
+	}
+}
+
+func validStaticWallet(orderID string) *cryptomus.StaticWalletRequest {
+	return &cryptomus.StaticWalletRequest{Currency: cryptomus.CurrencyUSDT, Network: cryptomus.NetworkTron, OrderID: orderID}
+}
+
+func TestValidateRequest(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`{"state":0,"result":{"uuid":"8b03432e-385b-4670-8d06-064591096795"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := cryptomus.New(server.Client(), "merchant", testPaymentKey, testPayoutKey)
+	client.SetBaseURL(server.URL + "/v1")
+
+	_, err := client.CreateInvoice(&cryptomus.InvoiceRequest{
+		Amount:  cryptomus.MustAmount("-1"),
+		OrderID: strings.Repeat("x", 129),
+		InvoiceRequestOptions: &cryptomus.InvoiceRequestOptions{
+			UrlCallback: "example.com/callback",
+			Lifetime:    time.Minute,
+		},
+	})
+	var validation cryptomus.ValidationErrors
+	require.ErrorAs(t, err, &validation)
+	require.Equal(t, []string{"amount", "currency", "lifetime", "order_id", "url_callback"}, validation.Fields())
+	require.Equal(t, []string{"The amount must be greater than zero."}, validation.Field("amount"))
+	require.Equal(t, []string{"The currency field is required."}, validation.Field("currency"))
+	require.Equal(t, []string{"The lifetime must be at least 300."}, validation.Field("lifetime"))
+	require.Equal(t, []string{"The order id may not be greater than 128 characters."}, validation.Field("order_id"))
+	require.Equal(t, []string{"The url callback must be a valid URL."}, validation.Field("url_callback"))
+	require.Contains(t, err.Error(), "cryptomus: POST /v1/payment: validation errors: ")
+
+	_, err = client.CreateRecurrence(&cryptomus.RecurrenceRequest{
+		Amount: cryptomus.MustAmount("10"), Currency: cryptomus.CurrencyUSDT, Name: "Plan", Period: "daily",
+	})
+	require.ErrorAs(t, err, &validation)
+	require.Equal(t, []string{"The selected period is invalid."}, validation.Field("period"))
+
+	_, err = client.Refund(&cryptomus.RefundRequest{PaymentUUID: testUUID})
+	require.ErrorAs(t, err, &validation)
+	require.True(t, validation.Has("address"))
+	require.Zero(t, calls)
+
+	_, err = client.CreateInvoice(validInvoice("o1"))
+	require.NoError(t, err)
+	require.Equal(t, 1, calls)
+}
+
+func TestUUID(t *testing.T) {
+	id, err := cryptomus.ParseUUID(" 8B03432E-385B-4670-8D06-064591096795 ")
+	require.NoError(t, err)
+	require.Equal(t, cryptomus.UUID(testUUID), id)
+	require.True(t, id.IsValid())
+	require.False(t, cryptomus.UUID("8b03432e385b46708d06064591096795").IsValid())
+	_, err = cryptomus.ParseUUID("p1")
+	require.Error(t, err)
+
+	client := cryptomus.New(nil, "merchant", testPaymentKey, testPayoutKey)
+	_, err = client.GetPaymentInfo(&cryptomus.PaymentInfoRequest{PaymentUUID: "p1"})
+	var validation cryptomus.ValidationErrors
+	require.ErrorAs(t, err, &validation)
+	require.Equal(t, []string{"The uuid must be a valid UUID."}, validation.Field("uuid"))
+
+	_, err = client.CancelRecurrence(&cryptomus.RecurrenceCancelRequest{})
+	require.EqualError(t, err, "either uuid or order_id must be provided")
+
+	_, err = client.GenerateStaticWalletQRCode("not-a-uuid")
+	require.ErrorAs(t, err, &validation)
+	require.True(t, validation.Has("wallet_address_uuid"))
+
+	_, err = client.TestPaymentWebhook(&cryptomus.TestWebhookRequest{
+		UrlCallback: "https://example.com/callback", Currency: "USDT", Network: "tron", Status: "paid", UUID: "p1",
+	})
+	require.ErrorAs(t, err, &validation)
+}
+
+func TestOptionalZeroValues(t *testing.T) {
+	data, err := json.Marshal(&cryptomus.InvoiceRequest{
+		Amount:   cryptomus.MustAmount("10"),
+		Currency: cryptomus.CurrencyUSDT,
+		OrderID:  "o1",
+		InvoiceRequestOptions: &cryptomus.InvoiceRequestOptions{
+			IsPaymentMultiple: cryptomus.Ptr(false),
+			DiscountPercent:   cryptomus.Ptr[int8](0),
+		},
+	})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"amount":"10","currency":"USDT","order_id":"o1","is_payment_multiple":false,"discount_percent":0}`, string(data))
+
+	data, err = json.Marshal(&cryptomus.RecurrenceRequest{Name: "Plan"})
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "discount_days")
+}
@@ -0,0 +1,56 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/backtrac3r/go-cryptomus"
+	"github.com/backtrac3r/go-cryptomus/cryptomusvcr"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"state":0,"result":{"uuid":"8b03432e-385b-4670-8d06-064591096795","order_id":"p1","address":"TDD97yguPESTpcrJMqU6h2ozZbibv4Vaqm","status":"process"}}`))
+	}))
+	t.Cleanup(server.Close)
+	path := filepath.Join(t.TempDir(), "payout.json")
+
+	rec, err := cryptomusvcr.New(path, cryptomusvcr.ModeRecord)
+	require.NoError(t, err)
+	rec.RedactFields = []string{"address"}
+	client := cryptomus.New(rec.Client(), "merchant", testPaymentKey, testPayoutKey)
+	client.SetBaseURL(server.URL + "/v1")
+	payout, err := client.CreatePayout(validPayout("p1"))
+	require.NoError(t, err)
+	require.Equal(t, "TDD97yguPESTpcrJMqU6h2ozZbibv4Vaqm", payout.Address)
+	require.NoError(t, rec.Stop())
+
+	fixture, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NotContains(t, string(fixture), testPayoutKey)
+	require.NotContains(t, string(fixture), "TDD97yguPESTpcrJMqU6h2ozZbibv4Vaqm")
+	require.Contains(t, string(fixture), cryptomusvcr.Redacted)
+
+	// Replaying needs neither the server nor the same keys
+	server.Close()
+	rec, err = cryptomusvcr.New(path, cryptomusvcr.ModeReplay)
+	require.NoError(t, err)
+	rec.RedactFields = []string{"address"}
+	client = cryptomus.New(rec.Client(), "other", "other-payment-key", "other-payout-key")
+	client.SetBaseURL(server.URL + "/v1")
+	payout, err = client.CreatePayout(validPayout("p1"))
+	require.NoError(t, err)
+	require.Equal(t, cryptomus.PayoutStatusProcess, payout.Status)
+	require.Equal(t, cryptomusvcr.Redacted, payout.Address)
+
+	// Every interaction is replayed once
+	_, err = client.CreatePayout(validPayout("p1"))
+	require.ErrorIs(t, err, cryptomusvcr.ErrNoInteraction)
+	_, err = client.CreatePayout(validPayout("p2"))
+	require.ErrorIs(t, err, cryptomusvcr.ErrNoInteraction)
+}
@@ -0,0 +1,367 @@
+package tests
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/backtrac3r/go-cryptomus"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+const paymentWebhookPayload = `{"type":"payment","uuid":"62f88b36-a9d5-4fa6-aa26-e040c3dbf26d","order_id":"97a75bf8eda5cca41ba9d2e104840fcd",` +
+	`"amount":"3.00000000","payment_amount":"3.00000000","payment_amount_usd":"0.23","merchant_amount":"2.94000000",` +
+	`"commission":"0.06000000","is_final":true,"status":"paid","from":"THgEWubVc8tPKXLJ4VZ5zbiiAK7AgqSeGH",` +
+	`"wallet_address_uuid":null,"network":"tron","currency":"TRX","payer_currency":"TRX","additional_data":null,` +
+	`"convert":{"to_currency":"USDT","commission":null,"rate":"0.07700000","amount":"0.22638000"},` +
+	`"txid":"6f0d9c8374db57cac0d806251473de754f361c83a03cd805f74aa9da3193486b",` +
+	`"created_at":"2023-03-23 09:43:00+03:00","sign":"a76c0d77f3e8e1a419b138af04ab600a"}`
+
+func TestPaymentWebhookDecode(t *testing.T) {
+	webhook := &cryptomus.PaymentWebhook{}
+	require.NoError(t, json.Unmarshal([]byte(paymentWebhookPayload), webhook))
+
+	require.Equal(t, cryptomus.PaymentStatusPaid, webhook.Status)
+	require.True(t, webhook.IsFinal)
+	require.Equal(t, "USDT", webhook.Convert.ToCurrency)
+	require.True(t, webhook.Convert.Commission.IsZero())
+	require.Equal(t, "0.077", webhook.Convert.Rate.String())
+	require.Equal(t, "0.22638", webhook.Convert.Amount.String())
+	require.Equal(t, 2023, webhook.CreatedAt.Year())
+	require.Equal(t, 6, webhook.CreatedAt.UTC().Hour())
+	require.Nil(t, webhook.UpdatedAt)
+}
+
+func TestWebhookConvertEmptyValues(t *testing.T) {
+	payload := strings.Replace(paymentWebhookPayload, `"commission":null,"rate":"0.07700000"`, `"commission":"","rate":"0.07700000"`, 1)
+
+	webhook := &cryptomus.PaymentWebhook{}
+	require.NoError(t, json.Unmarshal([]byte(payload), webhook))
+	require.True(t, webhook.Convert.Commission.IsZero())
+	require.Equal(t, "0.077", webhook.Convert.Rate.String())
+
+	legacy := &cryptomus.Webhook{}
+	require.NoError(t, json.Unmarshal([]byte(payload), legacy))
+	require.True(t, legacy.Convert.Commission.IsZero())
+	require.Equal(t, "0.22638", legacy.Convert.Amount.String())
+}
+
+const payoutWebhookPayload = `{"type":"payout","uuid":"9a1b7ad3-3a5c-4a8e-8b3e-0d3a9f8b2e1c","order_id":"payout-42",` +
+	`"amount":"10.00000000","merchant_amount":"10.50000000","commission":"0.50000000","is_final":true,"status":"paid",` +
+	`"txid":"2bcd6f7e9a0c","currency":"USDT","network":"tron","payer_currency":"USDT","payer_amount":"10.00000000",` +
+	`"sign":"6a1c0e3b5c5e0b2f0e6c7f2b8d3a1e4c"}`
+
+func TestPayoutWebhookDecode(t *testing.T) {
+	webhook := &cryptomus.PayoutWebhook{}
+	require.NoError(t, json.Unmarshal([]byte(payoutWebhookPayload), webhook))
+
+	require.Equal(t, "payout-42", webhook.OrderID)
+	require.Equal(t, "0.5", webhook.Commission.String())
+	require.Equal(t, "2bcd6f7e9a0c", webhook.TxId)
+}
+
+const walletWebhookPayload = `{"type":"wallet","uuid":"c1d2e3f4-1111-2222-3333-444455556666","order_id":"user-1001",` +
+	`"wallet_address_uuid":"0b7d5a2c-7777-8888-9999-aaaabbbbcccc","from":"TXYZ","amount":"25.00000000",` +
+	`"payment_amount":"25.00000000","payment_amount_usd":"25.00","merchant_amount":"24.50000000","commission":"0.50000000",` +
+	`"is_final":true,"status":"paid","network":"tron","currency":"USDT","payer_currency":"USDT","additional_data":null,` +
+	`"convert":null,"txid":"ab12","sign":"0c4f9e1d2b3a4c5d6e7f8091a2b3c4d5"}`
+
+func TestWalletWebhookDecode(t *testing.T) {
+	webhook := &cryptomus.WalletWebhook{}
+	require.NoError(t, json.Unmarshal([]byte(walletWebhookPayload), webhook))
+
+	require.Equal(t, "user-1001", webhook.OrderID)
+	require.Equal(t, cryptomus.UUID("0b7d5a2c-7777-8888-9999-aaaabbbbcccc"), webhook.WalletAddressUUID)
+	require.Nil(t, webhook.Convert)
+}
+
+const recurrenceWebhookPayload = `{"type":"recurrence","uuid":"e1f2a3b4-0000-1111-2222-333344445555","order_id":"sub-7",` +
+	`"name":"Pro plan","amount":"15","currency":"USD","payer_currency":"USDT","payer_amount":"15.00000000",` +
+	`"payer_amount_usd":"15.00","period":"monthly","status":"paid","is_final":true,` +
+	`"last_pay_off":"2024-01-31 12:00:00+00:00","additional_data":null,"sign":"f0e1d2c3b4a5968778695a4b3c2d1e0f"}`
+
+func TestRecurrenceWebhookDecode(t *testing.T) {
+	webhook := &cryptomus.RecurrenceWebhook{}
+	require.NoError(t, json.Unmarshal([]byte(recurrenceWebhookPayload), webhook))
+
+	require.Equal(t, "monthly", webhook.Period)
+	next, ok := webhook.NextChargeAt()
+	require.True(t, ok)
+	require.Equal(t, "2024-03-02", next.Format("2006-01-02"))
+}
+
+func TestParseWebhookEvent(t *testing.T) {
+	tests := []struct {
+		payload string
+		typ     cryptomus.WebhookType
+		orderID string
+	}{
+		{paymentWebhookPayload, cryptomus.WebhookTypePayment, "97a75bf8eda5cca41ba9d2e104840fcd"},
+		{payoutWebhookPayload, cryptomus.WebhookTypePayout, "payout-42"},
+		{walletWebhookPayload, cryptomus.WebhookTypeWallet, "user-1001"},
+		{recurrenceWebhookPayload, cryptomus.WebhookTypeRecurrence, "sub-7"},
+	}
+
+	for _, tt := range tests {
+		event, err := cryptomus.ParseWebhook([]byte(tt.payload))
+		require.NoError(t, err)
+		require.Equal(t, tt.typ, event.Type)
+		require.Equal(t, tt.orderID, event.OrderID())
+		require.Equal(t, "paid", event.Status())
+	}
+
+	event, err := cryptomus.ParseWebhook([]byte(`{"type":"transfer","uuid":"x"}`))
+	require.ErrorIs(t, err, cryptomus.ErrUnknownWebhookType)
+	require.Equal(t, cryptomus.WebhookType("transfer"), event.Type)
+}
+
+// phpSignedPayload builds a payload the way the PHP reference implementation signs it:
+// the signature covers the exact bytes without the sign field, including escaped slashes.
+// It returns the payload with the sign field appended and with the sign field first.
+func phpSignedPayload(key string) (last, first []byte) {
+	fields := `"type":"payment","uuid":"u-1","order_id":"1","amount":"1.50","status":"paid","url":"https:\/\/example.com\/cb","name":"Café"`
+	hash := md5.Sum([]byte(base64.StdEncoding.EncodeToString([]byte("{"+fields+"}")) + key))
+	sign := `"sign":"` + hex.EncodeToString(hash[:]) + `"`
+
+	return []byte("{" + fields + "," + sign + "}"), []byte("{" + sign + "," + fields + "}")
+}
+
+func TestVerifyRaw(t *testing.T) {
+	client := newTestClient()
+	payload, signFirst := phpSignedPayload(testPaymentKey)
+
+	require.NoError(t, client.VerifyRaw(payload))
+	require.NoError(t, client.VerifySign(testPaymentKey, payload), "the escaping must be preserved")
+	require.Error(t, cryptomus.New(nil, "merchant", "other-key", testPayoutKey).VerifyRaw(payload))
+
+	req := httptest.NewRequest(http.MethodPost, "/callback", bytes.NewReader(payload))
+	body, err := client.VerifyWebhookRequest(req)
+	require.NoError(t, err)
+	require.Equal(t, payload, body)
+
+	// The sign field may come first as well.
+	require.NoError(t, client.VerifyRaw(signFirst))
+
+	// Signed payloads produced by re-encoding are verified as well.
+	require.NoError(t, client.VerifyRaw(signPayload(t, testPaymentKey, paymentWebhookPayload)))
+}
+
+func TestTestWalletWebhook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/test-webhook/wallet", r.URL.Path)
+		request := &cryptomus.TestWebhookRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(request))
+		require.Equal(t, "USDT", request.Currency)
+		require.Equal(t, "tron", request.Network)
+		require.Equal(t, "paid", request.Status)
+		_, _ = w.Write([]byte(`{"state":0,"result":[]}`))
+	}))
+	defer server.Close()
+
+	client := cryptomus.New(http.DefaultClient, "merchant", "payment-key", "payout-key")
+	client.SetBaseURL(server.URL + "/v1")
+
+	res, err := client.TestWalletWebhook(&cryptomus.TestWebhookRequest{
+		UrlCallback: "https://example.com/callback",
+		Currency:    "USDT",
+		Network:     "tron",
+		Status:      "paid",
+	})
+	require.NoError(t, err)
+	require.Equal(t, int8(0), res.State)
+}
+
+func TestParseWebhookModes(t *testing.T) {
+	event, err := cryptomus.ParseWebhook([]byte(paymentWebhookPayload), cryptomus.WithParseMode(cryptomus.ParseStrict))
+	require.NoError(t, err)
+	require.Nil(t, event.Extra)
+
+	extended := []byte(`{"type":"payment","uuid":"u1","order_id":"o1","amount":"1","currency":"USDT","status":"paid",` +
+		`"is_final":true,"risk_score":7}`)
+
+	event, err = cryptomus.ParseWebhook(extended)
+	require.NoError(t, err)
+	require.Equal(t, cryptomus.PaymentStatusPaid, event.Payment.Status)
+	require.JSONEq(t, `7`, string(event.Extra["risk_score"]))
+
+	_, err = cryptomus.ParseWebhook(extended, cryptomus.WithParseMode(cryptomus.ParseStrict))
+	require.ErrorContains(t, err, "risk_score")
+
+	_, err = cryptomus.ParseWebhook([]byte(`{"type":"payout","uuid":"u1","status":"paid"}`),
+		cryptomus.WithParseMode(cryptomus.ParseStrict))
+	require.ErrorContains(t, err, "missing required fields: order_id, amount, currency, is_final")
+}
+
+func TestParseWebhookNormalizes(t *testing.T) {
+	raw := []byte(`{"type":"payout","uuid":"u1","order_id":"o1","amount":"12.50","commission":"","balance":"100.1",` +
+		`"currency":"USDT","status":"paid","is_final":true,"created_at":"2024-01-02 10:00:00+03:00","updated_at":""}`)
+
+	event, err := cryptomus.ParseWebhook(raw)
+	require.NoError(t, err)
+	require.True(t, event.Payout.Amount.Add(event.Payout.Balance.Decimal).Equal(decimal.RequireFromString("112.6")))
+	require.True(t, event.Payout.Commission.IsZero())
+	require.Equal(t, time.Date(2024, 1, 2, 7, 0, 0, 0, time.UTC), event.Payout.CreatedAt.UTC())
+	require.Equal(t, raw, event.Raw)
+}
+
+func TestSignatureDiagnostics(t *testing.T) {
+	body := []byte(`{"type":"payment","uuid":"u1","status":"paid","sign":"00000000000000000000000000000000"}`)
+
+	client := cryptomus.New(nil, "merchant", "payment-key-0123456789", "payout-key")
+	err := client.VerifyRaw(body)
+	require.ErrorIs(t, err, cryptomus.ErrInvalidSignature)
+	var sigErr *cryptomus.SignatureError
+	require.False(t, errors.As(err, &sigErr))
+
+	debug := cryptomus.New(nil, "merchant", "payment-key-0123456789", "payout-key", cryptomus.WithDebug())
+	err = debug.VerifyRaw(body)
+	require.ErrorIs(t, err, cryptomus.ErrInvalidSignature)
+	require.ErrorAs(t, err, &sigErr)
+	require.Equal(t, base64.StdEncoding.EncodeToString([]byte(`{"type":"payment","uuid":"u1","status":"paid"}`)), sigErr.Canonical)
+	require.Equal(t, "paym**************6789 (22 chars)", sigErr.MaskedKey)
+	fingerprint := sha256.Sum256([]byte("payment-key-0123456789"))
+	require.Equal(t, hex.EncodeToString(fingerprint[:8]), sigErr.KeyFingerprint)
+	require.NotContains(t, err.Error(), "payment-key-0123456789")
+	expected, err := cryptomus.Sign("payment-key-0123456789", []byte(`{"type":"payment","uuid":"u1","status":"paid"}`))
+	require.NoError(t, err)
+	require.NotContains(t, sigErr.Error(), expected)
+}
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"amount":"10","currency":"USDT"}`)
+	hash := md5.Sum([]byte(base64.StdEncoding.EncodeToString(body) + testPaymentKey))
+
+	sign, err := cryptomus.Sign(testPaymentKey, body)
+	require.NoError(t, err)
+	require.Equal(t, hex.EncodeToString(hash[:]), sign)
+
+	_, err = cryptomus.Sign("", body)
+	require.Error(t, err)
+
+	payload, _ := phpSignedPayload(testPaymentKey)
+	require.NoError(t, cryptomus.Verify(testPaymentKey, payload))
+	require.ErrorIs(t, cryptomus.Verify(testPayoutKey, payload), cryptomus.ErrInvalidSignature)
+}
+
+func TestSignLargeBodies(t *testing.T) {
+	// Sign encodes the body in chunks: check the sizes around their boundaries, and a key longer than a chunk
+	longKey := string(bytes.Repeat([]byte("k"), 5000))
+	for _, size := range []int{0, 1, 2, 3, 3071, 3072, 3073, 3074, 6144, 10000} {
+		body := bytes.Repeat([]byte("x{\"é\"}"), size)[:size]
+		for _, key := range []string{testPaymentKey, longKey} {
+			hash := md5.Sum([]byte(base64.StdEncoding.EncodeToString(body) + key))
+
+			sign, err := cryptomus.Sign(key, body)
+			require.NoError(t, err)
+			require.Equal(t, hex.EncodeToString(hash[:]), sign, "size %d", size)
+		}
+	}
+
+	body := []byte(`{"amount":"10","currency":"USDT"}`)
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = cryptomus.Sign(testPaymentKey, body)
+	})
+	require.LessOrEqual(t, allocs, 1.0, "only the signature is allocated")
+}
+
+func TestCustomSigner(t *testing.T) {
+	signer := cryptomus.SignerFunc(func(apiKey string, body []byte) (string, error) {
+		return "custom-" + apiKey, nil
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "custom-payment-key", r.Header.Get("sign"))
+		_, _ = w.Write([]byte(`{"state":0,"result":[]}`))
+	}))
+	defer server.Close()
+
+	client := cryptomus.New(server.Client(), "merchant", "payment-key", "payout-key", cryptomus.WithSigner(signer))
+	client.SetBaseURL(server.URL + "/v1")
+
+	_, err := client.TestPaymentWebhook(&cryptomus.TestWebhookRequest{
+		UrlCallback: "https://example.com/callback", Currency: "USDT", Network: "tron", Status: "paid",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, client.VerifyRaw([]byte(`{"type":"payment","uuid":"u1","sign":"custom-payment-key"}`)))
+	require.NoError(t, client.VerifyRaw([]byte(`{"type":"payout","uuid":"u1","sign":"custom-payout-key"}`)))
+	require.Error(t, client.VerifyRaw([]byte(`{"type":"payout","uuid":"u1","sign":"custom-payment-key"}`)))
+}
+
+func TestVerifyWithRotatedKeys(t *testing.T) {
+	payload, _ := phpSignedPayload("old-payment-key")
+
+	require.NoError(t, cryptomus.VerifyAny([]string{"new-payment-key", "old-payment-key"}, payload))
+	require.ErrorIs(t, cryptomus.VerifyAny([]string{"new-payment-key"}, payload), cryptomus.ErrInvalidSignature)
+
+	client := cryptomus.New(nil, "merchant", "new-payment-key", "payout-key",
+		cryptomus.WithPreviousPaymentKeys("old-payment-key"))
+	require.NoError(t, client.VerifyRaw(payload))
+
+	rotatedPayout := cryptomus.New(nil, "merchant", "new-payment-key", "new-payout-key",
+		cryptomus.WithPreviousPayoutKeys("old-payout-key"))
+	require.Error(t, rotatedPayout.VerifyRaw(payload), "previous payout keys must not verify payment webhooks")
+	_, err := rotatedPayout.VerifyWebhook(signPayload(t, "old-payout-key", payoutWebhookPayload))
+	require.NoError(t, err)
+}
+
+func TestMarshalCanonical(t *testing.T) {
+	body, err := cryptomus.MarshalCanonical(map[string]interface{}{
+		"url_callback": "https://example.com/cb?a=1&b=2",
+		"name":         "Café <Ünïcödé>",
+	})
+	require.NoError(t, err)
+	require.Equal(t, `{"name":"Café <Ünïcödé>","url_callback":"https:\/\/example.com\/cb?a=1&b=2"}`, string(body))
+
+	// Webhooks signed over PHP-encoded payloads verify.
+	fields := `"name":"Café","url":"https:\/\/example.com\/cb"`
+	hash := md5.Sum([]byte(base64.StdEncoding.EncodeToString([]byte("{"+fields+"}")) + testPaymentKey))
+	payload := []byte("{" + fields + `,"sign":"` + hex.EncodeToString(hash[:]) + `"}`)
+	require.NoError(t, newTestClient().VerifySign(testPaymentKey, payload))
+}
+
+func TestVerifyDetached(t *testing.T) {
+	body := []byte(`{"uuid":"u1","status":"paid"}`)
+	sign, err := cryptomus.Sign(testPaymentKey, body)
+	require.NoError(t, err)
+
+	require.NoError(t, cryptomus.VerifyDetached(testPaymentKey, body, sign))
+	require.ErrorIs(t, cryptomus.VerifyDetached(testPayoutKey, body, sign), cryptomus.ErrInvalidSignature)
+	require.ErrorIs(t, cryptomus.VerifyDetached(testPaymentKey, append(body, ' '), sign), cryptomus.ErrInvalidSignature)
+}
+
+func TestPreviewSignature(t *testing.T) {
+	key := "payment-key-0123456789"
+	preview, err := cryptomus.PreviewSignature(key, []byte(`{"a":1}`))
+	require.NoError(t, err)
+
+	require.Equal(t, "eyJhIjoxfQ==", preview.Base64)
+	require.Equal(t, "eyJhIjoxfQ==paym**************6789", preview.Hashed)
+	sign, err := cryptomus.Sign(key, []byte(`{"a":1}`))
+	require.NoError(t, err)
+	require.Equal(t, sign, preview.Sign)
+	require.NotContains(t, preview.String(), key)
+}
+
+func TestVerifySignKeepsOriginalBytes(t *testing.T) {
+	// Unsorted keys and numbers a float64 round-trip would reformat.
+	fields := `"uuid":"u1","amount":1.10,"order_id":12345678901234567890,"type":"payment"`
+	hash := md5.Sum([]byte(base64.StdEncoding.EncodeToString([]byte("{"+fields+"}")) + testPaymentKey))
+	payload := []byte("{" + fields + `,"sign":"` + hex.EncodeToString(hash[:]) + `"}`)
+
+	client := newTestClient()
+	require.NoError(t, client.VerifySign(testPaymentKey, payload))
+	require.ErrorIs(t, client.VerifySign(testPayoutKey, payload), cryptomus.ErrInvalidSignature)
+	require.ErrorContains(t, client.VerifySign(testPaymentKey, []byte(`{"uuid":"u1"}`)), "missing signature")
+}
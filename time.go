@@ -0,0 +1,90 @@
+package cryptomus
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// cryptomusTimeLayouts are the timestamp layouts used by the API, in order of preference.
+var cryptomusTimeLayouts = []string{
+	"2006-01-02 15:04:05-07:00",
+	"2006-01-02 15:04:05Z07:00",
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+}
+
+// cryptomusTimeLayout is the layout timestamps are encoded in, as sent by the API.
+const cryptomusTimeLayout = "2006-01-02 15:04:05-07:00"
+
+// cryptomusDateLayout is the layout of the date filters of the history endpoints.
+const cryptomusDateLayout = "2006-01-02 15:04:05"
+
+// CryptomusTime is a timestamp as sent by the API, e.g. "2023-03-23 09:43:00+03:00".
+// Unix timestamps and RFC 3339 strings are accepted as well, and null or empty values
+// decode to the zero time. It is encoded back in the layout of the API, the zero time as null.
+type CryptomusTime struct {
+	time.Time
+}
+
+// NewCryptomusTime wraps the time into a CryptomusTime.
+func NewCryptomusTime(t time.Time) CryptomusTime {
+	return CryptomusTime{t}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t CryptomusTime) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return []byte(`"` + t.Format(cryptomusTimeLayout) + `"`), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *CryptomusTime) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) || bytes.Equal(data, []byte(`""`)) {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	if data[0] != '"' {
+		seconds, err := strconv.ParseInt(string(data), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid timestamp %s: %w", data, err)
+		}
+		t.Time = time.Unix(seconds, 0)
+		return nil
+	}
+
+	value, err := strconv.Unquote(string(data))
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %s: %w", data, err)
+	}
+
+	for _, layout := range cryptomusTimeLayouts {
+		parsed, err := time.Parse(layout, value)
+		if err == nil {
+			t.Time = parsed
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid timestamp %q", value)
+}
+
+// historyPayload builds the payload of the history endpoints, with the dates formatted as
+// the API expects and the zero ones left out.
+func historyPayload(dateFrom, dateTo time.Time) map[string]any {
+	payload := map[string]any{}
+	if !dateFrom.IsZero() {
+		payload["date_from"] = dateFrom.Format(cryptomusDateLayout)
+	}
+	if !dateTo.IsZero() {
+		payload["date_to"] = dateTo.Format(cryptomusDateLayout)
+	}
+
+	return payload
+}
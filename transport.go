@@ -0,0 +1,119 @@
+package cryptomus
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Defaults of the HTTP client built by New when it is given none.
+// MaxIdleConnsPerHost is raised from the 2 connections of http.DefaultTransport, which make
+// busy merchants open and close a connection for most requests, as they all go to a single host.
+const (
+	DefaultMaxIdleConns          = 100
+	DefaultMaxIdleConnsPerHost   = 32
+	DefaultIdleConnTimeout       = 90 * time.Second
+	DefaultDialTimeout           = 10 * time.Second
+	DefaultKeepAlive             = 30 * time.Second
+	DefaultTLSHandshakeTimeout   = 10 * time.Second
+	DefaultResponseHeaderTimeout = 30 * time.Second
+	DefaultRequestTimeout        = 60 * time.Second
+)
+
+// TransportOptions tunes the HTTP client built by New when it is given none, or by NewHTTPClient.
+// Zero values select the defaults above.
+type TransportOptions struct {
+	MaxIdleConns          int           // Idle connections kept across hosts
+	MaxIdleConnsPerHost   int           // Idle connections kept to the API
+	MaxConnsPerHost       int           // Connections to the API, including active ones; unlimited if zero
+	IdleConnTimeout       time.Duration // How long an idle connection is kept
+	DialTimeout           time.Duration // Limit on establishing a TCP connection
+	KeepAlive             time.Duration // Interval of TCP keep-alive probes
+	TLSHandshakeTimeout   time.Duration // Limit on the TLS handshake
+	ResponseHeaderTimeout time.Duration // Limit on waiting for the response headers once the request is sent
+	RequestTimeout        time.Duration // Limit on the whole request, including reading the body; -1 disables it
+	TLSMinVersion         uint16        // Minimal TLS version, tls.VersionTLS12 if zero
+}
+
+// WithTransportOptions tunes the HTTP client New builds when it is given a nil *http.Client.
+// The options are ignored if New is given a client.
+func WithTransportOptions(opts *TransportOptions) Option {
+	return func(c *Cryptomus) {
+		c.transportOpts = opts
+	}
+}
+
+// NewTransport returns an *http.Transport tuned for the API, honoring the proxy environment variables
+// like http.DefaultTransport. Options may be nil, in which case the defaults are used.
+func NewTransport(opts *TransportOptions) *http.Transport {
+	o := opts.withDefaults()
+
+	dialer := &net.Dialer{
+		Timeout:   o.DialTimeout,
+		KeepAlive: o.KeepAlive,
+	}
+
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          o.MaxIdleConns,
+		MaxIdleConnsPerHost:   o.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       o.MaxConnsPerHost,
+		IdleConnTimeout:       o.IdleConnTimeout,
+		TLSHandshakeTimeout:   o.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: o.ResponseHeaderTimeout,
+		ExpectContinueTimeout: time.Second,
+		TLSClientConfig:       &tls.Config{MinVersion: o.TLSMinVersion},
+	}
+}
+
+// NewHTTPClient returns an HTTP client using NewTransport, with the request timeout of the options.
+// It is the client New uses when it is given none.
+func NewHTTPClient(opts *TransportOptions) *http.Client {
+	client := &http.Client{Transport: NewTransport(opts)}
+	if timeout := opts.withDefaults().RequestTimeout; timeout > 0 {
+		client.Timeout = timeout
+	}
+
+	return client
+}
+
+// withDefaults returns a copy of the options with the zero values replaced by the defaults.
+func (opts *TransportOptions) withDefaults() TransportOptions {
+	var o TransportOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	if o.MaxIdleConns == 0 {
+		o.MaxIdleConns = DefaultMaxIdleConns
+	}
+	if o.MaxIdleConnsPerHost == 0 {
+		o.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	}
+	if o.IdleConnTimeout == 0 {
+		o.IdleConnTimeout = DefaultIdleConnTimeout
+	}
+	if o.DialTimeout == 0 {
+		o.DialTimeout = DefaultDialTimeout
+	}
+	if o.KeepAlive == 0 {
+		o.KeepAlive = DefaultKeepAlive
+	}
+	if o.TLSHandshakeTimeout == 0 {
+		o.TLSHandshakeTimeout = DefaultTLSHandshakeTimeout
+	}
+	if o.ResponseHeaderTimeout == 0 {
+		o.ResponseHeaderTimeout = DefaultResponseHeaderTimeout
+	}
+	if o.RequestTimeout == 0 {
+		o.RequestTimeout = DefaultRequestTimeout
+	}
+	if o.TLSMinVersion == 0 {
+		o.TLSMinVersion = tls.VersionTLS12
+	}
+
+	return o
+}
@@ -0,0 +1,75 @@
+package cryptomus
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// maxCachedEndpoints bounds the endpoint URLs memoized for a base URL, which only grow past the
+// endpoints of the SDK when Call is given endpoints with identifiers in their path.
+const maxCachedEndpoints = 256
+
+// endpointURLs memoizes the full URLs of the endpoints under a base URL, so that requests
+// don't parse and join them every time.
+type endpointURLs struct {
+	base string
+
+	mu   sync.RWMutex
+	urls map[string]endpointURL
+}
+
+// endpointURL is the full URL of an endpoint.
+type endpointURL struct {
+	full string // URL the request is sent to
+	path string // Path of the URL the errors refer to, empty if it doesn't parse
+}
+
+// endpointURL returns the full URL of the endpoint under the base URL, memoizing it.
+// The memoized URLs are dropped when the base URL changes.
+func (c *Cryptomus) endpointURL(baseURL, endpoint string) (endpointURL, error) {
+	// Endpoints with a query, e.g. the cursor of a page, are too many to keep
+	if strings.Contains(endpoint, "?") {
+		return resolveEndpoint(baseURL, endpoint)
+	}
+
+	urls := c.urls.Load()
+	if urls == nil || urls.base != baseURL {
+		urls = &endpointURLs{base: baseURL, urls: make(map[string]endpointURL)}
+		c.urls.Store(urls)
+	}
+
+	urls.mu.RLock()
+	u, ok := urls.urls[endpoint]
+	urls.mu.RUnlock()
+	if ok {
+		return u, nil
+	}
+
+	u, err := resolveEndpoint(baseURL, endpoint)
+	if err != nil {
+		return endpointURL{}, err
+	}
+	urls.mu.Lock()
+	if len(urls.urls) < maxCachedEndpoints {
+		urls.urls[endpoint] = u
+	}
+	urls.mu.Unlock()
+
+	return u, nil
+}
+
+// resolveEndpoint joins the base URL and the endpoint.
+func resolveEndpoint(baseURL, endpoint string) (endpointURL, error) {
+	full, err := joinURL(baseURL, endpoint)
+	if err != nil {
+		return endpointURL{}, err
+	}
+
+	u := endpointURL{full: full}
+	if parsed, err := url.Parse(full); err == nil {
+		u.path = parsed.Path
+	}
+
+	return u, nil
+}
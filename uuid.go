@@ -0,0 +1,76 @@
+package cryptomus
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// UUID is the identifier Cryptomus assigns to invoices, payouts, static wallets and recurring payments,
+// in the canonical form "8b03432e-385b-4670-8d06-064591096795".
+type UUID string
+
+// ParseUUID returns the UUID in s, trimmed and lower-cased, failing if it is not in the canonical form.
+func ParseUUID(s string) (UUID, error) {
+	u := UUID(strings.ToLower(strings.TrimSpace(s)))
+	if !u.IsValid() {
+		return "", fmt.Errorf("cryptomus: invalid UUID %q", s)
+	}
+
+	return u, nil
+}
+
+// IsValid reports whether the UUID is 32 hexadecimal digits grouped as 8-4-4-4-12.
+func (u UUID) IsValid() bool {
+	if len(u) != 36 {
+		return false
+	}
+	for i := 0; i < len(u); i++ {
+		switch {
+		case i == 8 || i == 13 || i == 18 || i == 23:
+			if u[i] != '-' {
+				return false
+			}
+		case '0' <= u[i] && u[i] <= '9', 'a' <= u[i] && u[i] <= 'f', 'A' <= u[i] && u[i] <= 'F':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// String returns the UUID as a string.
+func (u UUID) String() string {
+	return string(u)
+}
+
+// newUUID returns a random version 4 UUID.
+func newUUID() UUID {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return UUID(fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]))
+}
+
+// validateUUID checks the uuid of the request field, if set, failing with ValidationErrors if it is malformed.
+func validateUUID(field string, u UUID) error {
+	if u == "" || u.IsValid() {
+		return nil
+	}
+
+	return ValidationErrors{field: {"The " + strings.ReplaceAll(field, "_", " ") + " must be a valid UUID."}}
+}
+
+// checkIdentifiers checks that a request refers to its object by uuid or order_id,
+// failing with the message if neither is set, and with ValidationErrors if the uuid is malformed.
+func checkIdentifiers(u UUID, orderID, message string) error {
+	if u == "" && orderID == "" {
+		return errors.New(message)
+	}
+
+	return validateUUID("uuid", u)
+}
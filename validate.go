@@ -0,0 +1,203 @@
+package cryptomus
+
+import (
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// validateRequest checks the request payload against the rules in the `validate` tags of its fields,
+// returning ValidationErrors keyed by the JSON names of the invalid fields, so problems are reported
+// without a call to the API. Payloads other than structs, such as maps, are not checked.
+//
+// The rules are separated by commas:
+//   - required: the field must not be empty
+//   - amount: the Amount must be greater than zero; it is required unless it is a pointer
+//   - url: the string must be an absolute http or https URL
+//   - currency: the CurrencyCode must be well-formed
+//   - crypto: the CurrencyCode must not be a fiat currency
+//   - uuid: the UUID must be well-formed
+//   - max=N, min=N: bounds of the length of strings and the value of numbers
+//   - oneof=a b c: the string must be one of the listed values
+//
+// Rules other than required and amount apply only to fields that are set.
+func validateRequest(payload interface{}) error {
+	v := reflect.ValueOf(payload)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	errs := ValidationErrors{}
+	validateStruct(v, errs)
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// validateStruct adds the errors of the fields of the struct, descending into embedded structs.
+func validateStruct(v reflect.Value, errs ValidationErrors) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		value := v.Field(i)
+
+		if field.Anonymous {
+			for value.Kind() == reflect.Pointer {
+				if value.IsNil() {
+					break
+				}
+				value = value.Elem()
+			}
+			if value.Kind() == reflect.Struct {
+				validateStruct(value, errs)
+			}
+			continue
+		}
+
+		rules, ok := field.Tag.Lookup("validate")
+		if !ok || !field.IsExported() {
+			continue
+		}
+
+		name := jsonFieldName(field)
+		for _, rule := range strings.Split(rules, ",") {
+			key, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+			if message := checkRule(key, arg, name, value); message != "" {
+				errs[name] = append(errs[name], message)
+				break
+			}
+		}
+	}
+}
+
+// checkRule returns the message describing why the value breaks the rule, or "" if it doesn't.
+func checkRule(rule, arg, name string, value reflect.Value) string {
+	label := strings.ReplaceAll(name, "_", " ")
+
+	if rule == "amount" {
+		if value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				return ""
+			}
+			value = value.Elem()
+		}
+		if amount, ok := value.Interface().(Amount); ok && amount.Sign() <= 0 {
+			return "The " + label + " must be greater than zero."
+		}
+		return ""
+	}
+
+	if value.IsZero() {
+		if rule == "required" {
+			return "The " + label + " field is required."
+		}
+		return ""
+	}
+	for value.Kind() == reflect.Pointer {
+		value = value.Elem()
+	}
+
+	switch rule {
+	case "url":
+		u, err := url.Parse(value.String())
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "The " + label + " must be a valid URL."
+		}
+	case "currency":
+		if !CurrencyCode(value.String()).IsValid() {
+			return "The " + label + " is not a valid currency code."
+		}
+	case "crypto":
+		if CurrencyCode(value.String()).IsFiat() {
+			return "The " + label + " must be a cryptocurrency."
+		}
+	case "uuid":
+		if !UUID(value.String()).IsValid() {
+			return "The " + label + " must be a valid UUID."
+		}
+	case "oneof":
+		for _, option := range strings.Fields(arg) {
+			if value.String() == option {
+				return ""
+			}
+		}
+		return "The selected " + label + " is invalid."
+	case "max", "min":
+		limit, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return ""
+		}
+		size, isLength := ruleSize(value)
+		if (rule == "max" && size <= limit) || (rule == "min" && size >= limit) {
+			return ""
+		}
+		bound := "may not be greater than "
+		if rule == "min" {
+			bound = "must be at least "
+		}
+		if isLength {
+			return "The " + label + " " + bound + arg + " characters."
+		}
+		return "The " + label + " " + bound + arg + "."
+	}
+
+	return ""
+}
+
+// ruleSize returns the length of strings, or the value of numbers, bounded by the max and min rules.
+// Durations are measured in seconds.
+func ruleSize(value reflect.Value) (float64, bool) {
+	if d, ok := value.Interface().(time.Duration); ok {
+		return d.Seconds(), false
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(value.String())), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), false
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), false
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), false
+	case reflect.Slice, reflect.Map:
+		if value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8 {
+			return float64(utf8.RuneCount(value.Bytes())), true
+		}
+		return float64(value.Len()), false
+	default:
+		return 0, false
+	}
+}
+
+// jsonFieldName returns the name of the field in JSON. Fields encoded by custom marshalers,
+// tagged "-", are named after the field in snake case, e.g. "lifetime" for Lifetime.
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		var b strings.Builder
+		for i, r := range field.Name {
+			if 'A' <= r && r <= 'Z' {
+				if i > 0 {
+					b.WriteByte('_')
+				}
+				r += 'a' - 'A'
+			}
+			b.WriteRune(r)
+		}
+		return b.String()
+	}
+
+	return name
+}
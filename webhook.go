@@ -3,24 +3,29 @@ package cryptomus
 import (
 	"encoding/json"
 	"errors"
+	"time"
 )
 
 const (
 	resendWebhookEndpoint      = "/payment/resend"
 	testPaymentWebhookEndpoint = "/test-webhook/payment"
 	testPayoutWebhookEndpoint  = "/test-webhook/payout"
+	testWalletWebhookEndpoint  = "/test-webhook/wallet"
 )
 
 type WebhookConvert struct {
 	ToCurrency string `json:"to_currency"`
-	Commission string `json:"commission"`
-	Rate       string `json:"rate"`
-	Amount     string `json:"amount"`
+	Commission Amount `json:"commission"`
+	Rate       Amount `json:"rate"`
+	Amount     Amount `json:"amount"`
 }
 
+// Webhook is the untyped payload of a payment, payout or static wallet callback, with amounts left as strings.
+//
+// Deprecated: use the package-level ParseWebhook, which decodes the payload into the typed event of its type.
 type Webhook struct {
 	Type              string         `json:"type"`
-	UUID              string         `json:"uuid"`
+	UUID              UUID           `json:"uuid"`
 	OrderID           string         `json:"order_id"`
 	Amount            string         `json:"amount"`
 	PaymentAmount     string         `json:"payment_amount"`
@@ -30,33 +35,153 @@ type Webhook struct {
 	IsFinal           bool           `json:"is_final"`
 	Status            string         `json:"status"`
 	From              string         `json:"from"`
-	WalletAddressUUID string         `json:"wallet_address_uuid"`
+	WalletAddressUUID UUID           `json:"wallet_address_uuid"`
 	Network           string         `json:"network"`
 	Currency          string         `json:"currency"`
 	PayerCurrency     string         `json:"payer_currency"`
-	AdditionalData    string         `json:"additional_data"`
+	AdditionalData    AdditionalData `json:"additional_data"`
 	Convert           WebhookConvert `json:"convert"`
 	TxId              string         `json:"txid"`
 	Sign              string         `json:"sign"`
 }
 
-type ResendWebhookRequest struct {
-	PaymentUUID string `json:"uuid,omitempty"`
-	OrderID     string `json:"order_id,omitempty"`
+// PaymentWebhook is the payload Cryptomus sends to the callback URL of an invoice.
+type PaymentWebhook struct {
+	Type                    string          `json:"type"`
+	UUID                    UUID            `json:"uuid"`
+	OrderID                 string          `json:"order_id"`
+	Amount                  Amount          `json:"amount"`
+	PaymentAmount           Amount          `json:"payment_amount"`
+	PaymentAmountUSD        Amount          `json:"payment_amount_usd"`
+	PayerAmount             Amount          `json:"payer_amount"`
+	PayerAmountExchangeRate Amount          `json:"payer_amount_exchange_rate"`
+	MerchantAmount          Amount          `json:"merchant_amount"`
+	Commission              Amount          `json:"commission"`
+	DiscountPercent         int8            `json:"discount_percent"`
+	Discount                Amount          `json:"discount"`
+	IsFinal                 bool            `json:"is_final"`
+	Status                  PaymentStatus   `json:"status"`
+	From                    string          `json:"from"`
+	WalletAddressUUID       UUID            `json:"wallet_address_uuid"`
+	Network                 string          `json:"network"`
+	Currency                string          `json:"currency"`
+	PayerCurrency           string          `json:"payer_currency"`
+	AdditionalData          AdditionalData  `json:"additional_data"`
+	Convert                 *WebhookConvert `json:"convert,omitempty"`
+	TxId                    string          `json:"txid"`
+	CreatedAt               *CryptomusTime  `json:"created_at,omitempty"`
+	UpdatedAt               *CryptomusTime  `json:"updated_at,omitempty"`
+	Sign                    string          `json:"sign"`
 }
 
-type resendWebhookRawResponse struct {
-	Result []string `json:"result"`
-	State  int8     `json:"state"`
+// PayoutWebhook is the payload Cryptomus sends to the callback URL of a payout.
+type PayoutWebhook struct {
+	Type           string         `json:"type"`
+	UUID           UUID           `json:"uuid"`
+	OrderID        string         `json:"order_id"`
+	Amount         Amount         `json:"amount"`
+	MerchantAmount Amount         `json:"merchant_amount"`
+	Commission     Amount         `json:"commission"`
+	IsFinal        bool           `json:"is_final"`
+	Status         PayoutStatus   `json:"status"`
+	TxId           string         `json:"txid"`
+	Address        string         `json:"address"`
+	Currency       string         `json:"currency"`
+	Network        string         `json:"network"`
+	PayerCurrency  string         `json:"payer_currency"`
+	PayerAmount    Amount         `json:"payer_amount"`
+	Balance        Amount         `json:"balance"`
+	CreatedAt      *CryptomusTime `json:"created_at,omitempty"`
+	UpdatedAt      *CryptomusTime `json:"updated_at,omitempty"`
+	Sign           string         `json:"sign"`
+}
+
+// WalletWebhook is the payload Cryptomus sends to the callback URL of a static wallet on deposit.
+// OrderID and WalletAddressUUID identify the static wallet, and therefore the account the funds belong to.
+type WalletWebhook struct {
+	Type              string          `json:"type"`
+	UUID              UUID            `json:"uuid"`
+	OrderID           string          `json:"order_id"`
+	WalletAddressUUID UUID            `json:"wallet_address_uuid"`
+	Address           string          `json:"address"`
+	From              string          `json:"from"`
+	Amount            Amount          `json:"amount"`
+	PaymentAmount     Amount          `json:"payment_amount"`
+	PaymentAmountUSD  Amount          `json:"payment_amount_usd"`
+	MerchantAmount    Amount          `json:"merchant_amount"`
+	Commission        Amount          `json:"commission"`
+	IsFinal           bool            `json:"is_final"`
+	Status            PaymentStatus   `json:"status"`
+	Network           string          `json:"network"`
+	Currency          string          `json:"currency"`
+	PayerCurrency     string          `json:"payer_currency"`
+	AdditionalData    AdditionalData  `json:"additional_data"`
+	Convert           *WebhookConvert `json:"convert,omitempty"`
+	TxId              string          `json:"txid"`
+	CreatedAt         *CryptomusTime  `json:"created_at,omitempty"`
+	UpdatedAt         *CryptomusTime  `json:"updated_at,omitempty"`
+	Sign              string          `json:"sign"`
+}
+
+// RecurrenceWebhook is the payload Cryptomus sends to the callback URL of a recurring payment on every charge.
+type RecurrenceWebhook struct {
+	Type           string           `json:"type"`
+	UUID           UUID             `json:"uuid"`
+	OrderID        string           `json:"order_id"`
+	Name           string           `json:"name"`
+	Amount         Amount           `json:"amount"`
+	Currency       string           `json:"currency"`
+	PayerCurrency  string           `json:"payer_currency"`
+	PayerAmount    Amount           `json:"payer_amount"`
+	PayerAmountUSD Amount           `json:"payer_amount_usd"`
+	Period         string           `json:"period"`
+	Status         RecurrenceStatus `json:"status"`
+	IsFinal        bool             `json:"is_final"`
+	TxId           string           `json:"txid"`
+	LastPayOff     *CryptomusTime   `json:"last_pay_off,omitempty"`
+	DiscountDays   int              `json:"discount_days,omitempty"`
+	DiscountAmount Amount           `json:"discount_amount"`
+	EndOfDiscount  *CryptomusTime   `json:"end_of_discount,omitempty"`
+	AdditionalData AdditionalData   `json:"additional_data"`
+	Sign           string           `json:"sign"`
+}
+
+// NextChargeAt estimates when the recurring payment is charged next, based on the
+// last charge and the period. It returns false if either of them is unknown.
+func (w *RecurrenceWebhook) NextChargeAt() (time.Time, bool) {
+	if w.LastPayOff == nil || w.LastPayOff.IsZero() {
+		return time.Time{}, false
+	}
+
+	last := w.LastPayOff.Time
+	switch w.Period {
+	case "weekly":
+		return last.AddDate(0, 0, 7), true
+	case "monthly":
+		return last.AddDate(0, 1, 0), true
+	case "three_month":
+		return last.AddDate(0, 3, 0), true
+	case "half_year":
+		return last.AddDate(0, 6, 0), true
+	case "yearly":
+		return last.AddDate(1, 0, 0), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+type ResendWebhookRequest struct {
+	PaymentUUID UUID   `json:"uuid,omitempty"`
+	OrderID     string `json:"order_id,omitempty"`
 }
 
 type TestWebhookRequest struct {
-	UrlCallback string `json:"url_callback"`
-	Currency    string `json:"currency"`
-	Network     string `json:"network"`
-	UUID        string `json:"uuid,omitempty"`
+	UrlCallback string `json:"url_callback" validate:"required,url"`
+	Currency    string `json:"currency" validate:"required"`
+	Network     string `json:"network" validate:"required"`
+	UUID        UUID   `json:"uuid,omitempty" validate:"uuid"`
 	OrderID     string `json:"order_id,omitempty"`
-	Status      string `json:"status"`
+	Status      string `json:"status" validate:"required"`
 }
 
 type TestWebhookResponse struct {
@@ -64,8 +189,10 @@ type TestWebhookResponse struct {
 	State  int8     `json:"state"`
 }
 
+// ParseWebhook decodes the payload into a Webhook, verifying its signature if verifySign is set.
+//
+// Deprecated: verify the body with VerifyWebhook and decode it with the package-level ParseWebhook.
 func (c *Cryptomus) ParseWebhook(reqBody []byte, verifySign bool) (*Webhook, error) {
-	var apiKey string
 	response := &Webhook{}
 
 	err := json.Unmarshal(reqBody, response)
@@ -73,18 +200,14 @@ func (c *Cryptomus) ParseWebhook(reqBody []byte, verifySign bool) (*Webhook, err
 		return nil, err
 	}
 
-	switch response.Type {
-	case "payment":
-		apiKey = c.paymentApiKey
-	case "payout":
-		apiKey = c.payoutApiKey
+	switch WebhookType(response.Type) {
+	case WebhookTypePayment, WebhookTypePayout, WebhookTypeWallet:
 	default:
 		return nil, errors.New("unknown webhook type")
 	}
 
 	if verifySign {
-		err = c.VerifySign(apiKey, reqBody)
-		if err != nil {
+		if _, err = c.VerifyWebhook(reqBody); err != nil {
 			return nil, err
 		}
 	}
@@ -93,23 +216,16 @@ func (c *Cryptomus) ParseWebhook(reqBody []byte, verifySign bool) (*Webhook, err
 }
 
 func (c *Cryptomus) ResendWebhook(resendRequest *ResendWebhookRequest) (bool, error) {
-	if resendRequest.PaymentUUID == "" && resendRequest.OrderID == "" {
-		return false, errors.New("you should pass one of required values [PaymentUUID, OrderID]")
-	}
-
-	res, err := c.fetch("POST", resendWebhookEndpoint, resendRequest)
-	if err != nil {
+	if err := checkIdentifiers(resendRequest.PaymentUUID, resendRequest.OrderID, "you should pass one of required values [PaymentUUID, OrderID]"); err != nil {
 		return false, err
 	}
 
-	defer res.Body.Close()
-
-	response := &resendWebhookRawResponse{}
-	if err = json.NewDecoder(res.Body).Decode(response); err != nil {
+	result, err := post[[]string](c, resendWebhookEndpoint, resendRequest)
+	if err != nil {
 		return false, err
 	}
 
-	return len(response.Result) == 0, nil
+	return len(result) == 0, nil
 }
 
 func (c *Cryptomus) TestPaymentWebhook(testRequest *TestWebhookRequest) (*TestWebhookResponse, error) {
@@ -121,7 +237,7 @@ func (c *Cryptomus) TestPaymentWebhook(testRequest *TestWebhookRequest) (*TestWe
 	defer res.Body.Close()
 
 	response := &TestWebhookResponse{}
-	if err = json.NewDecoder(res.Body).Decode(response); err != nil {
+	if err = decodeInto(res, response); err != nil {
 		return nil, err
 	}
 
@@ -137,7 +253,23 @@ func (c *Cryptomus) TestPayoutWebhook(testRequest *TestWebhookRequest) (*TestWeb
 	defer res.Body.Close()
 
 	response := &TestWebhookResponse{}
-	if err = json.NewDecoder(res.Body).Decode(response); err != nil {
+	if err = decodeInto(res, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *Cryptomus) TestWalletWebhook(testRequest *TestWebhookRequest) (*TestWebhookResponse, error) {
+	res, err := c.fetch("POST", testWalletWebhookEndpoint, testRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	response := &TestWebhookResponse{}
+	if err = decodeInto(res, response); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,166 @@
+package cryptomus
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// DefaultDedupCapacity is the number of keys remembered by a MemoryDedupStore unless configured otherwise.
+const DefaultDedupCapacity = 10000
+
+// DedupStore records the webhooks that were already processed, so retried callbacks are not handled twice.
+// Stores backed by Redis are straightforward to implement with SET NX and DEL.
+type DedupStore interface {
+	// Add records the key and reports whether it was not recorded before.
+	Add(ctx context.Context, key string) (bool, error)
+	// Remove forgets the key, so the webhook is processed again when it is retried.
+	Remove(ctx context.Context, key string) error
+}
+
+// WithDedup makes the dispatcher skip webhooks whose (type, uuid, status) was already recorded in the store.
+// The record is removed again when a handler fails, so the retried callback is processed.
+func WithDedup(store DedupStore) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.dedup = store
+	}
+}
+
+// DedupKey returns the key identifying the event in a DedupStore.
+func DedupKey(event *WebhookEvent) string {
+	return fmt.Sprintf("%s:%s:%s", event.Type, event.UUID(), event.Status())
+}
+
+// MemoryDedupStore is an in-memory DedupStore evicting the least recently added keys.
+// It is safe for concurrent use.
+type MemoryDedupStore struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	keys     map[string]*list.Element
+}
+
+// NewMemoryDedupStore creates a new in-memory store remembering up to capacity keys,
+// or DefaultDedupCapacity keys if capacity is not positive.
+func NewMemoryDedupStore(capacity int) *MemoryDedupStore {
+	if capacity <= 0 {
+		capacity = DefaultDedupCapacity
+	}
+
+	return &MemoryDedupStore{
+		capacity: capacity,
+		order:    list.New(),
+		keys:     make(map[string]*list.Element),
+	}
+}
+
+// Add records the key and reports whether it was not recorded before.
+func (s *MemoryDedupStore) Add(ctx context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elem, ok := s.keys[key]; ok {
+		s.order.MoveToFront(elem)
+		return false, nil
+	}
+
+	s.keys[key] = s.order.PushFront(key)
+	if s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.keys, oldest.Value.(string))
+	}
+
+	return true, nil
+}
+
+// Contains reports whether the key was recorded.
+func (s *MemoryDedupStore) Contains(ctx context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.keys[key]
+
+	return ok, nil
+}
+
+// Remove forgets the key.
+func (s *MemoryDedupStore) Remove(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elem, ok := s.keys[key]; ok {
+		s.order.Remove(elem)
+		delete(s.keys, key)
+	}
+
+	return nil
+}
+
+// SQLDedupStore is a DedupStore backed by a SQL table with a unique dedup_key column, e.g.
+//
+//	CREATE TABLE cryptomus_webhook_dedup (dedup_key VARCHAR(255) PRIMARY KEY, created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP)
+//
+// The default queries use the PostgreSQL and SQLite syntax. For MySQL, set them to
+// "INSERT IGNORE INTO ... (dedup_key) VALUES (?)", "DELETE FROM ... WHERE dedup_key = ?" and
+// "SELECT 1 FROM ... WHERE dedup_key = ?".
+type SQLDedupStore struct {
+	DB *sql.DB
+	// InsertQuery inserts the key given as the only argument, affecting no rows if it already exists.
+	InsertQuery string
+	// DeleteQuery deletes the key given as the only argument.
+	DeleteQuery string
+	// SelectQuery selects the key given as the only argument.
+	SelectQuery string
+}
+
+// NewSQLDedupStore creates a new SQL store using the table.
+func NewSQLDedupStore(db *sql.DB, table string) *SQLDedupStore {
+	return &SQLDedupStore{
+		DB:          db,
+		InsertQuery: fmt.Sprintf("INSERT INTO %s (dedup_key) VALUES ($1) ON CONFLICT DO NOTHING", table),
+		DeleteQuery: fmt.Sprintf("DELETE FROM %s WHERE dedup_key = $1", table),
+		SelectQuery: fmt.Sprintf("SELECT 1 FROM %s WHERE dedup_key = $1", table),
+	}
+}
+
+// Add records the key and reports whether it was not recorded before.
+func (s *SQLDedupStore) Add(ctx context.Context, key string) (bool, error) {
+	res, err := s.DB.ExecContext(ctx, s.InsertQuery, key)
+	if err != nil {
+		return false, fmt.Errorf("failed to record webhook: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to record webhook: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// Contains reports whether the key was recorded.
+func (s *SQLDedupStore) Contains(ctx context.Context, key string) (bool, error) {
+	var found int
+	err := s.DB.QueryRowContext(ctx, s.SelectQuery, key).Scan(&found)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to look up webhook: %w", err)
+	}
+
+	return true, nil
+}
+
+// Remove forgets the key.
+func (s *SQLDedupStore) Remove(ctx context.Context, key string) error {
+	if _, err := s.DB.ExecContext(ctx, s.DeleteQuery, key); err != nil {
+		return fmt.Errorf("failed to forget webhook: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,440 @@
+package cryptomus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// maxWebhookBodySize limits the size of webhook payloads read by the dispatcher.
+const maxWebhookBodySize = 1 << 20
+
+// WebhookHandler handles a verified webhook event.
+// Returning an error makes the dispatcher respond with a failure, so Cryptomus retries the callback.
+type WebhookHandler func(ctx context.Context, event *WebhookEvent) error
+
+// DispatcherOption configures a Dispatcher.
+type DispatcherOption func(*Dispatcher)
+
+// WithoutSignatureVerification disables signature verification, e.g. for local testing.
+func WithoutSignatureVerification() DispatcherOption {
+	return func(d *Dispatcher) {
+		d.verify = false
+	}
+}
+
+// WithAsync makes the dispatcher acknowledge verified callbacks immediately and process their events
+// in a pool of workers fed by a queue of the given size, so slow handlers don't make Cryptomus
+// retry and duplicate callbacks. Callbacks are rejected while the queue is full.
+// Handler errors are reported to the function set with WithAsyncErrorHandler.
+func WithAsync(workers, queueSize int) DispatcherOption {
+	return func(d *Dispatcher) {
+		if workers < 1 {
+			workers = 1
+		}
+		if queueSize < 0 {
+			queueSize = 0
+		}
+		d.workers = workers
+		d.queueSize = queueSize
+	}
+}
+
+// WithAsyncErrorHandler sets the function invoked with the errors and recovered panics
+// of handlers run by the asynchronous workers.
+func WithAsyncErrorHandler(onError func(event *WebhookEvent, err error)) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.onAsyncError = onError
+	}
+}
+
+// WithOrderedProcessing serializes the processing of events sharing the type and order_id,
+// e.g. the "process" and "paid" callbacks of an invoice arriving close together,
+// while events of different orders are still processed in parallel.
+// With WithAsync, the events of an order are also processed in the order they were received:
+// each order is assigned to a single worker, whose queue holds its share of the queue size.
+func WithOrderedProcessing() DispatcherOption {
+	return func(d *Dispatcher) {
+		d.orderLocks = &keyedMutex{locks: make(map[string]*keyedMutexEntry)}
+	}
+}
+
+// ErrWebhookQueueFull is returned when an asynchronous dispatcher cannot queue an event.
+var ErrWebhookQueueFull = errors.New("webhook queue is full")
+
+// ErrDispatcherClosed is returned when an event is received after the dispatcher was shut down.
+var ErrDispatcherClosed = errors.New("webhook dispatcher is closed")
+
+// dispatchKey identifies the handlers of an event type and status, where an empty status matches any status.
+type dispatchKey struct {
+	typ    WebhookType
+	status string
+}
+
+// Dispatcher routes verified webhook events to the handlers registered per event type and status.
+// It implements http.Handler, so it can be mounted directly as the callback URL of every entity.
+// Handlers must be registered before the dispatcher starts serving.
+type Dispatcher struct {
+	client    *Cryptomus
+	verify    bool
+	parseOpts []ParseOption
+	handlers  map[dispatchKey][]WebhookHandler
+	fallback  WebhookHandler
+
+	workers      int
+	queueSize    int
+	queues       []chan *WebhookEvent // A single queue shared by the workers, or one per worker if ordered
+	onAsyncError func(event *WebhookEvent, err error)
+	dedup        DedupStore
+	orderLocks   *keyedMutex
+	queueMu      sync.RWMutex
+	closed       bool
+	wg           sync.WaitGroup
+}
+
+// NewDispatcher creates a new webhook dispatcher verifying signatures with the keys of the client.
+func NewDispatcher(client *Cryptomus, opts ...DispatcherOption) *Dispatcher {
+	d := &Dispatcher{
+		client:   client,
+		verify:   true,
+		handlers: make(map[dispatchKey][]WebhookHandler),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	if d.workers > 0 {
+		d.startWorkers()
+	}
+
+	return d
+}
+
+// startWorkers creates the queues and starts the asynchronous workers.
+func (d *Dispatcher) startWorkers() {
+	if d.orderLocks == nil {
+		d.queues = []chan *WebhookEvent{make(chan *WebhookEvent, d.queueSize)}
+	} else {
+		// Split the queue size between the workers, rounding up so that none is left without room
+		size := (d.queueSize + d.workers - 1) / d.workers
+		d.queues = make([]chan *WebhookEvent, d.workers)
+		for i := range d.queues {
+			d.queues[i] = make(chan *WebhookEvent, size)
+		}
+	}
+
+	for i := 0; i < d.workers; i++ {
+		d.wg.Add(1)
+		go d.work(d.queues[i%len(d.queues)])
+	}
+}
+
+// On registers a handler for events of the type with the status, or with any status if status is empty.
+// Handlers registered for the exact status run before the ones registered for any status.
+func (d *Dispatcher) On(typ WebhookType, status string, handler WebhookHandler) {
+	key := dispatchKey{typ: typ, status: status}
+	d.handlers[key] = append(d.handlers[key], handler)
+}
+
+// OnUnknown registers the catch-all handler, invoked for events of unknown types
+// and for events no other handler is registered for.
+func (d *Dispatcher) OnUnknown(handler WebhookHandler) {
+	d.fallback = handler
+}
+
+// OnPayment registers a handler for payment events with the status, or with any status if status is empty.
+func (d *Dispatcher) OnPayment(status string, handler func(ctx context.Context, webhook *PaymentWebhook) error) {
+	d.On(WebhookTypePayment, status, func(ctx context.Context, event *WebhookEvent) error {
+		return handler(ctx, event.Payment)
+	})
+}
+
+// OnPayout registers a handler for payout events with the status, or with any status if status is empty.
+func (d *Dispatcher) OnPayout(status string, handler func(ctx context.Context, webhook *PayoutWebhook) error) {
+	d.On(WebhookTypePayout, status, func(ctx context.Context, event *WebhookEvent) error {
+		return handler(ctx, event.Payout)
+	})
+}
+
+// OnWallet registers a handler for static wallet events with the status, or with any status if status is empty.
+func (d *Dispatcher) OnWallet(status string, handler func(ctx context.Context, webhook *WalletWebhook) error) {
+	d.On(WebhookTypeWallet, status, func(ctx context.Context, event *WebhookEvent) error {
+		return handler(ctx, event.Wallet)
+	})
+}
+
+// OnRecurrence registers a handler for recurring payment events with the status, or with any status if status is empty.
+func (d *Dispatcher) OnRecurrence(status string, handler func(ctx context.Context, webhook *RecurrenceWebhook) error) {
+	d.On(WebhookTypeRecurrence, status, func(ctx context.Context, event *WebhookEvent) error {
+		return handler(ctx, event.Recurrence)
+	})
+}
+
+// OnPaymentPaid registers a handler for paid invoices, including the ones paid with an overpayment.
+func (d *Dispatcher) OnPaymentPaid(handler func(ctx context.Context, webhook *PaymentWebhook) error) {
+	d.OnPayment("paid", handler)
+	d.OnPayment("paid_over", handler)
+}
+
+// OnPaymentFail registers a handler for failed invoices.
+func (d *Dispatcher) OnPaymentFail(handler func(ctx context.Context, webhook *PaymentWebhook) error) {
+	d.OnPayment("fail", handler)
+	d.OnPayment("system_fail", handler)
+}
+
+// OnPayoutPaid registers a handler for completed payouts.
+func (d *Dispatcher) OnPayoutPaid(handler func(ctx context.Context, webhook *PayoutWebhook) error) {
+	d.OnPayout("paid", handler)
+}
+
+// OnPayoutFail registers a handler for failed payouts.
+func (d *Dispatcher) OnPayoutFail(handler func(ctx context.Context, webhook *PayoutWebhook) error) {
+	d.OnPayout("fail", handler)
+	d.OnPayout("system_fail", handler)
+}
+
+// OnWalletPaid registers a handler for deposits to static wallets.
+func (d *Dispatcher) OnWalletPaid(handler func(ctx context.Context, webhook *WalletWebhook) error) {
+	d.OnWallet("paid", handler)
+	d.OnWallet("paid_over", handler)
+}
+
+// webhookHandlerError marks errors returned by webhook handlers, as opposed to invalid payloads.
+type webhookHandlerError struct {
+	err error
+}
+
+func (e *webhookHandlerError) Error() string { return e.err.Error() }
+
+func (e *webhookHandlerError) Unwrap() error { return e.err }
+
+// Dispatch parses and verifies the raw webhook payload and routes it to the registered handlers.
+func (d *Dispatcher) Dispatch(ctx context.Context, raw []byte) error {
+	metrics := d.client.metrics
+
+	event, err := ParseWebhook(raw, d.parseOpts...)
+	if err != nil && !errors.Is(err, ErrUnknownWebhookType) {
+		metrics.IncCounter(MetricWebhooksInvalid, nil)
+		return err
+	}
+	metrics.IncCounter(MetricWebhooksReceived, map[string]string{"type": string(event.Type)})
+
+	if d.verify {
+		if _, verifyErr := d.client.VerifyWebhook(raw); verifyErr != nil {
+			metrics.IncCounter(MetricWebhookVerifyFailures, map[string]string{"type": string(event.Type)})
+			return verifyErr
+		}
+	}
+
+	if d.dedup != nil {
+		added, err := d.dedup.Add(ctx, DedupKey(&event))
+		if err != nil {
+			return &webhookHandlerError{err: err}
+		}
+		if !added {
+			return nil
+		}
+	}
+
+	if d.queues != nil {
+		if err := d.enqueue(&event); err != nil {
+			d.forget(&event)
+			return err
+		}
+		return nil
+	}
+
+	return d.process(ctx, &event)
+}
+
+// process routes the event, forgetting its dedup record if a handler fails.
+func (d *Dispatcher) process(ctx context.Context, event *WebhookEvent) error {
+	if d.orderLocks != nil && event.OrderID() != "" {
+		unlock := d.orderLocks.lock(orderKey(event))
+		defer unlock()
+	}
+
+	start := d.client.clock.Now()
+	err := d.route(ctx, event)
+
+	tags := map[string]string{"type": string(event.Type), "status": event.Status(), "result": "ok"}
+	if err != nil {
+		tags["result"] = "error"
+		d.client.metrics.IncCounter(MetricWebhookHandlerErrors, map[string]string{"type": tags["type"], "status": tags["status"]})
+		d.forget(event)
+	}
+	d.client.metrics.ObserveDuration(MetricWebhookProcessDuration, d.client.clock.Now().Sub(start), tags)
+
+	return err
+}
+
+// forget removes the dedup record of the event, so its retried callback is processed.
+func (d *Dispatcher) forget(event *WebhookEvent) {
+	if d.dedup != nil {
+		_ = d.dedup.Remove(context.Background(), DedupKey(event))
+	}
+}
+
+// enqueue hands the event over to the asynchronous workers.
+func (d *Dispatcher) enqueue(event *WebhookEvent) error {
+	d.queueMu.RLock()
+	defer d.queueMu.RUnlock()
+
+	if d.closed {
+		return &webhookHandlerError{err: ErrDispatcherClosed}
+	}
+
+	select {
+	case d.queueFor(event) <- event:
+		return nil
+	default:
+		return &webhookHandlerError{err: ErrWebhookQueueFull}
+	}
+}
+
+// queueFor returns the queue of the event: the queue of the worker its order is assigned to
+// if the processing is ordered, the shared queue otherwise.
+func (d *Dispatcher) queueFor(event *WebhookEvent) chan *WebhookEvent {
+	if len(d.queues) == 1 {
+		return d.queues[0]
+	}
+
+	key := orderKey(event)
+	if event.OrderID() == "" {
+		// Unrelated to other events, spread by its own identity
+		key = DedupKey(event)
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+
+	return d.queues[h.Sum32()%uint32(len(d.queues))]
+}
+
+// orderKey identifies the events whose processing is ordered together.
+func orderKey(event *WebhookEvent) string {
+	return string(event.Type) + ":" + event.OrderID()
+}
+
+// work processes the events of the queue until it is closed.
+func (d *Dispatcher) work(queue chan *WebhookEvent) {
+	defer d.wg.Done()
+
+	for event := range queue {
+		if err := d.process(context.Background(), event); err != nil && d.onAsyncError != nil {
+			d.onAsyncError(event, err)
+		}
+	}
+}
+
+// Shutdown stops accepting events and waits until the queued events are processed or the context is done.
+// It is a no-op for synchronous dispatchers.
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	if d.queues == nil {
+		return nil
+	}
+
+	d.queueMu.Lock()
+	if !d.closed {
+		d.closed = true
+		for _, queue := range d.queues {
+			close(queue)
+		}
+	}
+	d.queueMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// route invokes the handlers registered for the event, or the catch-all handler if there are none.
+func (d *Dispatcher) route(ctx context.Context, event *WebhookEvent) error {
+	handlers := d.handlers[dispatchKey{typ: event.Type, status: event.Status()}]
+	if event.Status() != "" {
+		handlers = append(handlers[:len(handlers):len(handlers)], d.handlers[dispatchKey{typ: event.Type}]...)
+	}
+
+	if len(handlers) == 0 && d.fallback != nil {
+		handlers = []WebhookHandler{d.fallback}
+	}
+
+	for _, handler := range handlers {
+		if err := callWebhookHandler(ctx, handler, event); err != nil {
+			return &webhookHandlerError{err: fmt.Errorf("%s webhook %s: %w", event.Type, event.UUID(), err)}
+		}
+	}
+
+	return nil
+}
+
+// callWebhookHandler invokes the handler, turning a panic into an error.
+func callWebhookHandler(ctx context.Context, handler WebhookHandler, event *WebhookEvent) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("webhook handler panicked: %v", r)
+		}
+	}()
+
+	return handler(ctx, event)
+}
+
+// ServeHTTP implements http.Handler.
+// It responds with 400 to invalid or unverified payloads and with 500 when a handler fails
+// or, in asynchronous mode, when the event cannot be queued.
+func (d *Dispatcher) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodySize))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	if err := d.Dispatch(r.Context(), raw); err != nil {
+		var handlerErr *webhookHandlerError
+		if errors.As(err, &handlerErr) {
+			http.Error(w, "webhook handler failed", http.StatusInternalServerError)
+			return
+		}
+		http.Error(w, "invalid webhook", http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// webhookKey returns the API key the webhooks of the type are signed with.
+func (c *Cryptomus) webhookKey(typ WebhookType) string {
+	creds := c.currentCredentials()
+	if typ == WebhookTypePayout {
+		return creds.payoutApiKey
+	}
+
+	return creds.paymentApiKey
+}
+
+// webhookKeys returns the API key the webhooks of the type are signed with, followed by its previous keys.
+func (c *Cryptomus) webhookKeys(typ WebhookType) []string {
+	creds := c.currentCredentials()
+	if typ == WebhookTypePayout {
+		return append([]string{creds.payoutApiKey}, c.previousPayoutKeys...)
+	}
+
+	return append([]string{creds.paymentApiKey}, c.previousPaymentKeys...)
+}
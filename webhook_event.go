@@ -0,0 +1,158 @@
+package cryptomus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// WebhookType is the kind of event a webhook payload carries.
+type WebhookType string
+
+const (
+	WebhookTypePayment    WebhookType = "payment"
+	WebhookTypePayout     WebhookType = "payout"
+	WebhookTypeWallet     WebhookType = "wallet"
+	WebhookTypeRecurrence WebhookType = "recurrence"
+)
+
+// IsKnown reports whether the type is one of the webhook types known to the SDK.
+func (t WebhookType) IsKnown() bool {
+	switch t {
+	case WebhookTypePayment, WebhookTypePayout, WebhookTypeWallet, WebhookTypeRecurrence:
+		return true
+	default:
+		return false
+	}
+}
+
+// ErrUnknownWebhookType is returned when the type of a webhook payload cannot be detected.
+var ErrUnknownWebhookType = errors.New("unknown webhook type")
+
+// WebhookEvent is a parsed webhook payload of any type.
+// Exactly one of the typed payloads is set, matching Type.
+type WebhookEvent struct {
+	Type       WebhookType
+	Payment    *PaymentWebhook
+	Payout     *PayoutWebhook
+	Wallet     *WalletWebhook
+	Recurrence *RecurrenceWebhook
+	Raw        []byte // Original payload
+	// Extra holds the fields of the payload unknown to the typed payload, when parsed leniently.
+	Extra map[string]json.RawMessage
+}
+
+// webhookEnvelope holds the fields used to detect the type of a webhook payload.
+type webhookEnvelope struct {
+	Type   string          `json:"type"`
+	Period json.RawMessage `json:"period"`
+}
+
+// ParseWebhook detects the type of the webhook payload and decodes it into the matching typed payload,
+// so a single endpoint can serve the callbacks of payments, payouts, static wallets and recurring payments.
+// The signature is not verified. For payloads of an unknown type, the returned event carries
+// the detected Type and Raw payload together with an error wrapping ErrUnknownWebhookType.
+// Payloads are parsed leniently unless WithParseMode(ParseStrict) is given.
+func ParseWebhook(raw []byte, opts ...ParseOption) (WebhookEvent, error) {
+	config := &parseConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	typ, err := detectWebhookType(raw)
+	if err != nil {
+		return WebhookEvent{}, err
+	}
+
+	event := WebhookEvent{Type: typ, Raw: raw}
+
+	var payload interface{}
+	switch event.Type {
+	case WebhookTypePayment:
+		event.Payment = &PaymentWebhook{}
+		payload = event.Payment
+	case WebhookTypePayout:
+		event.Payout = &PayoutWebhook{}
+		payload = event.Payout
+	case WebhookTypeWallet:
+		event.Wallet = &WalletWebhook{}
+		payload = event.Wallet
+	case WebhookTypeRecurrence:
+		event.Recurrence = &RecurrenceWebhook{}
+		payload = event.Recurrence
+	default:
+		return event, fmt.Errorf("%w: %q", ErrUnknownWebhookType, typ)
+	}
+
+	extra, err := decodeWebhookPayload(raw, event.Type, payload, config.mode)
+	if err != nil {
+		return WebhookEvent{}, fmt.Errorf("failed to decode %s webhook: %w", event.Type, err)
+	}
+	event.Extra = extra
+
+	return event, nil
+}
+
+// UUID returns the uuid of the event's payload.
+func (e *WebhookEvent) UUID() UUID {
+	switch {
+	case e.Payment != nil:
+		return e.Payment.UUID
+	case e.Payout != nil:
+		return e.Payout.UUID
+	case e.Wallet != nil:
+		return e.Wallet.UUID
+	case e.Recurrence != nil:
+		return e.Recurrence.UUID
+	default:
+		return ""
+	}
+}
+
+// OrderID returns the order_id of the event's payload.
+func (e *WebhookEvent) OrderID() string {
+	switch {
+	case e.Payment != nil:
+		return e.Payment.OrderID
+	case e.Payout != nil:
+		return e.Payout.OrderID
+	case e.Wallet != nil:
+		return e.Wallet.OrderID
+	case e.Recurrence != nil:
+		return e.Recurrence.OrderID
+	default:
+		return ""
+	}
+}
+
+// Status returns the status of the event's payload.
+func (e *WebhookEvent) Status() string {
+	switch {
+	case e.Payment != nil:
+		return string(e.Payment.Status)
+	case e.Payout != nil:
+		return string(e.Payout.Status)
+	case e.Wallet != nil:
+		return string(e.Wallet.Status)
+	case e.Recurrence != nil:
+		return string(e.Recurrence.Status)
+	default:
+		return ""
+	}
+}
+
+// detectWebhookType returns the type of the raw webhook, recognizing recurring payment
+// callbacks that carry no type field by their period.
+func detectWebhookType(raw []byte) (WebhookType, error) {
+	envelope := &webhookEnvelope{}
+	if err := json.Unmarshal(raw, envelope); err != nil {
+		return "", fmt.Errorf("failed to decode webhook: %w", err)
+	}
+
+	typ := WebhookType(envelope.Type)
+	if typ == "" && len(envelope.Period) > 0 {
+		typ = WebhookTypeRecurrence
+	}
+
+	return typ, nil
+}
@@ -0,0 +1,85 @@
+package cryptomus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// IdempotencyStore persists the keys of the webhooks whose business logic completed.
+// MemoryDedupStore and SQLDedupStore implement it.
+type IdempotencyStore interface {
+	// Contains reports whether the key was recorded.
+	Contains(ctx context.Context, key string) (bool, error)
+	// Add records the key and reports whether it was not recorded before.
+	Add(ctx context.Context, key string) (bool, error)
+}
+
+// WrapIdempotent returns a handler invoking the handler at most once per webhook (type, uuid, status),
+// even across process restarts when the store is persistent. The key is recorded only after the handler
+// succeeds, so a callback whose processing failed or was interrupted is processed again when retried.
+// Concurrent deliveries of the same webhook are serialized within the process.
+func WrapIdempotent(handler WebhookHandler, store IdempotencyStore) WebhookHandler {
+	locks := &keyedMutex{locks: make(map[string]*keyedMutexEntry)}
+
+	return func(ctx context.Context, event *WebhookEvent) error {
+		key := DedupKey(event)
+
+		unlock := locks.lock(key)
+		defer unlock()
+
+		done, err := store.Contains(ctx, key)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		if err := handler(ctx, event); err != nil {
+			return err
+		}
+
+		if _, err := store.Add(ctx, key); err != nil {
+			return fmt.Errorf("webhook handled but not committed: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// keyedMutex provides a mutex per key, dropping the mutexes nobody holds or waits for.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*keyedMutexEntry
+}
+
+type keyedMutexEntry struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// lock locks the mutex of the key and returns the function unlocking it.
+func (m *keyedMutex) lock(key string) func() {
+	m.mu.Lock()
+	entry, ok := m.locks[key]
+	if !ok {
+		entry = &keyedMutexEntry{}
+		m.locks[key] = entry
+	}
+	entry.refs++
+	m.mu.Unlock()
+
+	entry.mu.Lock()
+
+	return func() {
+		entry.mu.Unlock()
+
+		m.mu.Lock()
+		entry.refs--
+		if entry.refs == 0 {
+			delete(m.locks, key)
+		}
+		m.mu.Unlock()
+	}
+}
@@ -0,0 +1,155 @@
+package cryptomus
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// CryptomusWebhookIPs are the published addresses Cryptomus sends callbacks from.
+var CryptomusWebhookIPs = []string{"91.227.144.54"}
+
+// IPAllowlistOptions configures an IPAllowlist.
+type IPAllowlistOptions struct {
+	// Allowed lists the addresses or CIDR ranges callbacks may originate from.
+	// CryptomusWebhookIPs are used if empty.
+	Allowed []string
+	// TrustedProxies lists the addresses or CIDR ranges of reverse proxies in front of the service.
+	// The client address is taken from the X-Forwarded-For and X-Real-IP headers only
+	// when the request comes from a trusted proxy.
+	TrustedProxies []string
+}
+
+// IPAllowlist is a middleware rejecting webhook requests that don't originate from Cryptomus,
+// adding a second verification layer beyond the signature.
+type IPAllowlist struct {
+	allowed        []netip.Prefix
+	trustedProxies []netip.Prefix
+}
+
+// NewIPAllowlist creates a new IP allowlist. Options may be nil.
+func NewIPAllowlist(opts *IPAllowlistOptions) (*IPAllowlist, error) {
+	if opts == nil {
+		opts = &IPAllowlistOptions{}
+	}
+
+	allowed := opts.Allowed
+	if len(allowed) == 0 {
+		allowed = CryptomusWebhookIPs
+	}
+
+	a := &IPAllowlist{}
+	var err error
+	if a.allowed, err = parsePrefixes(allowed); err != nil {
+		return nil, fmt.Errorf("invalid allowed address: %w", err)
+	}
+	if a.trustedProxies, err = parsePrefixes(opts.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxy address: %w", err)
+	}
+
+	return a, nil
+}
+
+// Wrap returns a handler responding with 403 to requests from addresses that are not allowed.
+func (a *IPAllowlist) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.Allowed(r) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Allowed reports whether the request originates from an allowed address.
+func (a *IPAllowlist) Allowed(r *http.Request) bool {
+	addr, ok := a.clientAddr(r)
+	if !ok {
+		return false
+	}
+
+	return containsAddr(a.allowed, addr)
+}
+
+// clientAddr returns the address of the client, resolving forwarding headers set by trusted proxies.
+func (a *IPAllowlist) clientAddr(r *http.Request) (netip.Addr, bool) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	addr = addr.Unmap()
+
+	if !containsAddr(a.trustedProxies, addr) {
+		return addr, true
+	}
+
+	// Walk X-Forwarded-For from the right, skipping trusted proxies,
+	// as the leftmost entries can be forged by the client. Empty entries, e.g. of a missing header, are skipped.
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		entry := strings.TrimSpace(forwarded[i])
+		if entry == "" {
+			continue
+		}
+		hop, err := netip.ParseAddr(entry)
+		if err != nil {
+			return netip.Addr{}, false
+		}
+		hop = hop.Unmap()
+		if !containsAddr(a.trustedProxies, hop) {
+			return hop, true
+		}
+	}
+
+	if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); realIP != "" {
+		hop, err := netip.ParseAddr(realIP)
+		if err != nil {
+			return netip.Addr{}, false
+		}
+		return hop.Unmap(), true
+	}
+
+	return addr, true
+}
+
+// parsePrefixes parses addresses and CIDR ranges into prefixes.
+func parsePrefixes(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if strings.Contains(value, "/") {
+			prefix, err := netip.ParsePrefix(value)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return nil, err
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+
+	return prefixes, nil
+}
+
+// containsAddr reports whether any of the prefixes contains the address.
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
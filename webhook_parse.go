@@ -0,0 +1,141 @@
+package cryptomus
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// WebhookParseMode controls how webhook payloads deviating from the known schema are handled.
+type WebhookParseMode int
+
+const (
+	// ParseLenient ignores missing fields and captures unknown ones into WebhookEvent.Extra,
+	// keeping production services working when Cryptomus extends its payloads.
+	ParseLenient WebhookParseMode = iota
+	// ParseStrict rejects payloads with unknown fields or missing required fields,
+	// surfacing schema changes early during development.
+	ParseStrict
+)
+
+// ParseOption configures ParseWebhook.
+type ParseOption func(*parseConfig)
+
+type parseConfig struct {
+	mode WebhookParseMode
+}
+
+// WithParseMode sets the parsing mode. Payloads are parsed leniently by default.
+func WithParseMode(mode WebhookParseMode) ParseOption {
+	return func(c *parseConfig) {
+		c.mode = mode
+	}
+}
+
+// WithParseOptions configures how the dispatcher parses webhook payloads.
+func WithParseOptions(opts ...ParseOption) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.parseOpts = append(d.parseOpts, opts...)
+	}
+}
+
+// requiredWebhookFields lists the fields a payload of each type must carry in strict mode.
+var requiredWebhookFields = map[WebhookType][]string{
+	WebhookTypePayment:    {"uuid", "order_id", "amount", "currency", "status", "is_final"},
+	WebhookTypePayout:     {"uuid", "order_id", "amount", "currency", "status", "is_final"},
+	WebhookTypeWallet:     {"uuid", "order_id", "amount", "currency", "status", "is_final"},
+	WebhookTypeRecurrence: {"uuid", "order_id", "amount", "currency", "period", "status"},
+}
+
+// decodeWebhookPayload normalizes the raw payload and decodes it into the typed payload according to the mode,
+// returning the fields the typed payload has no place for.
+func decodeWebhookPayload(raw []byte, typ WebhookType, payload interface{}, mode WebhookParseMode) (map[string]json.RawMessage, error) {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+
+	raw, err := normalizeWebhookPayload(raw, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	if mode == ParseStrict {
+		var missing []string
+		for _, name := range requiredWebhookFields[typ] {
+			if value, ok := fields[name]; !ok || bytes.Equal(bytes.TrimSpace(value), []byte("null")) {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("missing required fields: %s", strings.Join(missing, ", "))
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(payload); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	if err := json.Unmarshal(raw, payload); err != nil {
+		return nil, err
+	}
+
+	for _, name := range jsonFieldNames(reflect.TypeOf(payload).Elem()) {
+		delete(fields, name)
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	return fields, nil
+}
+
+// jsonFieldNames returns the JSON names of the fields of the struct type, sorted.
+func jsonFieldNames(t reflect.Type) []string {
+	names := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// normalizeWebhookPayload replaces the empty strings Cryptomus sends for absent values with nulls,
+// so they decode into zero decimals and times instead of failing. The raw payload is returned
+// unchanged if it contains no empty strings.
+func normalizeWebhookPayload(raw []byte, fields map[string]json.RawMessage) ([]byte, error) {
+	normalized := make(map[string]json.RawMessage, len(fields))
+	changed := false
+	for name, value := range fields {
+		if bytes.Equal(value, []byte(`""`)) {
+			value = json.RawMessage("null")
+			changed = true
+		}
+		normalized[name] = value
+	}
+	if !changed {
+		return raw, nil
+	}
+
+	return json.Marshal(normalized)
+}
@@ -0,0 +1,136 @@
+package cryptomus
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Defaults of WebhookServerOptions.
+const (
+	DefaultWebhookPath            = "/"
+	DefaultWebhookHealthPath      = "/healthz"
+	DefaultWebhookShutdownTimeout = 10 * time.Second
+)
+
+// WebhookServerOptions configures ListenAndServeWebhooks.
+type WebhookServerOptions struct {
+	Path       string // Path the callbacks are posted to, DefaultWebhookPath if empty
+	HealthPath string // Path answering 200 to health checks, DefaultWebhookHealthPath if empty
+
+	// TLSConfig enables HTTPS. Certificates are taken from it, or loaded from CertFile and KeyFile.
+	TLSConfig *tls.Config
+	CertFile  string
+	KeyFile   string
+
+	// Middleware wraps the webhook handler, e.g. with IPAllowlist.Wrap.
+	Middleware func(http.Handler) http.Handler
+
+	ReadTimeout     time.Duration // Maximum duration for reading a request, 10 seconds if zero
+	WriteTimeout    time.Duration // Maximum duration for writing a response, 30 seconds if zero
+	ShutdownTimeout time.Duration // Time given to in-flight callbacks on shutdown, DefaultWebhookShutdownTimeout if zero
+
+	// OnListen is called with the address the server listens on, once it accepts connections.
+	OnListen func(addr net.Addr)
+}
+
+// webhookShutdowner is implemented by handlers processing events in the background, like Dispatcher.
+type webhookShutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
+// ListenAndServeWebhooks runs an HTTP server on the address, passing the callbacks to the handler,
+// usually a Dispatcher or a WebhookStream, for services whose only job is to consume Cryptomus callbacks.
+// It serves HTTPS when TLS is configured. When the context is done, the server stops accepting
+// connections, waits for in-flight callbacks and drains an asynchronous dispatcher, then returns nil.
+// Options may be nil.
+func ListenAndServeWebhooks(ctx context.Context, addr string, handler http.Handler, opts *WebhookServerOptions) error {
+	if opts == nil {
+		opts = &WebhookServerOptions{}
+	}
+
+	path := opts.Path
+	if path == "" {
+		path = DefaultWebhookPath
+	}
+	healthPath := opts.HealthPath
+	if healthPath == "" {
+		healthPath = DefaultWebhookHealthPath
+	}
+	readTimeout := opts.ReadTimeout
+	if readTimeout == 0 {
+		readTimeout = 10 * time.Second
+	}
+	writeTimeout := opts.WriteTimeout
+	if writeTimeout == 0 {
+		writeTimeout = 30 * time.Second
+	}
+	shutdownTimeout := opts.ShutdownTimeout
+	if shutdownTimeout == 0 {
+		shutdownTimeout = DefaultWebhookShutdownTimeout
+	}
+
+	webhooks := handler
+	if opts.Middleware != nil {
+		webhooks = opts.Middleware(webhooks)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(healthPath, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	})
+	mux.Handle(path, webhooks)
+
+	server := &http.Server{
+		Handler:           mux,
+		TLSConfig:         opts.TLSConfig,
+		ReadHeaderTimeout: readTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	if opts.OnListen != nil {
+		opts.OnListen(listener.Addr())
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if opts.TLSConfig != nil || opts.CertFile != "" {
+			serveErr <- server.ServeTLS(listener, opts.CertFile, opts.KeyFile)
+			return
+		}
+		serveErr <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("webhook server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	err = server.Shutdown(shutdownCtx)
+	if shutdowner, ok := handler.(webhookShutdowner); ok {
+		err = errors.Join(err, shutdowner.Shutdown(shutdownCtx))
+	}
+	if err != nil {
+		return fmt.Errorf("webhook server shutdown: %w", err)
+	}
+
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("webhook server failed: %w", err)
+	}
+
+	return nil
+}
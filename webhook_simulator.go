@@ -0,0 +1,170 @@
+package cryptomus
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// simulatedPayerAddress is the sender address used in simulated callbacks.
+const simulatedPayerAddress = "TXRbqBnMxgcGYbxHRmYUwUvymVtM5WbCGX"
+
+// WebhookSimulator constructs realistic callback payloads, signs them with the client's
+// API keys and POSTs them to a local URL, enabling end-to-end testing of webhook handlers
+// without the live test-webhook endpoints.
+type WebhookSimulator struct {
+	client     *Cryptomus
+	httpClient *http.Client
+}
+
+// NewWebhookSimulator creates a new simulator signing with the API keys of the client.
+func NewWebhookSimulator(client *Cryptomus) *WebhookSimulator {
+	return &WebhookSimulator{
+		client:     client,
+		httpClient: client.client,
+	}
+}
+
+// Payment builds a payment callback for an invoice paid in full in the currency on the tron network.
+func (s *WebhookSimulator) Payment(orderID string, amount Amount, currency string, status PaymentStatus) *PaymentWebhook {
+	return &PaymentWebhook{
+		Type:             string(WebhookTypePayment),
+		UUID:             newUUID(),
+		OrderID:          orderID,
+		Amount:           amount,
+		PaymentAmount:    amount,
+		PaymentAmountUSD: amount,
+		PayerAmount:      amount,
+		MerchantAmount:   amount,
+		Commission:       Amount{},
+		IsFinal:          status.IsFinal(),
+		Status:           status,
+		From:             simulatedPayerAddress,
+		Network:          "tron",
+		Currency:         currency,
+		PayerCurrency:    currency,
+		TxId:             simulatedTxID(),
+	}
+}
+
+// Payout builds a payout callback for a payout of the amount in the currency on the tron network.
+func (s *WebhookSimulator) Payout(orderID string, amount Amount, currency string, status PayoutStatus) *PayoutWebhook {
+	return &PayoutWebhook{
+		Type:           string(WebhookTypePayout),
+		UUID:           newUUID(),
+		OrderID:        orderID,
+		Amount:         amount,
+		MerchantAmount: amount,
+		Commission:     Amount{},
+		IsFinal:        status.IsFinal(),
+		Status:         status,
+		TxId:           simulatedTxID(),
+		Address:        simulatedPayerAddress,
+		Currency:       currency,
+		Network:        "tron",
+		PayerCurrency:  currency,
+		PayerAmount:    amount,
+	}
+}
+
+// Wallet builds a static wallet callback for a deposit of the amount in the currency on the tron network.
+func (s *WebhookSimulator) Wallet(orderID string, amount Amount, currency string, status PaymentStatus) *WalletWebhook {
+	return &WalletWebhook{
+		Type:              string(WebhookTypeWallet),
+		UUID:              newUUID(),
+		OrderID:           orderID,
+		WalletAddressUUID: newUUID(),
+		From:              simulatedPayerAddress,
+		Amount:            amount,
+		PaymentAmount:     amount,
+		PaymentAmountUSD:  amount,
+		MerchantAmount:    amount,
+		Commission:        Amount{},
+		IsFinal:           status.IsFinal(),
+		Status:            status,
+		Network:           "tron",
+		Currency:          currency,
+		PayerCurrency:     currency,
+		TxId:              simulatedTxID(),
+	}
+}
+
+// Sign encodes the payload and appends the signature, choosing the API key by the payload's type.
+func (s *WebhookSimulator) Sign(payload interface{}) ([]byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	typ, err := detectWebhookType(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return signWebhookPayload(s.client.signer, s.client.webhookKey(typ), body)
+}
+
+// Send signs the payload and POSTs it to the URL, returning an error unless the response status is 2xx.
+func (s *WebhookSimulator) Send(ctx context.Context, url string, payload interface{}) error {
+	body, err := s.Sign(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return fmt.Errorf("webhook rejected with status %s: %s", res.Status, strings.TrimSpace(string(message)))
+	}
+
+	return nil
+}
+
+// signWebhookPayload re-encodes the JSON payload with sorted keys and no sign field,
+// and appends the signature computed over it with the signer, as Cryptomus does for callbacks.
+func signWebhookPayload(signer Signer, apiKey string, body []byte) ([]byte, error) {
+	fields := map[string]interface{}{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&fields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+	delete(fields, "sign")
+
+	unsigned, err := MarshalCanonical(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	sign, err := signer.Sign(apiKey, unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signature: %w", err)
+	}
+
+	return appendSignField(unsigned, sign), nil
+}
+
+// simulatedTxID returns a random transaction hash.
+func simulatedTxID() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,153 @@
+package cryptomus
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultWebhookStreamBuffer is the number of events buffered by a WebhookStream unless configured otherwise.
+const DefaultWebhookStreamBuffer = 64
+
+// ErrWebhookStreamFull is returned when an event cannot be delivered because the stream's buffer is full.
+var ErrWebhookStreamFull = errors.New("webhook stream is full")
+
+// ErrWebhookStreamClosed is returned when an event is received after the stream was closed.
+var ErrWebhookStreamClosed = errors.New("webhook stream is closed")
+
+// WebhookStreamOption configures a WebhookStream.
+type WebhookStreamOption func(*WebhookStream)
+
+// WithStreamBuffer sets the number of events buffered before backpressure applies.
+func WithStreamBuffer(size int) WebhookStreamOption {
+	return func(s *WebhookStream) {
+		if size >= 0 {
+			s.buffer = size
+		}
+	}
+}
+
+// WithStreamBlockTimeout limits how long a callback waits for buffer space before it is rejected.
+// By default a callback waits as long as its request is alive.
+func WithStreamBlockTimeout(timeout time.Duration) WebhookStreamOption {
+	return func(s *WebhookStream) {
+		s.blockTimeout = timeout
+	}
+}
+
+// WithStreamRejectWhenFull makes callbacks fail immediately when the buffer is full,
+// leaving it to Cryptomus to retry them later.
+func WithStreamRejectWhenFull() WebhookStreamOption {
+	return func(s *WebhookStream) {
+		s.rejectWhenFull = true
+	}
+}
+
+// WithStreamDispatcherOptions configures the dispatcher that verifies the streamed events.
+func WithStreamDispatcherOptions(opts ...DispatcherOption) WebhookStreamOption {
+	return func(s *WebhookStream) {
+		s.dispatcherOpts = append(s.dispatcherOpts, opts...)
+	}
+}
+
+// WebhookStream delivers verified webhook events on a channel, for services structured
+// around select loops rather than handler callbacks. It implements http.Handler.
+// A callback is acknowledged once its event is buffered, and rejected when backpressure
+// applies, so Cryptomus retries it later.
+type WebhookStream struct {
+	dispatcher     *Dispatcher
+	dispatcherOpts []DispatcherOption
+	buffer         int
+	blockTimeout   time.Duration
+	rejectWhenFull bool
+
+	mu        sync.RWMutex
+	closed    bool
+	events    chan *WebhookEvent
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewWebhookStream creates a new webhook stream verifying signatures with the keys of the client.
+func NewWebhookStream(client *Cryptomus, opts ...WebhookStreamOption) *WebhookStream {
+	s := &WebhookStream{buffer: DefaultWebhookStreamBuffer}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.events = make(chan *WebhookEvent, s.buffer)
+	s.done = make(chan struct{})
+	s.dispatcher = NewDispatcher(client, s.dispatcherOpts...)
+	s.dispatcher.OnUnknown(s.send)
+
+	return s
+}
+
+// Events returns the channel the verified events are delivered on.
+// The channel is closed by Close.
+func (s *WebhookStream) Events() <-chan *WebhookEvent {
+	return s.events
+}
+
+// Dispatch verifies the raw webhook payload and delivers it on the stream.
+func (s *WebhookStream) Dispatch(ctx context.Context, raw []byte) error {
+	return s.dispatcher.Dispatch(ctx, raw)
+}
+
+// ServeHTTP implements http.Handler.
+func (s *WebhookStream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.dispatcher.ServeHTTP(w, r)
+}
+
+// Close stops accepting events and closes the events channel.
+// Buffered events can still be received after Close.
+func (s *WebhookStream) Close() {
+	// Release the callbacks waiting for buffer space before taking the lock they hold
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.closed {
+		s.closed = true
+		close(s.events)
+	}
+}
+
+// send delivers the event on the channel, applying the configured backpressure.
+func (s *WebhookStream) send(ctx context.Context, event *WebhookEvent) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return ErrWebhookStreamClosed
+	}
+
+	if s.rejectWhenFull {
+		select {
+		case s.events <- event:
+			return nil
+		default:
+			return ErrWebhookStreamFull
+		}
+	}
+
+	if s.blockTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.blockTimeout)
+		defer cancel()
+	}
+
+	select {
+	case s.events <- event:
+		return nil
+	case <-s.done:
+		return ErrWebhookStreamClosed
+	case <-ctx.Done():
+		return ErrWebhookStreamFull
+	}
+}